@@ -9,14 +9,16 @@ import (
 
 	"job-portal-api/internal/database"
 	"job-portal-api/internal/handlers"
+	"job-portal-api/internal/mailer"
 	"job-portal-api/internal/repository"
 	"job-portal-api/internal/services"
+	"job-portal-api/internal/storage"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/rs/zerolog/log"
 )
 
@@ -34,32 +36,6 @@ func startApp() error {
 	cfg := config.GetConfig()
 	log.Info().Msg("Config done")
 
-	log.Info().Msg("main : Started : Initializing authentication support")
-	// privatePEM, err := os.ReadFile(`private.pem`)
-	// if err != nil {
-	// 	return fmt.Errorf("reading auth private key %w", err)
-	// }
-	privatePEM:=[]byte(cfg.AuthConfig.PrivateKey)
-	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
-	if err != nil {
-		return fmt.Errorf("parsing auth private key %w", err)
-	}
-
-	// publicPEM, err := os.ReadFile(`pubkey.pem`)
-	// if err != nil {
-	// 	return fmt.Errorf("reading auth public key %w", err)
-	// }
-    publicPEM:=[]byte(cfg.AuthConfig.PublicKey)
-	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
-	if err != nil {
-		return fmt.Errorf("parsing auth public key %w", err)
-	}
-
-	a, err := auth.NewAuth(privateKey, publicKey)
-	if err != nil {
-		return fmt.Errorf("constructing auth %w", err)
-	}
-
 	// =========================================================================
 	// Starting the Database
 	log.Info().Msg("main : Started : Initializing database support")
@@ -84,6 +60,47 @@ func startApp() error {
 	if err != nil {
 		return fmt.Errorf("redis db is not connected: %w ", err)
 	}
+
+	// =========================================================================
+	// Initializing  Authentication Support
+	log.Info().Msg("main : Started : Initializing authentication support")
+	// privatePEM, err := os.ReadFile(`private.pem`)
+	// if err != nil {
+	// 	return fmt.Errorf("reading auth private key %w", err)
+	// }
+	privatePEM:=[]byte(cfg.AuthConfig.PrivateKey)
+	publicPEM:=[]byte(cfg.AuthConfig.PublicKey)
+	primaryKey, err := auth.ParseSigningKey(cfg.AuthConfig.KeyID, privatePEM, publicPEM)
+	if err != nil {
+		return fmt.Errorf("parsing auth keys %w", err)
+	}
+	keys := []auth.SigningKey{primaryKey}
+
+	// Extra keys let us rotate without logging everybody out
+	if cfg.AuthConfig.KeysDir != "" {
+		extraKeys, err := auth.LoadSigningKeys(cfg.AuthConfig.KeysDir)
+		if err != nil {
+			return fmt.Errorf("loading auth keys %w", err)
+		}
+		keys = append(keys, extraKeys...)
+	}
+	for _, retired := range strings.Split(cfg.AuthConfig.RetiredKeyIDs, ",") {
+		for i := range keys {
+			if keys[i].ID == strings.TrimSpace(retired) {
+				keys[i].Retired = true
+			}
+		}
+	}
+	activeKeyID := cfg.AuthConfig.ActiveKeyID
+	if activeKeyID == "" {
+		activeKeyID = cfg.AuthConfig.KeyID
+	}
+
+	a, err := auth.NewAuth(keys, activeKeyID, redisLayer)
+	if err != nil {
+		return fmt.Errorf("constructing auth %w", err)
+	}
+
 	// =========================================================================
 	//Initialize Conn layer support
 
@@ -92,11 +109,47 @@ func startApp() error {
 		return err
 	}
 
-	ms, err := services.NewService(r, a, redisLayer)
+	// mail delivery, smtp in production and an outbox for local development
+	m, err := mailer.NewMailer(mailer.Config{
+		Driver:       cfg.MailConfig.Driver,
+		From:         cfg.MailConfig.From,
+		SMTPHost:     cfg.MailConfig.SMTPHost,
+		SMTPPort:     cfg.MailConfig.SMTPPort,
+		SMTPUsername: cfg.MailConfig.SMTPUsername,
+		SMTPPassword: cfg.MailConfig.SMTPPassword,
+		OutboxDir:    cfg.MailConfig.OutboxDir,
+	})
+	if err != nil {
+		return fmt.Errorf("constructing mailer %w", err)
+	}
+
+	// uploaded resumes, on disk or in an s3 compatible bucket
+	bs, err := storage.NewBlobStore(storage.Config{
+		Driver:      cfg.StorageConfig.Driver,
+		LocalDir:    cfg.StorageConfig.Dir,
+		S3Endpoint:  cfg.StorageConfig.S3Endpoint,
+		S3Region:    cfg.StorageConfig.S3Region,
+		S3Bucket:    cfg.StorageConfig.S3Bucket,
+		S3AccessKey: cfg.StorageConfig.S3AccessKey,
+		S3SecretKey: cfg.StorageConfig.S3SecretKey,
+	})
+	if err != nil {
+		return fmt.Errorf("constructing blob store %w", err)
+	}
+
+	ms, err := services.NewService(r, a, redisLayer, m,
+		services.WithAppURL(cfg.AppConfig.BaseURL),
+		services.WithBlobStore(bs),
+		services.WithLinkSecret(cfg.StorageConfig.LinkSecret))
 	if err != nil {
 		return err
 	}
 
+	// Published jobs past their expiry date are closed in the background
+	expiryCtx, stopExpiry := context.WithCancel(context.Background())
+	defer stopExpiry()
+	go expireJobs(expiryCtx, ms)
+
 	// =========================================================================
 	// Initialize http service
 	api := http.Server{
@@ -134,3 +187,23 @@ func startApp() error {
 	return nil
 
 }
+
+// jobExpiryInterval is how often jobs past their expiry date are looked for
+const jobExpiryInterval = time.Minute
+
+// expireJobs expires the jobs past their expiry date until ctx is cancelled
+func expireJobs(ctx context.Context, ms services.UserService) {
+	ticker := time.NewTicker(jobExpiryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := ms.ExpireJobs(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("expiring jobs")
+			}
+		}
+	}
+}
@@ -14,12 +14,16 @@ type Config struct {
 	PostgresConfig PostgresConfig
 	AuthConfig     AuthConfig
 	RedisConfig    RedisConfig
+	MailConfig     MailConfig
+	StorageConfig  StorageConfig
 }
 type AppConfig struct {
 	Port         string `env:"APP_PORT,required=true"`
 	ReadTimeout  uint32 `env:"APP_READTIMEOUT,required=true"`
 	WriteTimeout uint32 `env:"APP_WRITETIMEOUT,required=true"`
 	IdleTimeout  uint32 `env:"APP_IDLETIMEOUT,required=true"`
+	// Public address of the api, used in links sent by email
+	BaseURL string `env:"APP_BASE_URL,default=http://localhost:8080"`
 }
 type PostgresConfig struct {
 	Host     string `env:"POSTGRES_HOST,required=true"`
@@ -34,6 +38,14 @@ type PostgresConfig struct {
 type AuthConfig struct {
 	PublicKey  string `env:"PUBLICKEY,required=true"`
 	PrivateKey string `env:"PRIVATEKEY,required=true"`
+	// kid of the PUBLICKEY/PRIVATEKEY pair
+	KeyID string `env:"AUTH_KEY_ID,default=primary"`
+	// optional directory with more keys stored as <kid>.pem / <kid>.pub.pem
+	KeysDir string `env:"AUTH_KEYS_DIR"`
+	// kid used for signing, defaults to AUTH_KEY_ID
+	ActiveKeyID string `env:"AUTH_ACTIVE_KEY_ID"`
+	// comma separated kids that are no longer accepted
+	RetiredKeyIDs string `env:"AUTH_RETIRED_KEY_IDS"`
 }
 type RedisConfig struct {
 	Address  string `env:"ADDR,required=true"`
@@ -41,8 +53,35 @@ type RedisConfig struct {
 	Db       string `env:"DB,required=true"`
 }
 
+type MailConfig struct {
+	// smtp or outbox
+	Driver       string `env:"MAIL_DRIVER,default=outbox"`
+	From         string `env:"MAIL_FROM,default=no-reply@jobportal.local"`
+	SMTPHost     string `env:"SMTP_HOST"`
+	SMTPPort     string `env:"SMTP_PORT,default=587"`
+	SMTPUsername string `env:"SMTP_USERNAME"`
+	SMTPPassword string `env:"SMTP_PASSWORD"`
+	// where the outbox driver writes mails, only logged when empty
+	OutboxDir string `env:"MAIL_OUTBOX_DIR"`
+}
+
+type StorageConfig struct {
+	// local or s3
+	Driver string `env:"STORAGE_DRIVER,default=local"`
+	// where the local driver keeps uploaded files
+	Dir string `env:"STORAGE_DIR,default=uploads"`
+	// any s3 compatible endpoint, e.g. http://minio:9000
+	S3Endpoint  string `env:"S3_ENDPOINT"`
+	S3Region    string `env:"S3_REGION,default=us-east-1"`
+	S3Bucket    string `env:"S3_BUCKET"`
+	S3AccessKey string `env:"S3_ACCESS_KEY"`
+	S3SecretKey string `env:"S3_SECRET_KEY"`
+	// signs resume download links, a random secret is used when empty
+	LinkSecret string `env:"STORAGE_LINK_SECRET"`
+}
+
 func init() {
-	_ = godotenv.Load(".env", ".postgres.env", ".auth.env", ".redis.env",".job.postgres.env") 
+	_ = godotenv.Load(".env", ".postgres.env", ".auth.env", ".redis.env",".job.postgres.env", ".mail.env", ".storage.env") 
 
 	_, err := env.UnmarshalFromEnviron(&cfg)
 	if err != nil {
@@ -1,6 +1,6 @@
 module job-portal-api
 
-go 1.19
+go 1.21
 
 require (
 	github.com/gin-gonic/gin v1.9.1
@@ -1,9 +1,10 @@
 package auth
 
 import (
-	"crypto/rsa"
+	"context"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -12,49 +13,143 @@ type ctxKey int
 
 const Key ctxKey = 1
 
+// Audiences tell the token kinds apart, a token issued for one purpose is
+// never accepted for another
+const (
+	AccessAudience            = "users"
+	EmailVerificationAudience = "email-verification"
+)
+
 // Auth Struct
 type Auth struct {
-	privateKey *rsa.PrivateKey
-	publicKey  *rsa.PublicKey
+	keys     map[string]SigningKey
+	activeID string
+	revoked  RevocationList
+}
+
+// RevocationList reports whether a token id (jti) has been revoked
+type RevocationList interface {
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
 }
+
+// Claims carried in the portal tokens
+type Claims struct {
+	jwt.RegisteredClaims
+	Roles []string `json:"roles,omitempty"`
+}
+
+// HasRole reports whether the claims carry any of the given roles
+func (c Claims) HasRole(roles ...string) bool {
+	for _, r := range roles {
+		for _, have := range c.Roles {
+			if r == have {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 //go:generate mockgen -source=auth.go -destination=auth_mock.go -package=auth
 type Authentication interface {
-	GenerateToken(claims jwt.RegisteredClaims) (string, error)
-	ValidateToken(token string) (jwt.RegisteredClaims, error)
+	GenerateToken(claims Claims) (string, error)
+	ValidateToken(ctx context.Context, token string) (Claims, error)
+	ValidateTokenFor(ctx context.Context, token, audience string) (Claims, error)
+	JWKS() JWKSet
 }
 
-// Creating NewAuth Factory Function
-func NewAuth(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey) (Authentication, error) {
-	if privateKey == nil || publicKey == nil {
-		return nil, errors.New("private/public key cannot be nil")
+// Creating NewAuth Factory Function. Tokens are signed with the key named by
+// activeID and verified with any key that is not retired. revoked can be nil
+// when tokens are never revoked.
+func NewAuth(keys []SigningKey, activeID string, revoked RevocationList) (Authentication, error) {
+	a := &Auth{
+		keys:     make(map[string]SigningKey, len(keys)),
+		activeID: activeID,
+		revoked:  revoked,
+	}
+	for _, k := range keys {
+		if k.ID == "" || k.PublicKey == nil {
+			return nil, errors.New("signing key needs an id and a public key")
+		}
+		if _, ok := a.keys[k.ID]; ok {
+			return nil, fmt.Errorf("duplicate signing key id %q", k.ID)
+		}
+		a.keys[k.ID] = k
+	}
+	active, ok := a.keys[activeID]
+	if !ok {
+		return nil, fmt.Errorf("active signing key %q not found", activeID)
+	}
+	if active.PrivateKey == nil || active.Retired {
+		return nil, fmt.Errorf("active signing key %q needs a private key and cannot be retired", activeID)
 	}
-	return &Auth{
-		privateKey: privateKey,
-		publicKey:  publicKey,
-	}, nil
+	return a, nil
 }
 
 // Generating Tokens
-func (a *Auth) GenerateToken(claims jwt.RegisteredClaims) (string, error) {
+func (a *Auth) GenerateToken(claims Claims) (string, error) {
+	active := a.keys[a.activeID]
 	tkn := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
-	tokenStr, err := tkn.SignedString(a.privateKey)
+	tkn.Header["kid"] = active.ID
+	tokenStr, err := tkn.SignedString(active.PrivateKey)
 	if err != nil {
 		return "", fmt.Errorf("signing token %w", err)
 	}
 	return tokenStr, nil
 }
 
-// Validating the tokens
-func (a *Auth) ValidateToken(token string) (jwt.RegisteredClaims, error) {
-	var c jwt.RegisteredClaims
-	tkn, err := jwt.ParseWithClaims(token, &c, func(token *jwt.Token) (interface{}, error) {
-		return a.publicKey, nil
-	})
+// ValidateToken validates an access token
+func (a *Auth) ValidateToken(ctx context.Context, token string) (Claims, error) {
+	return a.ValidateTokenFor(ctx, token, AccessAudience)
+}
+
+// ValidateTokenFor validates a token issued for the given audience
+func (a *Auth) ValidateTokenFor(ctx context.Context, token, audience string) (Claims, error) {
+	var c Claims
+	tkn, err := jwt.ParseWithClaims(token, &c, a.verificationKey,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithAudience(audience))
 	if err != nil {
-		return jwt.RegisteredClaims{}, fmt.Errorf("parsing token %w", err)
+		return Claims{}, fmt.Errorf("parsing token %w", err)
 	}
 	if !tkn.Valid {
-		return jwt.RegisteredClaims{}, errors.New("invalid token")
+		return Claims{}, errors.New("invalid token")
+	}
+	if a.revoked != nil && c.ID != "" {
+		revoked, err := a.revoked.IsTokenRevoked(ctx, c.ID)
+		if err != nil {
+			return Claims{}, fmt.Errorf("checking token revocation %w", err)
+		}
+		if revoked {
+			return Claims{}, errors.New("token revoked")
+		}
 	}
 	return c, nil
 }
+
+// verificationKey picks the public key named by the kid header. Tokens
+// issued before key ids existed carry no kid and are checked against the
+// active key.
+func (a *Auth) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		kid = a.activeID
+	}
+	k, ok := a.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if k.Retired {
+		return nil, fmt.Errorf("signing key %q is retired", kid)
+	}
+	return k.PublicKey, nil
+}
+
+// UserId parses the user id stored in the subject of the claims
+func (c Claims) UserId() (uint, error) {
+	id, err := strconv.ParseUint(c.Subject, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid subject in claims %w", err)
+	}
+	return uint(id), nil
+}
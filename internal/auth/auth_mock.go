@@ -9,12 +9,50 @@
 package auth
 
 import (
+	context "context"
 	reflect "reflect"
 
-	jwt "github.com/golang-jwt/jwt/v5"
 	gomock "go.uber.org/mock/gomock"
 )
 
+// MockRevocationList is a mock of RevocationList interface.
+type MockRevocationList struct {
+	ctrl     *gomock.Controller
+	recorder *MockRevocationListMockRecorder
+}
+
+// MockRevocationListMockRecorder is the mock recorder for MockRevocationList.
+type MockRevocationListMockRecorder struct {
+	mock *MockRevocationList
+}
+
+// NewMockRevocationList creates a new mock instance.
+func NewMockRevocationList(ctrl *gomock.Controller) *MockRevocationList {
+	mock := &MockRevocationList{ctrl: ctrl}
+	mock.recorder = &MockRevocationListMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRevocationList) EXPECT() *MockRevocationListMockRecorder {
+	return m.recorder
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockRevocationList) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", ctx, jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockRevocationListMockRecorder) IsTokenRevoked(ctx, jti any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockRevocationList)(nil).IsTokenRevoked), ctx, jti)
+}
+
 // MockAuthentication is a mock of Authentication interface.
 type MockAuthentication struct {
 	ctrl     *gomock.Controller
@@ -39,7 +77,7 @@ func (m *MockAuthentication) EXPECT() *MockAuthenticationMockRecorder {
 }
 
 // GenerateToken mocks base method.
-func (m *MockAuthentication) GenerateToken(claims jwt.RegisteredClaims) (string, error) {
+func (m *MockAuthentication) GenerateToken(claims Claims) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GenerateToken", claims)
 	ret0, _ := ret[0].(string)
@@ -53,17 +91,46 @@ func (mr *MockAuthenticationMockRecorder) GenerateToken(claims any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateToken", reflect.TypeOf((*MockAuthentication)(nil).GenerateToken), claims)
 }
 
+// JWKS mocks base method.
+func (m *MockAuthentication) JWKS() JWKSet {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(JWKSet)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockAuthenticationMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockAuthentication)(nil).JWKS))
+}
+
 // ValidateToken mocks base method.
-func (m *MockAuthentication) ValidateToken(token string) (jwt.RegisteredClaims, error) {
+func (m *MockAuthentication) ValidateToken(ctx context.Context, token string) (Claims, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ValidateToken", token)
-	ret0, _ := ret[0].(jwt.RegisteredClaims)
+	ret := m.ctrl.Call(m, "ValidateToken", ctx, token)
+	ret0, _ := ret[0].(Claims)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ValidateToken indicates an expected call of ValidateToken.
-func (mr *MockAuthenticationMockRecorder) ValidateToken(token any) *gomock.Call {
+func (mr *MockAuthenticationMockRecorder) ValidateToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateToken", reflect.TypeOf((*MockAuthentication)(nil).ValidateToken), ctx, token)
+}
+
+// ValidateTokenFor mocks base method.
+func (m *MockAuthentication) ValidateTokenFor(ctx context.Context, token, audience string) (Claims, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ValidateTokenFor", ctx, token, audience)
+	ret0, _ := ret[0].(Claims)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ValidateTokenFor indicates an expected call of ValidateTokenFor.
+func (mr *MockAuthenticationMockRecorder) ValidateTokenFor(ctx, token, audience any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateToken", reflect.TypeOf((*MockAuthentication)(nil).ValidateToken), token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateTokenFor", reflect.TypeOf((*MockAuthentication)(nil).ValidateTokenFor), ctx, token, audience)
 }
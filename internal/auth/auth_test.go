@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestKey(t *testing.T, id string) SigningKey {
+	t.Helper()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key %v", err)
+	}
+	return SigningKey{ID: id, PrivateKey: privateKey, PublicKey: &privateKey.PublicKey}
+}
+
+func testClaims() Claims {
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "1",
+			Audience:  jwt.ClaimStrings{AccessAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		Roles: []string{"candidate"},
+	}
+}
+
+func TestAuth_KeyRotation(t *testing.T) {
+	oldKey := newTestKey(t, "old")
+	newKey := newTestKey(t, "new")
+
+	// Token issued before the rotation
+	before, err := NewAuth([]SigningKey{oldKey}, "old", nil)
+	if err != nil {
+		t.Fatalf("NewAuth() error = %v", err)
+	}
+	oldToken, err := before.GenerateToken(testClaims())
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		keys    []SigningKey
+		active  string
+		token   func(a Authentication) string
+		wantErr bool
+	}{
+		{name: "new key signs and verifies",
+			keys: []SigningKey{oldKey, newKey}, active: "new",
+			token: func(a Authentication) string { tkn, _ := a.GenerateToken(testClaims()); return tkn },
+		},
+		{name: "tokens from the previous key are still accepted",
+			keys: []SigningKey{oldKey, newKey}, active: "new",
+			token: func(a Authentication) string { return oldToken },
+		},
+		{name: "tokens from a retired key are rejected",
+			keys: []SigningKey{{ID: "old", PublicKey: oldKey.PublicKey, Retired: true}, newKey}, active: "new",
+			token:   func(a Authentication) string { return oldToken },
+			wantErr: true,
+		},
+		{name: "tokens from an unknown key are rejected",
+			keys: []SigningKey{newKey}, active: "new",
+			token:   func(a Authentication) string { return oldToken },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := NewAuth(tt.keys, tt.active, nil)
+			if err != nil {
+				t.Fatalf("NewAuth() error = %v", err)
+			}
+			got, err := a.ValidateToken(context.Background(), tt.token(a))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got.Subject != "1" {
+				t.Errorf("ValidateToken() = %v", got)
+			}
+		})
+	}
+}
+
+func TestAuth_JWKS(t *testing.T) {
+	active := newTestKey(t, "b")
+	verifyOnly := newTestKey(t, "a")
+	verifyOnly.PrivateKey = nil
+	retired := newTestKey(t, "c")
+	retired.Retired = true
+
+	a, err := NewAuth([]SigningKey{active, verifyOnly, retired}, "b", nil)
+	if err != nil {
+		t.Fatalf("NewAuth() error = %v", err)
+	}
+	set := a.JWKS()
+	if len(set.Keys) != 2 || set.Keys[0].Kid != "a" || set.Keys[1].Kid != "b" {
+		t.Fatalf("JWKS() = %+v", set)
+	}
+	if set.Keys[1].E != "AQAB" || set.Keys[1].Alg != "RS256" {
+		t.Errorf("JWKS() key = %+v", set.Keys[1])
+	}
+}
+
+func TestNewAuth_activeKey(t *testing.T) {
+	verifyOnly := newTestKey(t, "a")
+	verifyOnly.PrivateKey = nil
+	_, err := NewAuth([]SigningKey{verifyOnly}, "a", nil)
+	if err == nil {
+		t.Errorf("NewAuth() accepted an active key without a private key")
+	}
+	_, err = NewAuth([]SigningKey{newTestKey(t, "a")}, "missing", nil)
+	if err == nil {
+		t.Errorf("NewAuth() accepted an unknown active key")
+	}
+}
+
+func TestAuth_ValidateTokenAudience(t *testing.T) {
+	a, err := NewAuth([]SigningKey{newTestKey(t, "a")}, "a", nil)
+	if err != nil {
+		t.Fatalf("NewAuth() error = %v", err)
+	}
+	verification := testClaims()
+	verification.Audience = jwt.ClaimStrings{EmailVerificationAudience}
+	tkn, err := a.GenerateToken(verification)
+	if err != nil {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	if _, err := a.ValidateToken(context.Background(), tkn); err == nil {
+		t.Errorf("ValidateToken() accepted an email verification token")
+	}
+	if _, err := a.ValidateTokenFor(context.Background(), tkn, EmailVerificationAudience); err != nil {
+		t.Errorf("ValidateTokenFor() error = %v", err)
+	}
+}
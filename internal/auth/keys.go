@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is one RSA key pair known to the portal, identified by its kid.
+// Keys without a private key can only verify tokens.
+type SigningKey struct {
+	ID         string
+	PrivateKey *rsa.PrivateKey
+	PublicKey  *rsa.PublicKey
+	Retired    bool
+}
+
+// JWK is the public part of a signing key in JSON Web Key format
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JWKSet is served on /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS lists the public keys other services may use to verify portal tokens,
+// retired keys are left out
+func (a *Auth) JWKS() JWKSet {
+	set := JWKSet{Keys: []JWK{}}
+	for _, k := range a.keys {
+		if k.Retired {
+			continue
+		}
+		set.Keys = append(set.Keys, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: jwt.SigningMethodRS256.Alg(),
+			Kid: k.ID,
+			N:   base64.RawURLEncoding.EncodeToString(k.PublicKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.PublicKey.E)).Bytes()),
+		})
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+// ParseSigningKey builds a signing key from PEM data, privatePEM can be empty
+// for verify only keys
+func ParseSigningKey(id string, privatePEM, publicPEM []byte) (SigningKey, error) {
+	k := SigningKey{ID: id}
+	if len(privatePEM) != 0 {
+		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+		if err != nil {
+			return SigningKey{}, fmt.Errorf("parsing private key %q %w", id, err)
+		}
+		k.PrivateKey = privateKey
+		k.PublicKey = &privateKey.PublicKey
+	}
+	if len(publicPEM) != 0 {
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+		if err != nil {
+			return SigningKey{}, fmt.Errorf("parsing public key %q %w", id, err)
+		}
+		k.PublicKey = publicKey
+	}
+	if k.PublicKey == nil {
+		return SigningKey{}, fmt.Errorf("no key material for %q", id)
+	}
+	return k, nil
+}
+
+// LoadSigningKeys reads the keys kept in dir. A key is stored as
+// <kid>.pem (private key) and/or <kid>.pub.pem (public key only).
+func LoadSigningKeys(dir string) ([]SigningKey, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading keys dir %w", err)
+	}
+	type pems struct{ private, public []byte }
+	found := map[string]*pems{}
+	var ids []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".pem") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("reading key file %w", err)
+		}
+		id := strings.TrimSuffix(name, ".pem")
+		public := strings.HasSuffix(id, ".pub")
+		id = strings.TrimSuffix(id, ".pub")
+		p, ok := found[id]
+		if !ok {
+			p = &pems{}
+			found[id] = p
+			ids = append(ids, id)
+		}
+		if public {
+			p.public = data
+		} else {
+			p.private = data
+		}
+	}
+	sort.Strings(ids)
+	keys := make([]SigningKey, 0, len(ids))
+	for _, id := range ids {
+		k, err := ParseSigningKey(id, found[id].private, found[id].public)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
@@ -2,6 +2,8 @@ package caching
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -20,8 +22,33 @@ type Redis struct {
 type Cache interface {
 	AddCache(ctx context.Context, jobid uint, jobData models.Job) error
 	GetCache(ctx context.Context, jobid uint) (string, error)
-	AddEmailToCache(ctx context.Context, email string, otp string) error
-	GetEmailFromCache(ctx context.Context, otp string) (string, error)
+	DeleteCache(ctx context.Context, jobid uint) error
+	AddEmailToCache(ctx context.Context, email string, otp string, ttl time.Duration) error
+	GetEmailFromCache(ctx context.Context, email string) (string, error)
+	DeleteOTP(ctx context.Context, email string) error
+	ResetOTP(ctx context.Context, email string) error
+	IncrOTPAttempts(ctx context.Context, email string, ttl time.Duration) (int64, error)
+	GetOTPAttempts(ctx context.Context, email string) (int64, error)
+	StartOTPCooldown(ctx context.Context, email string, cooldown time.Duration) (bool, error)
+	StartVerificationCooldown(ctx context.Context, email string, cooldown time.Duration) (bool, error)
+
+	AddRefreshToken(ctx context.Context, token string, uid uint, ttl time.Duration) error
+	TakeRefreshToken(ctx context.Context, token string) (uint, error)
+	RefreshTokenOwner(ctx context.Context, token string) (uint, error)
+	DeleteRefreshToken(ctx context.Context, token string) error
+	RevokeToken(ctx context.Context, jti string, ttl time.Duration) error
+	IsTokenRevoked(ctx context.Context, jti string) (bool, error)
+
+	SaveScreening(ctx context.Context, sc models.Screening, ttl time.Duration) error
+	GetScreening(ctx context.Context, id string) (models.Screening, error)
+	AddScreeningResults(ctx context.Context, sc models.Screening, results []models.ScreeningResult, ttl time.Duration) error
+	GetScreeningResults(ctx context.Context, id string, start, stop int64) ([]models.ScreeningResult, error)
+
+	RecommendationsGeneration(ctx context.Context) (int64, error)
+	InvalidateRecommendations(ctx context.Context) error
+	SaveRecommendations(ctx context.Context, uid uint, rec models.Recommendations, ttl time.Duration) error
+	GetRecommendations(ctx context.Context, uid uint) (models.Recommendations, error)
+	DeleteRecommendations(ctx context.Context, uid uint) error
 }
 
 func NewRedis(rdb *redis.Client) (Cache, error) {
@@ -49,20 +76,280 @@ func (re *Redis) GetCache(ctx context.Context, jobid uint) (string, error) {
 	return str, err
 }
 
-func (re *Redis) AddEmailToCache(ctx context.Context, email string, otp string) error {
-	err := re.rdb.Set(ctx, email, otp, 5*time.Minute).Err()
-	fmt.Println("[[[[[[[[[[]]]]]]]]]]", err)
+// DeleteCache drops a cached job so the next read goes to the database
+func (re *Redis) DeleteCache(ctx context.Context, jobid uint) error {
+	jobID := strconv.FormatUint(uint64(jobid), 10)
+	return re.rdb.Del(ctx, jobID).Err()
+}
+
+// Password reset codes, attempts and cooldowns are kept per email
+func otpKey(email string) string         { return "otp:" + email }
+func otpAttemptsKey(email string) string { return "otp_attempts:" + email }
+func otpCooldownKey(email string) string { return "otp_cooldown:" + email }
+
+// AddEmailToCache stores a new code. The attempt counter is left alone, so
+// requesting new codes does not buy more guesses.
+func (re *Redis) AddEmailToCache(ctx context.Context, email string, otp string, ttl time.Duration) error {
+	err := re.rdb.Set(ctx, otpKey(email), otp, ttl).Err()
 	if err != nil {
-		log.Err(err).Msg("error while adding==================")
+		log.Err(err).Msg("error while adding otp to redis")
 		return fmt.Errorf("error while adding to redis : otp : %w = ", err)
 	}
 	return nil
 }
 func (re *Redis) GetEmailFromCache(ctx context.Context, email string) (string, error) {
-	str, err := re.rdb.Get(ctx, email).Result()
+	str, err := re.rdb.Get(ctx, otpKey(email)).Result()
 	if err != nil {
-		log.Err(err).Msg("error while getting=======================")
 		return "", err
 	}
 	return str, nil
 }
+
+// DeleteOTP removes the code so it cannot be used any more
+func (re *Redis) DeleteOTP(ctx context.Context, email string) error {
+	return re.rdb.Del(ctx, otpKey(email)).Err()
+}
+
+// ResetOTP removes the code and its attempt counter once the password is reset
+func (re *Redis) ResetOTP(ctx context.Context, email string) error {
+	return re.rdb.Del(ctx, otpKey(email), otpAttemptsKey(email)).Err()
+}
+
+// IncrOTPAttempts counts a wrong code and returns the number of wrong codes so
+// far, the count is dropped ttl after the last wrong code
+func (re *Redis) IncrOTPAttempts(ctx context.Context, email string, ttl time.Duration) (int64, error) {
+	pipe := re.rdb.TxPipeline()
+	n := pipe.Incr(ctx, otpAttemptsKey(email))
+	pipe.Expire(ctx, otpAttemptsKey(email), ttl)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return n.Val(), nil
+}
+
+// GetOTPAttempts returns the number of wrong codes entered for the email
+func (re *Redis) GetOTPAttempts(ctx context.Context, email string) (int64, error) {
+	n, err := re.rdb.Get(ctx, otpAttemptsKey(email)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return n, err
+}
+
+// StartOTPCooldown returns false when a code was already requested for the
+// email within the cooldown
+func (re *Redis) StartOTPCooldown(ctx context.Context, email string, cooldown time.Duration) (bool, error) {
+	return re.rdb.SetNX(ctx, otpCooldownKey(email), 1, cooldown).Result()
+}
+
+// StartVerificationCooldown returns false when a verification mail was
+// already sent to the email within the cooldown
+func (re *Redis) StartVerificationCooldown(ctx context.Context, email string, cooldown time.Duration) (bool, error) {
+	return re.rdb.SetNX(ctx, "verify_cooldown:"+email, 1, cooldown).Result()
+}
+
+// Refresh tokens are stored under a hash of the token so a dump of redis
+// does not hand out usable tokens
+func refreshKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "refresh:" + hex.EncodeToString(sum[:])
+}
+
+func (re *Redis) AddRefreshToken(ctx context.Context, token string, uid uint, ttl time.Duration) error {
+	err := re.rdb.Set(ctx, refreshKey(token), uid, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("error while adding refresh token to redis : %w", err)
+	}
+	return nil
+}
+
+// TakeRefreshToken reads and deletes the refresh token in one step so it can
+// only ever be used once
+func (re *Redis) TakeRefreshToken(ctx context.Context, token string) (uint, error) {
+	str, err := re.rdb.GetDel(ctx, refreshKey(token)).Result()
+	if err != nil {
+		return 0, err
+	}
+	uid, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid refresh token entry : %w", err)
+	}
+	return uint(uid), nil
+}
+
+// RefreshTokenOwner returns the user the refresh token was handed to,
+// redis.Nil when it is unknown or expired
+func (re *Redis) RefreshTokenOwner(ctx context.Context, token string) (uint, error) {
+	str, err := re.rdb.Get(ctx, refreshKey(token)).Result()
+	if err != nil {
+		return 0, err
+	}
+	uid, err := strconv.ParseUint(str, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid refresh token entry : %w", err)
+	}
+	return uint(uid), nil
+}
+
+func (re *Redis) DeleteRefreshToken(ctx context.Context, token string) error {
+	return re.rdb.Del(ctx, refreshKey(token)).Err()
+}
+
+// RevokeToken keeps the token id on the deny list until the token would have
+// expired anyway
+func (re *Redis) RevokeToken(ctx context.Context, jti string, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	err := re.rdb.Set(ctx, "revoked:"+jti, 1, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("error while revoking token in redis : %w", err)
+	}
+	return nil
+}
+
+func (re *Redis) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	n, err := re.rdb.Exists(ctx, "revoked:"+jti).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// A screening is kept under screening:<id> and its results, one entry per
+// application, in the list screening:<id>:results
+func screeningKey(id string) string        { return "screening:" + id }
+func screeningResultsKey(id string) string { return "screening:" + id + ":results" }
+
+func (re *Redis) SaveScreening(ctx context.Context, sc models.Screening, ttl time.Duration) error {
+	val, err := json.Marshal(sc)
+	if err != nil {
+		return err
+	}
+	err = re.rdb.Set(ctx, screeningKey(sc.ID), val, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("error while saving screening to redis : %w", err)
+	}
+	return nil
+}
+
+// GetScreening returns redis.Nil when there is no such screening
+func (re *Redis) GetScreening(ctx context.Context, id string) (models.Screening, error) {
+	val, err := re.rdb.Get(ctx, screeningKey(id)).Bytes()
+	if err != nil {
+		return models.Screening{}, err
+	}
+	var sc models.Screening
+	err = json.Unmarshal(val, &sc)
+	if err != nil {
+		return models.Screening{}, fmt.Errorf("invalid screening entry : %w", err)
+	}
+	return sc, nil
+}
+
+// AddScreeningResults appends results and saves the progress of the screening
+// in one step, so Done always matches the number of stored results
+func (re *Redis) AddScreeningResults(ctx context.Context, sc models.Screening, results []models.ScreeningResult, ttl time.Duration) error {
+	val, err := json.Marshal(sc)
+	if err != nil {
+		return err
+	}
+	entries := make([]any, 0, len(results))
+	for _, r := range results {
+		entry, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+	pipe := re.rdb.TxPipeline()
+	if len(entries) > 0 {
+		pipe.RPush(ctx, screeningResultsKey(sc.ID), entries...)
+		pipe.Expire(ctx, screeningResultsKey(sc.ID), ttl)
+	}
+	pipe.Set(ctx, screeningKey(sc.ID), val, ttl)
+	_, err = pipe.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("error while adding screening results to redis : %w", err)
+	}
+	return nil
+}
+
+// GetScreeningResults returns the results from start to stop, both included
+func (re *Redis) GetScreeningResults(ctx context.Context, id string, start, stop int64) ([]models.ScreeningResult, error) {
+	entries, err := re.rdb.LRange(ctx, screeningResultsKey(id), start, stop).Result()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]models.ScreeningResult, 0, len(entries))
+	for _, entry := range entries {
+		var r models.ScreeningResult
+		err = json.Unmarshal([]byte(entry), &r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid screening result entry : %w", err)
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// Recommendations are kept per user under recommendations:<uid>. Any job
+// change moves recommendations:generation on, which outdates all of them at
+// once without looking for the keys.
+const recommendationsGenerationKey = "recommendations:generation"
+
+func recommendationsKey(uid uint) string {
+	return "recommendations:" + strconv.FormatUint(uint64(uid), 10)
+}
+
+// RecommendationsGeneration returns the current generation, 0 before the
+// first job change
+func (re *Redis) RecommendationsGeneration(ctx context.Context) (int64, error) {
+	gen, err := re.rdb.Get(ctx, recommendationsGenerationKey).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return gen, err
+}
+
+// InvalidateRecommendations outdates the recommendations of every user
+func (re *Redis) InvalidateRecommendations(ctx context.Context) error {
+	err := re.rdb.Incr(ctx, recommendationsGenerationKey).Err()
+	if err != nil {
+		return fmt.Errorf("error while invalidating recommendations in redis : %w", err)
+	}
+	return nil
+}
+
+func (re *Redis) SaveRecommendations(ctx context.Context, uid uint, rec models.Recommendations, ttl time.Duration) error {
+	val, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	err = re.rdb.Set(ctx, recommendationsKey(uid), val, ttl).Err()
+	if err != nil {
+		return fmt.Errorf("error while saving recommendations to redis : %w", err)
+	}
+	return nil
+}
+
+// GetRecommendations returns redis.Nil when nothing is cached for the user
+func (re *Redis) GetRecommendations(ctx context.Context, uid uint) (models.Recommendations, error) {
+	val, err := re.rdb.Get(ctx, recommendationsKey(uid)).Bytes()
+	if err != nil {
+		return models.Recommendations{}, err
+	}
+	var rec models.Recommendations
+	err = json.Unmarshal(val, &rec)
+	if err != nil {
+		return models.Recommendations{}, fmt.Errorf("invalid recommendations entry : %w", err)
+	}
+	return rec, nil
+}
+
+// DeleteRecommendations drops the recommendations of one user, e.g. after
+// the preferences changed
+func (re *Redis) DeleteRecommendations(ctx context.Context, uid uint) error {
+	return re.rdb.Del(ctx, recommendationsKey(uid)).Err()
+}
@@ -12,6 +12,7 @@ import (
 	context "context"
 	models "job-portal-api/internal/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -53,6 +54,104 @@ func (mr *MockCacheMockRecorder) AddCache(ctx, jobid, jobData any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCache", reflect.TypeOf((*MockCache)(nil).AddCache), ctx, jobid, jobData)
 }
 
+// AddEmailToCache mocks base method.
+func (m *MockCache) AddEmailToCache(ctx context.Context, email, otp string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddEmailToCache", ctx, email, otp, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEmailToCache indicates an expected call of AddEmailToCache.
+func (mr *MockCacheMockRecorder) AddEmailToCache(ctx, email, otp, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEmailToCache", reflect.TypeOf((*MockCache)(nil).AddEmailToCache), ctx, email, otp, ttl)
+}
+
+// AddRefreshToken mocks base method.
+func (m *MockCache) AddRefreshToken(ctx context.Context, token string, uid uint, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddRefreshToken", ctx, token, uid, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddRefreshToken indicates an expected call of AddRefreshToken.
+func (mr *MockCacheMockRecorder) AddRefreshToken(ctx, token, uid, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddRefreshToken", reflect.TypeOf((*MockCache)(nil).AddRefreshToken), ctx, token, uid, ttl)
+}
+
+// AddScreeningResults mocks base method.
+func (m *MockCache) AddScreeningResults(ctx context.Context, sc models.Screening, results []models.ScreeningResult, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddScreeningResults", ctx, sc, results, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddScreeningResults indicates an expected call of AddScreeningResults.
+func (mr *MockCacheMockRecorder) AddScreeningResults(ctx, sc, results, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddScreeningResults", reflect.TypeOf((*MockCache)(nil).AddScreeningResults), ctx, sc, results, ttl)
+}
+
+// DeleteCache mocks base method.
+func (m *MockCache) DeleteCache(ctx context.Context, jobid uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCache", ctx, jobid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCache indicates an expected call of DeleteCache.
+func (mr *MockCacheMockRecorder) DeleteCache(ctx, jobid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCache", reflect.TypeOf((*MockCache)(nil).DeleteCache), ctx, jobid)
+}
+
+// DeleteOTP mocks base method.
+func (m *MockCache) DeleteOTP(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOTP", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOTP indicates an expected call of DeleteOTP.
+func (mr *MockCacheMockRecorder) DeleteOTP(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOTP", reflect.TypeOf((*MockCache)(nil).DeleteOTP), ctx, email)
+}
+
+// DeleteRecommendations mocks base method.
+func (m *MockCache) DeleteRecommendations(ctx context.Context, uid uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecommendations", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecommendations indicates an expected call of DeleteRecommendations.
+func (mr *MockCacheMockRecorder) DeleteRecommendations(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecommendations", reflect.TypeOf((*MockCache)(nil).DeleteRecommendations), ctx, uid)
+}
+
+// DeleteRefreshToken mocks base method.
+func (m *MockCache) DeleteRefreshToken(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRefreshToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRefreshToken indicates an expected call of DeleteRefreshToken.
+func (mr *MockCacheMockRecorder) DeleteRefreshToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRefreshToken", reflect.TypeOf((*MockCache)(nil).DeleteRefreshToken), ctx, token)
+}
+
 // GetCache mocks base method.
 func (m *MockCache) GetCache(ctx context.Context, jobid uint) (string, error) {
 	m.ctrl.T.Helper()
@@ -67,3 +166,253 @@ func (mr *MockCacheMockRecorder) GetCache(ctx, jobid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCache", reflect.TypeOf((*MockCache)(nil).GetCache), ctx, jobid)
 }
+
+// GetEmailFromCache mocks base method.
+func (m *MockCache) GetEmailFromCache(ctx context.Context, email string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmailFromCache", ctx, email)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmailFromCache indicates an expected call of GetEmailFromCache.
+func (mr *MockCacheMockRecorder) GetEmailFromCache(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailFromCache", reflect.TypeOf((*MockCache)(nil).GetEmailFromCache), ctx, email)
+}
+
+// GetOTPAttempts mocks base method.
+func (m *MockCache) GetOTPAttempts(ctx context.Context, email string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOTPAttempts", ctx, email)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOTPAttempts indicates an expected call of GetOTPAttempts.
+func (mr *MockCacheMockRecorder) GetOTPAttempts(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOTPAttempts", reflect.TypeOf((*MockCache)(nil).GetOTPAttempts), ctx, email)
+}
+
+// GetRecommendations mocks base method.
+func (m *MockCache) GetRecommendations(ctx context.Context, uid uint) (models.Recommendations, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecommendations", ctx, uid)
+	ret0, _ := ret[0].(models.Recommendations)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecommendations indicates an expected call of GetRecommendations.
+func (mr *MockCacheMockRecorder) GetRecommendations(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommendations", reflect.TypeOf((*MockCache)(nil).GetRecommendations), ctx, uid)
+}
+
+// GetScreening mocks base method.
+func (m *MockCache) GetScreening(ctx context.Context, id string) (models.Screening, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScreening", ctx, id)
+	ret0, _ := ret[0].(models.Screening)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScreening indicates an expected call of GetScreening.
+func (mr *MockCacheMockRecorder) GetScreening(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScreening", reflect.TypeOf((*MockCache)(nil).GetScreening), ctx, id)
+}
+
+// GetScreeningResults mocks base method.
+func (m *MockCache) GetScreeningResults(ctx context.Context, id string, start, stop int64) ([]models.ScreeningResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScreeningResults", ctx, id, start, stop)
+	ret0, _ := ret[0].([]models.ScreeningResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScreeningResults indicates an expected call of GetScreeningResults.
+func (mr *MockCacheMockRecorder) GetScreeningResults(ctx, id, start, stop any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScreeningResults", reflect.TypeOf((*MockCache)(nil).GetScreeningResults), ctx, id, start, stop)
+}
+
+// IncrOTPAttempts mocks base method.
+func (m *MockCache) IncrOTPAttempts(ctx context.Context, email string, ttl time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrOTPAttempts", ctx, email, ttl)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrOTPAttempts indicates an expected call of IncrOTPAttempts.
+func (mr *MockCacheMockRecorder) IncrOTPAttempts(ctx, email, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrOTPAttempts", reflect.TypeOf((*MockCache)(nil).IncrOTPAttempts), ctx, email, ttl)
+}
+
+// InvalidateRecommendations mocks base method.
+func (m *MockCache) InvalidateRecommendations(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateRecommendations", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateRecommendations indicates an expected call of InvalidateRecommendations.
+func (mr *MockCacheMockRecorder) InvalidateRecommendations(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateRecommendations", reflect.TypeOf((*MockCache)(nil).InvalidateRecommendations), ctx)
+}
+
+// IsTokenRevoked mocks base method.
+func (m *MockCache) IsTokenRevoked(ctx context.Context, jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsTokenRevoked", ctx, jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsTokenRevoked indicates an expected call of IsTokenRevoked.
+func (mr *MockCacheMockRecorder) IsTokenRevoked(ctx, jti any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsTokenRevoked", reflect.TypeOf((*MockCache)(nil).IsTokenRevoked), ctx, jti)
+}
+
+// RecommendationsGeneration mocks base method.
+func (m *MockCache) RecommendationsGeneration(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecommendationsGeneration", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecommendationsGeneration indicates an expected call of RecommendationsGeneration.
+func (mr *MockCacheMockRecorder) RecommendationsGeneration(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecommendationsGeneration", reflect.TypeOf((*MockCache)(nil).RecommendationsGeneration), ctx)
+}
+
+// RefreshTokenOwner mocks base method.
+func (m *MockCache) RefreshTokenOwner(ctx context.Context, token string) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshTokenOwner", ctx, token)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshTokenOwner indicates an expected call of RefreshTokenOwner.
+func (mr *MockCacheMockRecorder) RefreshTokenOwner(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokenOwner", reflect.TypeOf((*MockCache)(nil).RefreshTokenOwner), ctx, token)
+}
+
+// ResetOTP mocks base method.
+func (m *MockCache) ResetOTP(ctx context.Context, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetOTP", ctx, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetOTP indicates an expected call of ResetOTP.
+func (mr *MockCacheMockRecorder) ResetOTP(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetOTP", reflect.TypeOf((*MockCache)(nil).ResetOTP), ctx, email)
+}
+
+// RevokeToken mocks base method.
+func (m *MockCache) RevokeToken(ctx context.Context, jti string, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeToken", ctx, jti, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeToken indicates an expected call of RevokeToken.
+func (mr *MockCacheMockRecorder) RevokeToken(ctx, jti, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeToken", reflect.TypeOf((*MockCache)(nil).RevokeToken), ctx, jti, ttl)
+}
+
+// SaveRecommendations mocks base method.
+func (m *MockCache) SaveRecommendations(ctx context.Context, uid uint, rec models.Recommendations, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRecommendations", ctx, uid, rec, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRecommendations indicates an expected call of SaveRecommendations.
+func (mr *MockCacheMockRecorder) SaveRecommendations(ctx, uid, rec, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRecommendations", reflect.TypeOf((*MockCache)(nil).SaveRecommendations), ctx, uid, rec, ttl)
+}
+
+// SaveScreening mocks base method.
+func (m *MockCache) SaveScreening(ctx context.Context, sc models.Screening, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveScreening", ctx, sc, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveScreening indicates an expected call of SaveScreening.
+func (mr *MockCacheMockRecorder) SaveScreening(ctx, sc, ttl any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveScreening", reflect.TypeOf((*MockCache)(nil).SaveScreening), ctx, sc, ttl)
+}
+
+// StartOTPCooldown mocks base method.
+func (m *MockCache) StartOTPCooldown(ctx context.Context, email string, cooldown time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartOTPCooldown", ctx, email, cooldown)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartOTPCooldown indicates an expected call of StartOTPCooldown.
+func (mr *MockCacheMockRecorder) StartOTPCooldown(ctx, email, cooldown any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartOTPCooldown", reflect.TypeOf((*MockCache)(nil).StartOTPCooldown), ctx, email, cooldown)
+}
+
+// StartVerificationCooldown mocks base method.
+func (m *MockCache) StartVerificationCooldown(ctx context.Context, email string, cooldown time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartVerificationCooldown", ctx, email, cooldown)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartVerificationCooldown indicates an expected call of StartVerificationCooldown.
+func (mr *MockCacheMockRecorder) StartVerificationCooldown(ctx, email, cooldown any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartVerificationCooldown", reflect.TypeOf((*MockCache)(nil).StartVerificationCooldown), ctx, email, cooldown)
+}
+
+// TakeRefreshToken mocks base method.
+func (m *MockCache) TakeRefreshToken(ctx context.Context, token string) (uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TakeRefreshToken", ctx, token)
+	ret0, _ := ret[0].(uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TakeRefreshToken indicates an expected call of TakeRefreshToken.
+func (mr *MockCacheMockRecorder) TakeRefreshToken(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TakeRefreshToken", reflect.TypeOf((*MockCache)(nil).TakeRefreshToken), ctx, token)
+}
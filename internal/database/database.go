@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"job-portal-api/config"
 	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -21,19 +22,30 @@ func OpenConnection() (*gorm.DB, error) {
 		return nil, err
 	}
 	//fmt.Println("DB:====", db.Migrator().AutoMigrate(&models.User{}))
+	// Users that signed up before email verification existed keep their access
+	backfillVerified := db.Migrator().HasTable(&models.User{}) && !db.Migrator().HasColumn(&models.User{}, "Verified")
 	err = db.Migrator().AutoMigrate(&models.User{})
 	if err != nil {
 		// If there is an error while migrating, log the error message and stop the program
 		return nil, err
 	}
+	if backfillVerified {
+		err = db.Model(&models.User{}).Where("1 = 1").Update("verified", true).Error
+		if err != nil {
+			return nil, err
+		}
+	}
 	err = db.Migrator().AutoMigrate(&models.Company{})
 	if err != nil {
 		// If there is an error while migrating, log the error message and stop the program
 		return nil, err
 	}
+	// Jobs posted before statuses existed were live, they stay published
+	backfillStatus := db.Migrator().HasTable(&models.Job{}) && !db.Migrator().HasColumn(&models.Job{}, "Status")
 	err = db.Migrator().AutoMigrate(
 		&models.User{},
 		&models.Company{},
+		&models.CompanyMember{},
 		&models.Job{},
 		&models.Location{},
 		&models.Skill{},
@@ -41,10 +53,34 @@ func OpenConnection() (*gorm.DB, error) {
 		&models.Qualification{},
 		&models.Shift{},
 		&models.JobType{},
+		&models.Application{},
+		&models.ApplicationStageChange{},
+		&models.Resume{},
+		&models.CandidateProfile{},
 	)
 	if err != nil {
 		// If there is an error while migrating, log the error message and stop the program
 		return nil, err
 	}
+	if backfillStatus {
+		err = db.Model(&models.Job{}).Unscoped().Where("1 = 1").Update("status", models.JobPublished).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	// Taxonomy names are unique, older databases may hold duplicates
+	err = repository.MigrateTaxonomies(db)
+	if err != nil {
+		return nil, err
+	}
+	// Salaries used to be free-form text
+	err = repository.MigrateSalaries(db)
+	if err != nil {
+		return nil, err
+	}
+	err = repository.MigrateJobSearch(db)
+	if err != nil {
+		return nil, err
+	}
 	return db, nil
 }
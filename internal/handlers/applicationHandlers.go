@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middlewares"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+)
+
+// Applying for a job API, candidates only
+func (h *handler) applyForJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	jid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	// An empty body applies with the candidate profile
+	var na models.NewApplication
+	err = json.NewDecoder(c.Request.Body).Decode(&na)
+	if err != nil && !errors.Is(err, io.EOF) {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide proper data"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(na)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide proper data"})
+		return
+	}
+	a, err := h.s.ApplyForJob(ctx, jid, na, claims)
+	if errors.Is(err, services.ErrJobNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrJobNotOpen) || errors.Is(err, services.ErrAlreadyApplied) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrApplicationDetails) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	c.JSON(http.StatusCreated, a)
+}
+
+// Moving an application to another stage API, members of the company only
+func (h *handler) moveApplication(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	aid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	var m models.MoveApplication
+	err = json.NewDecoder(c.Request.Body).Decode(&m)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide proper data"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(m)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide one of the stages screening, interview, offer, hired or rejected"})
+		return
+	}
+	a, err := h.s.MoveApplication(ctx, uint(aid), m, claims)
+	if abortApplicationError(c, traceid, err) {
+		return
+	}
+	c.JSON(http.StatusOK, a)
+}
+
+// Withdrawing an own application API, candidates only
+func (h *handler) withdrawApplication(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	aid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	a, err := h.s.WithdrawApplication(ctx, uint(aid), claims)
+	if abortApplicationError(c, traceid, err) {
+		return
+	}
+	c.JSON(http.StatusOK, a)
+}
+
+// Listing the applications for a job API, filtered by stage, members of the
+// company only
+func (h *handler) getJobApplications(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	var q models.ApplicationListQuery
+	err := c.ShouldBindQuery(&q)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide the job id in job and valid stages"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(q)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide the job id in job and valid stages"})
+		return
+	}
+	l, err := h.s.ViewJobApplications(ctx, q.JobId, q.Stages, claims)
+	if abortApplicationError(c, traceid, err) {
+		return
+	}
+	c.JSON(http.StatusOK, l)
+}
+
+// Listing the applications of the logged in candidate API
+func (h *handler) getMyApplications(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	l, err := h.s.ViewMyApplications(ctx, claims)
+	if abortApplicationError(c, traceid, err) {
+		return
+	}
+	c.JSON(http.StatusOK, l)
+}
+
+// Listing the stage changes of an application API, for the candidate and the
+// members of the company
+func (h *handler) getApplicationHistory(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	aid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	l, err := h.s.ViewApplicationHistory(ctx, uint(aid), claims)
+	if abortApplicationError(c, traceid, err) {
+		return
+	}
+	c.JSON(http.StatusOK, l)
+}
+
+// abortApplicationError answers the error of an application request and
+// reports whether there was one
+func abortApplicationError(c *gin.Context, traceid string, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrApplicationNotFound), errors.Is(err, services.ErrJobNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotCompanyMember):
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrApplicationTransition):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+	}
+	return true
+}
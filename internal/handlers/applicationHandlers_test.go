@@ -0,0 +1,224 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middlewares"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+)
+
+const validApplicationBody = `{"name":"ravi","job_application":{"noticePeriod":30,"experience":2,"technologyStack":[3]}}`
+
+func Test_handler_applyForJob(t *testing.T) {
+	tests := []struct {
+		name               string
+		param              string
+		body               string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{name: "invalid job id", param: "abc", body: validApplicationBody,
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "notice period missing", param: "5", body: `{"name":"ravi","job_application":{"experience":2}}`,
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"please provide proper data"}`,
+		},
+		{name: "job does not exist", param: "5", body: validApplicationBody,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ApplyForJob(gomock.Any(), uint64(5), gomock.Any(), gomock.Any()).Return(models.Application{}, services.ErrJobNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{name: "job closed", param: "5", body: validApplicationBody,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ApplyForJob(gomock.Any(), uint64(5), gomock.Any(), gomock.Any()).Return(models.Application{}, services.ErrJobNotOpen)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   `{"error":"job is not open for applications"}`,
+		},
+		{name: "applied before", param: "5", body: validApplicationBody,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ApplyForJob(gomock.Any(), uint64(5), gomock.Any(), gomock.Any()).Return(models.Application{}, services.ErrAlreadyApplied)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   `{"error":"you have already applied for this job"}`,
+		},
+		{name: "saving fails", param: "5", body: validApplicationBody,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ApplyForJob(gomock.Any(), uint64(5), gomock.Any(), gomock.Any()).Return(models.Application{}, errors.New("application cannot be saved"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{name: "empty body applies with the profile", param: "5", body: ``,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ApplyForJob(gomock.Any(), uint64(5), models.NewApplication{}, gomock.Any()).Return(models.Application{UserId: 4, JobId: 5}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{name: "no profile to apply with", param: "5", body: `{"name":"ravi"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ApplyForJob(gomock.Any(), uint64(5), models.NewApplication{Name: "ravi"}, gomock.Any()).Return(models.Application{}, services.ErrApplicationDetails)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"job_application is required until you create a profile"}`,
+		},
+		{name: "application created", param: "5", body: validApplicationBody,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ApplyForJob(gomock.Any(), uint64(5), models.NewApplication{
+					Name: "ravi",
+					Jobs: &models.RequestFromUser{NoticePeriod: 30, Experience: 2, Skills: []uint{3}},
+				}, gomock.Any()).Return(models.Application{UserId: 4, JobId: 5, Matched: true}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodPost, "http://tests.com", strings.NewReader(tt.body))
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+			ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+			c.Request = httpRequest.WithContext(ctx)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: tt.param})
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			tt.setup(ms)
+			h := &handler{s: ms}
+			h.applyForJob(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			if tt.expectedResponse != "" {
+				assert.Equal(t, tt.expectedResponse, rr.Body.String())
+			}
+		})
+	}
+}
+
+func Test_handler_applicationPipeline(t *testing.T) {
+	tests := []struct {
+		name               string
+		handle             func(h *handler) gin.HandlerFunc
+		url                string
+		param              string
+		body               string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{name: "move to an unknown stage",
+			handle: func(h *handler) gin.HandlerFunc { return h.moveApplication }, param: "8", body: `{"stage":"withdrawn"}`,
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"please provide one of the stages screening, interview, offer, hired or rejected"}`,
+		},
+		{name: "move skipping a stage",
+			handle: func(h *handler) gin.HandlerFunc { return h.moveApplication }, param: "8", body: `{"stage":"offer"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().MoveApplication(gomock.Any(), uint(8), models.MoveApplication{Stage: models.StageOffer}, gomock.Any()).
+					Return(models.Application{}, services.ErrApplicationTransition)
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   `{"error":"application cannot move to this stage"}`,
+		},
+		{name: "move by a recruiter of another company",
+			handle: func(h *handler) gin.HandlerFunc { return h.moveApplication }, param: "8", body: `{"stage":"interview"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().MoveApplication(gomock.Any(), uint(8), gomock.Any(), gomock.Any()).Return(models.Application{}, services.ErrNotCompanyMember)
+			},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{name: "move with a note",
+			handle: func(h *handler) gin.HandlerFunc { return h.moveApplication }, param: "8", body: `{"stage":"interview","note":"call on monday"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().MoveApplication(gomock.Any(), uint(8), models.MoveApplication{Stage: models.StageInterview, Note: "call on monday"}, gomock.Any()).
+					Return(models.Application{Stage: models.StageInterview}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{name: "list without a job",
+			handle: func(h *handler) gin.HandlerFunc { return h.getJobApplications }, url: "/applications?stage=offer",
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"please provide the job id in job and valid stages"}`,
+		},
+		{name: "list by stage",
+			handle: func(h *handler) gin.HandlerFunc { return h.getJobApplications }, url: "/applications?job=5&stage=interview&stage=offer",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ViewJobApplications(gomock.Any(), uint64(5), []string{models.StageInterview, models.StageOffer}, gomock.Any()).
+					Return([]models.Application{}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `[]`,
+		},
+		{name: "list for a missing job",
+			handle: func(h *handler) gin.HandlerFunc { return h.getJobApplications }, url: "/applications?job=5",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ViewJobApplications(gomock.Any(), uint64(5), nil, gomock.Any()).Return(nil, services.ErrJobNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{name: "own applications",
+			handle: func(h *handler) gin.HandlerFunc { return h.getMyApplications },
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ViewMyApplications(gomock.Any(), gomock.Any()).Return([]models.Application{{JobId: 5, Stage: models.StageScreening}}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{name: "withdraw an application of someone else",
+			handle: func(h *handler) gin.HandlerFunc { return h.withdrawApplication }, param: "8",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().WithdrawApplication(gomock.Any(), uint(8), gomock.Any()).Return(models.Application{}, services.ErrApplicationNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{name: "history",
+			handle: func(h *handler) gin.HandlerFunc { return h.getApplicationHistory }, param: "8",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ViewApplicationHistory(gomock.Any(), uint(8), gomock.Any()).Return([]models.ApplicationStageChange{}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `[]`,
+		},
+		{name: "history with an invalid id",
+			handle: func(h *handler) gin.HandlerFunc { return h.getApplicationHistory }, param: "abc",
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodPost, "http://tests.com"+tt.url, strings.NewReader(tt.body))
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+			ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+			c.Request = httpRequest.WithContext(ctx)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: tt.param})
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			tt.setup(ms)
+			h := &handler{s: ms}
+			tt.handle(h)(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			if tt.expectedResponse != "" {
+				assert.Equal(t, tt.expectedResponse, rr.Body.String())
+			}
+		})
+	}
+}
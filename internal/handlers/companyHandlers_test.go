@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 
+	"job-portal-api/internal/auth"
 	"job-portal-api/internal/middlewares"
 	"job-portal-api/internal/models"
 	"job-portal-api/internal/services"
@@ -46,6 +47,7 @@ func Test_handler_createCom(t *testing.T) {
 				"domain":"software}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				return c, rr, nil
@@ -60,6 +62,7 @@ func Test_handler_createCom(t *testing.T) {
 			httpRequest, _ := http.NewRequest(http.MethodGet, "http://tests.com", strings.NewReader(`{"company_name":"qjjqj","address":"niki@gmail.com","password":"1234"}`))
 			ctx := httpRequest.Context()
 			ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 			httpRequest = httpRequest.WithContext(ctx)
 			c.Request = httpRequest
 			c.Params = append(c.Params, gin.Param{Key: "id", Value: " 1"})
@@ -76,12 +79,13 @@ func Test_handler_createCom(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://tests.com", strings.NewReader(`{"company_name":"tek","address":"bangalore","domain":"software"}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				c.Params = append(c.Params, gin.Param{Key: "id", Value: "1"})
 				mc := gomock.NewController(t)
 				ms := services.NewMockUserService(mc)
-				ms.EXPECT().AddCompanyDetails(gomock.Any(), gomock.Any()).Return(models.Company{}, nil).AnyTimes()
+				ms.EXPECT().AddCompanyDetails(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Company{}, nil).AnyTimes()
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusOK,
@@ -94,11 +98,12 @@ func Test_handler_createCom(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://tests.com", strings.NewReader(`{"company_name":"tek","address":"bangalore","domain":"software"}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
 				ms := services.NewMockUserService(mc)
-				ms.EXPECT().AddCompanyDetails(gomock.Any(), gomock.Any()).Return(models.Company{}, errors.New("error in company creation")).AnyTimes()
+				ms.EXPECT().AddCompanyDetails(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Company{}, errors.New("error in company creation")).AnyTimes()
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusBadRequest,
@@ -270,3 +275,115 @@ func Test_handler_viewCompany(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_deleteCompany(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+	}{
+		{name: "invalid cascade flag", query: "?cascade=maybe",
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "company still has jobs",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().DeleteCompany(gomock.Any(), uint64(3), false, gomock.Any()).Return(services.ErrCompanyHasJobs)
+			},
+			expectedStatusCode: http.StatusConflict,
+		},
+		{name: "not an owner", query: "?cascade=true",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().DeleteCompany(gomock.Any(), uint64(3), true, gomock.Any()).Return(services.ErrNotCompanyMember)
+			},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{name: "company deleted with its jobs", query: "?cascade=true",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().DeleteCompany(gomock.Any(), uint64(3), true, gomock.Any()).Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodDelete, "http://tests.com/companies/3"+tt.query, nil)
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+			ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+			c.Request = httpRequest.WithContext(ctx)
+			c.Params = append(c.Params, gin.Param{Key: "cid", Value: "3"})
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			tt.setup(ms)
+			h := &handler{s: ms}
+			h.deleteCompany(c)
+			assert.Equal(t, tt.expectedStatusCode, c.Writer.Status())
+		})
+	}
+}
+
+func Test_handler_updateCompany(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+	}{
+		{name: "missing fields", body: `{"company_name":"tek"}`,
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "company deleted", body: `{"company_name":"tek","address":"pune","domain":"it"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().UpdateCompany(gomock.Any(), uint64(3), gomock.Any(), gomock.Any()).Return(models.Company{}, services.ErrCompanyNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{name: "company updated", body: `{"company_name":"tek","address":"pune","domain":"it"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().UpdateCompany(gomock.Any(), uint64(3), models.Company{CompanyName: "tek", Address: "pune", Domain: "it"}, gomock.Any()).
+					Return(models.Company{CompanyName: "tek"}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodPut, "http://tests.com", strings.NewReader(tt.body))
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+			ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+			c.Request = httpRequest.WithContext(ctx)
+			c.Params = append(c.Params, gin.Param{Key: "cid", Value: "3"})
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			tt.setup(ms)
+			h := &handler{s: ms}
+			h.updateCompany(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+		})
+	}
+}
+
+func Test_handler_restoreCompany(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	httpRequest, _ := http.NewRequest(http.MethodPost, "http://tests.com", nil)
+	ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+	ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+	c.Request = httpRequest.WithContext(ctx)
+	c.Params = append(c.Params, gin.Param{Key: "cid", Value: "3"})
+	mc := gomock.NewController(t)
+	ms := services.NewMockUserService(mc)
+	ms.EXPECT().RestoreCompany(gomock.Any(), uint64(3), gomock.Any()).Return(models.Company{}, services.ErrCompanyNotFound)
+	h := &handler{s: ms}
+	h.restoreCompany(c)
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"job-portal-api/internal/auth"
 	"job-portal-api/internal/middlewares"
+	"job-portal-api/internal/models"
 	"job-portal-api/internal/services"
 	"net/http"
 	"time"
@@ -26,22 +27,76 @@ func API(a auth.Authentication, s services.UserService) *gin.Engine {
 
 	r.Use(m.LoggerMiddleware(), gin.Recovery())
 
+	// Roles allowed on each protected route
+	anyRole := []string{models.RoleCandidate, models.RoleRecruiter, models.RoleAdmin}
+	hiring := []string{models.RoleRecruiter, models.RoleAdmin}
+
 	//Endpoints call
-	r.GET("/check", m.AuthenticationMiddleware(check))
+	r.GET("/check", m.AuthenticationMiddleware(m.Authorize(check, anyRole...)))
 	//users endpoint
 	r.POST("/signup", h.Registration)
 	r.POST("/login", h.Signin)
+	r.GET("/verify-email", h.VerifyEmail)
+	r.POST("/verify-email/resend", h.ResendVerification)
+	r.POST("/token/refresh", h.RefreshToken)
+	r.GET("/.well-known/jwks.json", h.jwks)
+	r.POST("/logout", m.AuthenticationMiddleware(m.Authorize(h.Logout, anyRole...)))
+	r.PUT("/users/:id/role", m.AuthenticationMiddleware(m.Authorize(h.setUserRole, models.RoleAdmin)))
 	//company endpoint
-	r.POST("/createCompany", m.AuthenticationMiddleware(h.createCom))
-	r.GET("/getallcompanies", m.AuthenticationMiddleware(h.getAllTheCompanies))
-	r.GET("/getacompany/:cid", m.AuthenticationMiddleware(h.viewCompany))
+	r.POST("/createCompany", m.AuthenticationMiddleware(m.Authorize(h.createCom, hiring...)))
+	r.GET("/getallcompanies", m.AuthenticationMiddleware(m.Authorize(h.getAllTheCompanies, anyRole...)))
+	r.GET("/getacompany/:cid", m.AuthenticationMiddleware(m.Authorize(h.viewCompany, anyRole...)))
+	r.PUT("/companies/:cid", m.AuthenticationMiddleware(m.Authorize(h.updateCompany, hiring...)))
+	r.DELETE("/companies/:cid", m.AuthenticationMiddleware(m.Authorize(h.deleteCompany, hiring...)))
+	r.POST("/companies/:cid/restore", m.AuthenticationMiddleware(m.Authorize(h.restoreCompany, hiring...)))
+	r.POST("/companies/:cid/members", m.AuthenticationMiddleware(m.Authorize(h.addCompanyMember, hiring...)))
+	r.GET("/companies/:cid/members", m.AuthenticationMiddleware(m.Authorize(h.viewCompanyMembers, hiring...)))
+	r.DELETE("/companies/:cid/members/:uid", m.AuthenticationMiddleware(m.Authorize(h.removeCompanyMember, hiring...)))
 	//jobs endpoint
-	r.POST("/companies/:cid", m.AuthenticationMiddleware(h.postJob))
-	r.GET("/jobs/:CompanyId", m.AuthenticationMiddleware(h.getJobsFromCompany))
-	r.GET("/jobs", m.AuthenticationMiddleware(h.getAllJobs))
-	r.GET("/jobs/jid", m.AuthenticationMiddleware(h.getOneJob))
+	r.POST("/companies/:cid", m.AuthenticationMiddleware(m.Authorize(h.postJob, hiring...)))
+	r.GET("/jobs/:CompanyId", m.AuthenticationMiddleware(m.Authorize(h.getJobsFromCompany, anyRole...)))
+	r.GET("/jobs", m.AuthenticationMiddleware(m.Authorize(h.getAllJobs, anyRole...)))
+	r.GET("/jobs/search", m.AuthenticationMiddleware(m.Authorize(h.searchJobs, anyRole...)))
+	r.GET("/jobs/jid", m.AuthenticationMiddleware(m.Authorize(h.getOneJob, anyRole...)))
+	r.PUT("/jobs/:id", m.AuthenticationMiddleware(m.Authorize(h.updateJob, hiring...)))
+	r.PATCH("/jobs/:id", m.AuthenticationMiddleware(m.Authorize(h.patchJob, hiring...)))
+	r.DELETE("/jobs/:id", m.AuthenticationMiddleware(m.Authorize(h.deleteJob, hiring...)))
+	r.POST("/jobs/:id/publish", m.AuthenticationMiddleware(m.Authorize(h.publishJob, hiring...)))
+	r.POST("/jobs/:id/pause", m.AuthenticationMiddleware(m.Authorize(h.pauseJob, hiring...)))
+	r.POST("/jobs/:id/close", m.AuthenticationMiddleware(m.Authorize(h.closeJob, hiring...)))
+	r.POST("/jobs/:id/apply", m.AuthenticationMiddleware(m.Authorize(h.applyForJob, models.RoleCandidate)))
+	//applications endpoint
+	r.GET("/applications", m.AuthenticationMiddleware(m.Authorize(h.getJobApplications, hiring...)))
+	r.POST("/applications/:id/stage", m.AuthenticationMiddleware(m.Authorize(h.moveApplication, hiring...)))
+	r.GET("/applications/:id/history", m.AuthenticationMiddleware(m.Authorize(h.getApplicationHistory, anyRole...)))
+	r.GET("/me/applications", m.AuthenticationMiddleware(m.Authorize(h.getMyApplications, models.RoleCandidate)))
+	r.POST("/me/applications/:id/withdraw", m.AuthenticationMiddleware(m.Authorize(h.withdrawApplication, models.RoleCandidate)))
+	r.GET("/applications/:id/resumes", m.AuthenticationMiddleware(m.Authorize(h.getApplicationResumes, anyRole...)))
+	//profile of the logged in candidate, applications fall back to it
+	r.POST("/me/profile", m.AuthenticationMiddleware(m.Authorize(h.createProfile, models.RoleCandidate)))
+	r.GET("/me/profile", m.AuthenticationMiddleware(m.Authorize(h.getProfile, models.RoleCandidate)))
+	r.PUT("/me/profile", m.AuthenticationMiddleware(m.Authorize(h.updateProfile, models.RoleCandidate)))
+	r.DELETE("/me/profile", m.AuthenticationMiddleware(m.Authorize(h.deleteProfile, models.RoleCandidate)))
+	r.GET("/me/recommended-jobs", m.AuthenticationMiddleware(m.Authorize(h.getRecommendedJobs, models.RoleCandidate)))
+	//resume endpoints, downloads are authenticated by the signed link
+	r.POST("/me/resumes", m.AuthenticationMiddleware(m.Authorize(h.uploadResume, models.RoleCandidate)))
+	r.GET("/me/resumes", m.AuthenticationMiddleware(m.Authorize(h.getMyResumes, models.RoleCandidate)))
+	r.DELETE("/me/resumes/:id", m.AuthenticationMiddleware(m.Authorize(h.deleteResume, models.RoleCandidate)))
+	r.GET("/resumes/:id/link", m.AuthenticationMiddleware(m.Authorize(h.getResumeLink, anyRole...)))
+	r.GET("/resumes/:id/download", h.downloadResume)
+
+	//master data endpoints, listing is public and changes are for admins
+	for _, kind := range models.TaxonomyKinds {
+		r.GET("/"+kind.Path, h.listTaxonomy(kind))
+		r.POST("/"+kind.Path, m.AuthenticationMiddleware(m.Authorize(h.addTaxonomy(kind), models.RoleAdmin)))
+		r.PUT("/"+kind.Path+"/:id", m.AuthenticationMiddleware(m.Authorize(h.renameTaxonomy(kind), models.RoleAdmin)))
+		r.DELETE("/"+kind.Path+"/:id", m.AuthenticationMiddleware(m.Authorize(h.deleteTaxonomy(kind), models.RoleAdmin)))
+		r.POST("/"+kind.Path+"/:id/merge", m.AuthenticationMiddleware(m.Authorize(h.mergeTaxonomy(kind), models.RoleAdmin)))
+	}
 
-	r.POST("/process/applications", m.AuthenticationMiddleware(h.processApplications))
+	r.POST("/process/applications", m.AuthenticationMiddleware(m.Authorize(h.processApplications, hiring...)))
+	r.POST("/screenings", m.AuthenticationMiddleware(m.Authorize(h.submitScreening, hiring...)))
+	r.GET("/screenings/:id", m.AuthenticationMiddleware(m.Authorize(h.getScreening, hiring...)))
 	r.POST("/forget",h.ForgotPassword)
 	r.POST("/password",h.SetNewPassword)
 
@@ -49,6 +104,12 @@ func API(a auth.Authentication, s services.UserService) *gin.Engine {
 	return r
 }
 
+// Public keys for verifying portal tokens
+func (h *handler) jwks(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.a.JWKS())
+}
+
 // Checking whether the user is  there or not
 func check(c *gin.Context) {
 	time.Sleep(time.Second * 3)
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_API_roleAccess(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		roles              []string
+		expectedStatusCode int
+	}{
+		{name: "check without role", method: http.MethodGet, path: "/check", roles: nil, expectedStatusCode: http.StatusForbidden},
+		{name: "candidate cannot create company", method: http.MethodPost, path: "/createCompany", roles: []string{models.RoleCandidate}, expectedStatusCode: http.StatusForbidden},
+		{name: "recruiter can create company", method: http.MethodPost, path: "/createCompany", roles: []string{models.RoleRecruiter}, expectedStatusCode: http.StatusOK},
+		{name: "admin can create company", method: http.MethodPost, path: "/createCompany", roles: []string{models.RoleAdmin}, expectedStatusCode: http.StatusOK},
+		{name: "view companies without role", method: http.MethodGet, path: "/getallcompanies", roles: nil, expectedStatusCode: http.StatusForbidden},
+		{name: "candidate can view companies", method: http.MethodGet, path: "/getallcompanies", roles: []string{models.RoleCandidate}, expectedStatusCode: http.StatusOK},
+		{name: "view company without role", method: http.MethodGet, path: "/getacompany/1", roles: nil, expectedStatusCode: http.StatusForbidden},
+		{name: "candidate can view company", method: http.MethodGet, path: "/getacompany/1", roles: []string{models.RoleCandidate}, expectedStatusCode: http.StatusOK},
+		{name: "candidate cannot delete company", method: http.MethodDelete, path: "/companies/1", roles: []string{models.RoleCandidate}, expectedStatusCode: http.StatusForbidden},
+		{name: "candidate cannot restore company", method: http.MethodPost, path: "/companies/1/restore", roles: []string{models.RoleCandidate}, expectedStatusCode: http.StatusForbidden},
+		{name: "candidate cannot post job", method: http.MethodPost, path: "/companies/1", roles: []string{models.RoleCandidate}, expectedStatusCode: http.StatusForbidden},
+		{name: "recruiter can post job", method: http.MethodPost, path: "/companies/1", roles: []string{models.RoleRecruiter}, expectedStatusCode: http.StatusOK},
+		{name: "company jobs without role", method: http.MethodGet, path: "/jobs/1", roles: nil, expectedStatusCode: http.StatusForbidden},
+		{name: "candidate can view company jobs", method: http.MethodGet, path: "/jobs/1", roles: []string{models.RoleCandidate}, expectedStatusCode: http.StatusOK},
+		{name: "all jobs without role", method: http.MethodGet, path: "/jobs", roles: nil, expectedStatusCode: http.StatusForbidden},
+		{name: "candidate can view all jobs", method: http.MethodGet, path: "/jobs", roles: []string{models.RoleCandidate}, expectedStatusCode: http.StatusOK},
+		{name: "one job without role", method: http.MethodGet, path: "/jobs/jid", roles: nil, expectedStatusCode: http.StatusForbidden},
+		{name: "candidate cannot update job", method: http.MethodPut, path: "/jobs/1", roles: []string{models.RoleCandidate}, expectedStatusCode: http.StatusForbidden},
+		{name: "candidate cannot patch job", method: http.MethodPatch, path: "/jobs/1", roles: []string{models.RoleCandidate}, expectedStatusCode: http.StatusForbidden},
+		{name: "candidate cannot delete job", method: http.MethodDelete, path: "/jobs/1", roles: []string{models.RoleCandidate}, expectedStatusCode: http.StatusForbidden},
+		{name: "recruiter can delete job", method: http.MethodDelete, path: "/jobs/1", roles: []string{models.RoleRecruiter}, expectedStatusCode: http.StatusNoContent},
+		{name: "candidate cannot publish job", method: http.MethodPost, path: "/jobs/1/publish", roles: []string{models.RoleCandidate}, expectedStatusCode: http.StatusForbidden},
+		{name: "recruiter can close job", method: http.MethodPost, path: "/jobs/1/close", roles: []string{models.RoleRecruiter}, expectedStatusCode: http.StatusOK},
+		{name: "recruiter cannot apply for job", method: http.MethodPost, path: "/jobs/1/apply", roles: []string{models.RoleRecruiter}, expectedStatusCode: http.StatusForbidden},
+		{name: "candidate can apply for job", method: http.MethodPost, path: "/jobs/1/apply", roles: []string{models.RoleCandidate}, expectedStatusCode: http.StatusCreated},
+		{name: "candidate cannot move application", method: http.MethodPost, path: "/applications/1/stage", roles: []string{models.RoleCandidate}, expectedStatusCode: http.StatusForbidden},
+		{name: "recruiter cannot list own applications", method: http.MethodGet, path: "/me/applications", roles: []string{models.RoleRecruiter}, expectedStatusCode: http.StatusForbidden},
+		{name: "candidate can list own applications", method: http.MethodGet, path: "/me/applications", roles: []string{models.RoleCandidate}, expectedStatusCode: http.StatusOK},
+		{name: "candidate cannot process applications", method: http.MethodPost, path: "/process/applications", roles: []string{models.RoleCandidate}, expectedStatusCode: http.StatusForbidden},
+		{name: "recruiter can process applications", method: http.MethodPost, path: "/process/applications", roles: []string{models.RoleRecruiter}, expectedStatusCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			mc := gomock.NewController(t)
+			ma := auth.NewMockAuthentication(mc)
+			ma.EXPECT().ValidateToken(gomock.Any(), gomock.Any()).Return(auth.Claims{Roles: tt.roles}, nil).AnyTimes()
+			ms := services.NewMockUserService(mc)
+			ms.EXPECT().AddCompanyDetails(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Company{}, nil).AnyTimes()
+			ms.EXPECT().ViewAllCompanies(gomock.Any()).Return([]models.Company{}, nil).AnyTimes()
+			ms.EXPECT().ViewCompanyDetails(gomock.Any(), gomock.Any()).Return(models.Company{}, nil).AnyTimes()
+			ms.EXPECT().AddJobDetails(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Response{}, nil).AnyTimes()
+			ms.EXPECT().ViewJobFromCompany(gomock.Any()).Return([]models.Job{}, nil).AnyTimes()
+			ms.EXPECT().ViewAllJobs(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.JobPage{}, nil).AnyTimes()
+			ms.EXPECT().DeleteJob(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+			ms.EXPECT().CloseJob(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Job{}, nil).AnyTimes()
+			ms.EXPECT().ApplyForJob(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Application{}, nil).AnyTimes()
+			ms.EXPECT().ViewMyApplications(gomock.Any(), gomock.Any()).Return([]models.Application{}, nil).AnyTimes()
+			ms.EXPECT().ProcessJobApplications(gomock.Any(), gomock.Any()).Return([]models.ScreeningResult{}, nil).AnyTimes()
+
+			body := map[string]string{
+				"/createCompany":        `{"company_name":"tek","address":"bangalore","domain":"software"}`,
+				"/companies/1":          validJobBody,
+				"/process/applications": `[]`,
+				"/jobs/1/apply":         validApplicationBody,
+			}[tt.path]
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(body))
+			req.Header.Set("Authorization", "Bearer token")
+			API(ma, ms).ServeHTTP(rr, req)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+		})
+	}
+}
+
+func Test_API_unauthenticated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mc := gomock.NewController(t)
+	ma := auth.NewMockAuthentication(mc)
+	ma.EXPECT().ValidateToken(gomock.Any(), gomock.Any()).Return(auth.Claims{}, errors.New("invalid token")).AnyTimes()
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/createCompany", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	API(ma, nil).ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Equal(t, `{"error":"Unauthorized"}`, rr.Body.String())
+}
@@ -1,18 +1,24 @@
 package handlers
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 
 	"job-portal-api/internal/auth"
 	"job-portal-api/internal/middlewares"
 	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
-	"github.com/golang-jwt/jwt/v5"
 	"github.com/rs/zerolog/log"
 )
 
@@ -26,7 +32,7 @@ func (h *handler) postJob(c *gin.Context) {
 		return
 	}
 
-	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
 	if !ok {
 		log.Error().Str("Trace Id", traceid).Msg("login first")
 		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
@@ -46,8 +52,23 @@ func (h *handler) postJob(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide proper data"})
 		return
 	}
+	validate := validator.New()
+	err = validate.Struct(jobData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide proper data"})
+		return
+	}
 	fmt.Println("=============================")
-	jd, err := h.s.AddJobDetails(ctx, jobData, cid)
+	jd, err := h.s.AddJobDetails(ctx, jobData, cid, claims)
+	if abortInvalidReferences(c, err) {
+		return
+	}
+	if errors.Is(err, services.ErrNotCompanyMember) {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Str("trace id", traceid)
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
@@ -58,6 +79,17 @@ func (h *handler) postJob(c *gin.Context) {
 
 }
 
+// abortInvalidReferences answers 422 with the ids of a job request that do not
+// exist and reports whether it did
+func abortInvalidReferences(c *gin.Context, err error) bool {
+	var invalid *services.InvalidReferencesError
+	if !errors.As(err, &invalid) {
+		return false
+	}
+	c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": invalid.Error(), "invalid": invalid.Fields})
+	return true
+}
+
 // Listing jobs for a company API using company id
 func (h *handler) getJobsFromCompany(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -83,7 +115,8 @@ func (h *handler) getJobsFromCompany(c *gin.Context) {
 
 }
 
-// Getting All the jobs API
+// Getting All the jobs API, one page at a time with the filters and sort
+// order of the query string
 func (h *handler) getAllJobs(c *gin.Context) {
 	ctx := c.Request.Context()
 	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
@@ -93,7 +126,31 @@ func (h *handler) getAllJobs(c *gin.Context) {
 		return
 	}
 
-	s, err := h.s.ViewAllJobs(ctx)
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"msg": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	var q models.JobListQuery
+	err := c.ShouldBindQuery(&q)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "invalid query parameters"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(q)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "invalid query parameters"})
+		return
+	}
+	s, err := h.s.ViewAllJobs(ctx, q, claims)
+	if errors.Is(err, services.ErrNotCompanyMember) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"msg": "only members of the company may list jobs that are not published"})
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceId).Msg("user signup problem")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
@@ -103,6 +160,50 @@ func (h *handler) getAllJobs(c *gin.Context) {
 
 }
 
+// Searching the title and description of jobs API, takes the listing
+// filters too
+func (h *handler) searchJobs(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"msg": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	var q models.JobSearchQuery
+	err := c.ShouldBindQuery(&q)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "invalid query parameters"})
+		return
+	}
+	q.Q = strings.TrimSpace(q.Q)
+	validate := validator.New()
+	err = validate.Struct(q)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "please provide the search text in q and valid filters"})
+		return
+	}
+	page, err := h.s.SearchJobs(ctx, q, claims)
+	if errors.Is(err, services.ErrNotCompanyMember) {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"msg": "only members of the company may list jobs that are not published"})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("searching jobs")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
 // Getting a single job posting API using jobid
 func (h *handler) getOneJob(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -129,6 +230,256 @@ func (h *handler) getOneJob(c *gin.Context) {
 
 }
 
+// Replacing a job posting API, the association lists replace the old ones
+func (h *handler) updateJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	jid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	var jobData models.NewJobRequest
+	err = json.NewDecoder(c.Request.Body).Decode(&jobData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide proper data"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(jobData)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide proper data"})
+		return
+	}
+	job, err := h.s.UpdateJob(ctx, jid, jobData, claims)
+	if abortInvalidReferences(c, err) {
+		return
+	}
+	if errors.Is(err, services.ErrJobExpiryPassed) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrJobNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrNotCompanyMember) {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// Partially updating a job posting API
+func (h *handler) patchJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	jid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	var patch models.JobPatchRequest
+	err = json.NewDecoder(c.Request.Body).Decode(&patch)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide proper data"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(patch)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide proper data"})
+		return
+	}
+	job, err := h.s.PatchJob(ctx, jid, patch, claims)
+	if abortInvalidReferences(c, err) {
+		return
+	}
+	if errors.Is(err, services.ErrInvalidSalaryRange) || errors.Is(err, services.ErrJobExpiryPassed) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrJobNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrNotCompanyMember) {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// Deleting a job posting API, the job is soft deleted
+func (h *handler) deleteJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	jid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	err = h.s.DeleteJob(ctx, jid, claims)
+	if errors.Is(err, services.ErrJobNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrNotCompanyMember) {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Publishing a job API, the body may set a new expiry date
+func (h *handler) publishJob(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	jid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	var publish models.PublishJobRequest
+	err = json.NewDecoder(c.Request.Body).Decode(&publish)
+	// The body is optional
+	if err != nil && !errors.Is(err, io.EOF) {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide proper data"})
+		return
+	}
+	job, err := h.s.PublishJob(ctx, jid, publish.ExpiresAt, claims)
+	if abortJobStatusError(c, traceid, err) {
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// Pausing a published job API
+func (h *handler) pauseJob(c *gin.Context) {
+	h.changeJobStatus(c, h.s.PauseJob)
+}
+
+// Closing a job for good API
+func (h *handler) closeJob(c *gin.Context) {
+	h.changeJobStatus(c, h.s.CloseJob)
+}
+
+// changeJobStatus runs a status change that takes nothing but the job id
+func (h *handler) changeJobStatus(c *gin.Context, change func(ctx context.Context, jid uint64, claims auth.Claims) (models.Job, error)) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	jid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	job, err := change(ctx, jid, claims)
+	if abortJobStatusError(c, traceid, err) {
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// abortJobStatusError answers the error of a status change and reports
+// whether there was one
+func abortJobStatusError(c *gin.Context, traceid string, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrJobNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotCompanyMember):
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrJobTransition):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrJobExpiryPassed):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+	}
+	return true
+}
+
 func (h *handler) processApplications(c *gin.Context) {
 	ctx := c.Request.Context()
 	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
@@ -137,13 +488,18 @@ func (h *handler) processApplications(c *gin.Context) {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
 		return
 	}
-	_, ok = ctx.Value(auth.Key).(jwt.RegisteredClaims)
+	_, ok = ctx.Value(auth.Key).(auth.Claims)
 	if !ok {
 		log.Error().Str("Trace Id", traceId).Msg("login first")
 		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
 		return
 	}
 
+	if c.ContentType() == ndjsonContentType {
+		h.streamApplications(c, traceId)
+		return
+	}
+
 	var appData []models.NewUserApplication
 
 	err := json.NewDecoder(c.Request.Body).Decode(&appData)
@@ -164,7 +520,7 @@ func (h *handler) processApplications(c *gin.Context) {
 		}
 	}
 
-	a, err := h.s.ProcessJobApplications(appData)
+	a, err := h.s.ProcessJobApplications(ctx, appData)
 	if err != nil {
 		log.Error().Err(err).Str("trace id", traceId)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
@@ -174,3 +530,108 @@ func (h *handler) processApplications(c *gin.Context) {
 	c.JSON(http.StatusOK, a)
 
 }
+
+const (
+	ndjsonContentType = "application/x-ndjson"
+	// maxNDJSONLine is the longest line a streamed application may take
+	maxNDJSONLine = 1 << 20
+)
+
+// streamApplications screens an application/x-ndjson body, one application
+// per line, and writes a result line for each application as soon as it is
+// screened. Lines that cannot be read get an error result with their line
+// number.
+func (h *handler) streamApplications(c *gin.Context, traceId string) {
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	// Results are written while the body is still being read
+	rc := http.NewResponseController(c.Writer)
+	err := rc.EnableFullDuplex()
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		log.Error().Err(err).Str("trace id", traceId).Msg("enabling full duplex")
+	}
+
+	in := make(chan models.StreamedApplication)
+	invalid := make(chan models.ScreeningResult)
+	go readApplications(ctx, c.Request.Body, in, invalid)
+	results := h.s.StreamJobApplications(ctx, in)
+
+	c.Header("Content-Type", ndjsonContentType)
+	c.Status(http.StatusOK)
+	enc := json.NewEncoder(c.Writer)
+	failed := false
+	for results != nil || invalid != nil {
+		var r models.ScreeningResult
+		var ok bool
+		select {
+		case r, ok = <-results:
+			if !ok {
+				results = nil
+				continue
+			}
+		case r, ok = <-invalid:
+			if !ok {
+				invalid = nil
+				continue
+			}
+		}
+		if failed {
+			continue
+		}
+		err = enc.Encode(r)
+		if err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			// The client is gone, stop screening and drain what is in flight
+			log.Error().Err(err).Str("trace id", traceId).Msg("writing screening result")
+			failed = true
+			cancel()
+			invalid = nil
+		}
+	}
+}
+
+// readApplications sends every line of body to in, or to invalid when it is
+// not an application, and closes both channels at the end of the body
+func readApplications(ctx context.Context, body io.Reader, in chan<- models.StreamedApplication, invalid chan<- models.ScreeningResult) {
+	defer close(in)
+	defer close(invalid)
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLine)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var a models.NewUserApplication
+		err := json.Unmarshal(text, &a)
+		if err != nil {
+			select {
+			case invalid <- models.ScreeningResult{Line: line, Verdict: models.VerdictError, Error: "line is not a valid application"}:
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+		select {
+		case in <- models.StreamedApplication{Line: line, Application: a}:
+		case <-ctx.Done():
+			return
+		}
+	}
+	err := scanner.Err()
+	if err != nil {
+		msg := "request body cannot be read"
+		if errors.Is(err, bufio.ErrTooLong) {
+			msg = fmt.Sprintf("line is longer than %d bytes", maxNDJSONLine)
+		}
+		select {
+		case invalid <- models.ScreeningResult{Line: line + 1, Verdict: models.VerdictError, Error: msg}:
+		case <-ctx.Done():
+		}
+	}
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 
 	"job-portal-api/internal/auth"
@@ -12,13 +13,17 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/assert/v2"
-	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/mock/gomock"
 )
 
+// validJobBody passes the validation of models.NewJobRequest
+const validJobBody = `{"jobTitle":"sde","salaryMin":10000,"salaryMax":20000,"salaryCurrency":"INR","salaryPeriod":"monthly",` +
+	`"minNp":1,"maxNp":3,"budget":1,"jobDesc":"go","minExp":1,"maxExp":3,"LocationIDs":[1]}`
+
 func Test_handler_postJob(t *testing.T) {
 
 	tests := []struct {
@@ -62,7 +67,7 @@ func Test_handler_postJob(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Params = append(c.Params, gin.Param{Key: "cid", Value: "abc"})
 				c.Request = httpRequest
@@ -93,7 +98,7 @@ func Test_handler_postJob(t *testing.T) {
 				"jobTypeIDs": [1]}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Params = append(c.Params, gin.Param{Key: "cid", Value: "1"})
 				c.Request = httpRequest
@@ -110,7 +115,10 @@ func Test_handler_postJob(t *testing.T) {
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://tests.com", strings.NewReader(`{
 					            "jobTitle": "asdfghj",
-								"sal": "85000",
+								"salaryMin": 85000,
+								"salaryMax": 95000,
+								"salaryCurrency": "INR",
+								"salaryPeriod": "annual",
 				 				"minNp": 3,
 				 				"maxNp": 60,
 								"budget": 85000,
@@ -125,13 +133,13 @@ func Test_handler_postJob(t *testing.T) {
 				 				"jobTypeIDs": [1]}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Params = append(c.Params, gin.Param{Key: "cid", Value: "1"})
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
 				ms := services.NewMockUserService(mc)
-				ms.EXPECT().AddJobDetails(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Response{}, errors.New("error in adding job")).AnyTimes()
+				ms.EXPECT().AddJobDetails(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Response{}, errors.New("error in adding job")).AnyTimes()
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusBadRequest,
@@ -143,7 +151,10 @@ func Test_handler_postJob(t *testing.T) {
 				c, _ := gin.CreateTestContext(rr)
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://tests.com", strings.NewReader(`{
 					            "jobTitle": "asdfghj",
-								"sal": "85000",
+								"salaryMin": 85000,
+								"salaryMax": 95000,
+								"salaryCurrency": "INR",
+								"salaryPeriod": "annual",
 				 				"minNp": 3,
 				 				"maxNp": 60,
 								"budget": 85000.0,
@@ -158,18 +169,95 @@ func Test_handler_postJob(t *testing.T) {
 				 				"jobTypeIDs": [1]}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Params = append(c.Params, gin.Param{Key: "cid", Value: "1"})
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
 				ms := services.NewMockUserService(mc)
-				ms.EXPECT().AddJobDetails(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Response{}, nil).AnyTimes()
+				ms.EXPECT().AddJobDetails(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Response{}, nil).AnyTimes()
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusOK,
 			expectedResponse:   `{"ID":0}`,
 		},
+		{name: "not a member of the company",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://tests.com", strings.NewReader(validJobBody))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Params = append(c.Params, gin.Param{Key: "cid", Value: "1"})
+				c.Request = httpRequest
+				mc := gomock.NewController(t)
+				ms := services.NewMockUserService(mc)
+				ms.EXPECT().AddJobDetails(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Response{}, services.ErrNotCompanyMember).AnyTimes()
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"error":"user is not allowed to act for this company"}`,
+		},
+		{name: "salary range reversed",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				body := strings.Replace(validJobBody, `"salaryMax":20000`, `"salaryMax":5000`, 1)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://tests.com", strings.NewReader(body))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Params = append(c.Params, gin.Param{Key: "cid", Value: "1"})
+				c.Request = httpRequest
+				mc := gomock.NewController(t)
+				ms := services.NewMockUserService(mc)
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"please provide proper data"}`,
+		},
+		{name: "unknown currency",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				body := strings.Replace(validJobBody, `"INR"`, `"RUPEES"`, 1)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://tests.com", strings.NewReader(body))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Params = append(c.Params, gin.Param{Key: "cid", Value: "1"})
+				c.Request = httpRequest
+				mc := gomock.NewController(t)
+				ms := services.NewMockUserService(mc)
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"please provide proper data"}`,
+		},
+		{name: "unknown ids",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://tests.com", strings.NewReader(validJobBody))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Params = append(c.Params, gin.Param{Key: "cid", Value: "1"})
+				c.Request = httpRequest
+				mc := gomock.NewController(t)
+				ms := services.NewMockUserService(mc)
+				ms.EXPECT().AddJobDetails(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Response{},
+					&services.InvalidReferencesError{Fields: map[string][]uint{"SkillIDs": {9}, "companyId": {1}}})
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse:   `{"error":"job refers to items that do not exist","invalid":{"SkillIDs":[9],"companyId":[1]}}`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -297,15 +385,157 @@ func Test_handler_getAllJobs(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
 				ms := services.NewMockUserService(mc)
-				ms.EXPECT().ViewAllJobs(gomock.Any()).Return([]models.Job{}, nil).AnyTimes()
+				ms.EXPECT().ViewAllJobs(gomock.Any(), models.JobListQuery{}, gomock.Any()).Return(models.JobPage{Jobs: []models.Job{}, Page: 1, PageSize: 20}, nil).AnyTimes()
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse:   `[]`,
+			expectedResponse: `{"jobs":[],"total":0,"page":1,"page_size":20,` +
+				`"facets":{"locations":null,"skills":null,"work_modes":null,"shifts":null,"job_types":null,"qualifications":null,"companies":null}}`,
+		},
+		{
+			name: "filters and sort read from the query",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080/jobs?page=2&page_size=5&sort=budget&order=asc&skill=1&skill=3&company=4&min_exp=2&notice_period=30", nil)
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				mc := gomock.NewController(t)
+				ms := services.NewMockUserService(mc)
+				company, minExp, notice := uint64(4), 2.0, 30
+				ms.EXPECT().ViewAllJobs(gomock.Any(), models.JobListQuery{
+					Page: 2, PageSize: 5, Sort: "budget", Order: "asc",
+					SkillIDs: []uint{1, 3}, CompanyId: &company, MinExperience: &minExp, NoticePeriod: &notice,
+				}, gomock.Any()).Return(models.JobPage{Jobs: []models.Job{}, Total: 6, Page: 2, PageSize: 5, Facets: models.JobFacets{
+					Locations:      []models.FacetCount{{ID: 1, Name: "Bangalore", Count: 4}},
+					Skills:         []models.FacetCount{{ID: 1, Name: "Go", Count: 5}, {ID: 2, Name: "Java", Count: 3}},
+					WorkModes:      []models.FacetCount{},
+					Shifts:         []models.FacetCount{},
+					JobTypes:       []models.FacetCount{},
+					Qualifications: []models.FacetCount{},
+					Companies:      []models.FacetCount{{ID: 4, Name: "tek", Count: 6}},
+				}}, nil)
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"jobs":[],"total":6,"page":2,"page_size":5,"facets":{` +
+				`"locations":[{"id":1,"name":"Bangalore","count":4}],"skills":[{"id":1,"name":"Go","count":5},{"id":2,"name":"Java","count":3}],` +
+				`"work_modes":[],"shifts":[],"job_types":[],"qualifications":[],"companies":[{"id":4,"name":"tek","count":6}]}}`,
+		},
+		{
+			name: "unknown sort",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080/jobs?sort=salary", nil)
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"msg":"invalid query parameters"}`,
+		},
+		{
+			name: "salary filter without currency",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080/jobs?min_salary=50000", nil)
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"msg":"invalid query parameters"}`,
+		},
+		{
+			name: "salary filter",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080/jobs?min_salary=50000&currency=USD&salary_period=monthly", nil)
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				mc := gomock.NewController(t)
+				ms := services.NewMockUserService(mc)
+				minSalary := 50000.0
+				ms.EXPECT().ViewAllJobs(gomock.Any(), models.JobListQuery{MinSalary: &minSalary, Currency: "USD", SalaryPeriod: models.PayMonthly}, gomock.Any()).
+					Return(models.JobPage{Jobs: []models.Job{}}, nil)
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse: `{"jobs":[],"total":0,"page":0,"page_size":0,` +
+				`"facets":{"locations":null,"skills":null,"work_modes":null,"shifts":null,"job_types":null,"qualifications":null,"companies":null}}`,
+		},
+		{
+			name: "page size too large",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080/jobs?page_size=500", nil)
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"msg":"invalid query parameters"}`,
+		},
+		{
+			name: "unknown status",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080/jobs?status=archived", nil)
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				return c, rr, nil
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"msg":"invalid query parameters"}`,
+		},
+		{
+			name: "drafts of a company the user is not a member of",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080/jobs?status=draft&status=paused&company=4", nil)
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				mc := gomock.NewController(t)
+				ms := services.NewMockUserService(mc)
+				company := uint64(4)
+				ms.EXPECT().ViewAllJobs(gomock.Any(), models.JobListQuery{Statuses: []string{models.JobDraft, models.JobPaused}, CompanyId: &company}, gomock.Any()).
+					Return(models.JobPage{}, services.ErrNotCompanyMember)
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"msg":"only members of the company may list jobs that are not published"}`,
 		},
 		{
 			name: "failed in viewing all jobs",
@@ -315,11 +545,12 @@ func Test_handler_getAllJobs(t *testing.T) {
 				httpRequest, _ := http.NewRequest(http.MethodGet, "http://test.com:8080", nil)
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "123")
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
 				ms := services.NewMockUserService(mc)
-				ms.EXPECT().ViewAllJobs(gomock.Any()).Return([]models.Job{}, errors.New("error")).AnyTimes()
+				ms.EXPECT().ViewAllJobs(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.JobPage{}, errors.New("error")).AnyTimes()
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusInternalServerError,
@@ -338,6 +569,71 @@ func Test_handler_getAllJobs(t *testing.T) {
 	}
 }
 
+func Test_handler_searchJobs(t *testing.T) {
+	tests := []struct {
+		name               string
+		url                string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{name: "search text missing",
+			url:                "http://test.com:8080/jobs/search?q=%20&skill=1",
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"msg":"please provide the search text in q and valid filters"}`,
+		},
+		{name: "invalid filter",
+			url:                "http://test.com:8080/jobs/search?q=golang&sort=salary",
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"msg":"please provide the search text in q and valid filters"}`,
+		},
+		{name: "search with filters",
+			url: "http://test.com:8080/jobs/search?q=%20golang%20developer&location=2&page=2",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().SearchJobs(gomock.Any(), models.JobSearchQuery{
+					Q:            "golang developer",
+					JobListQuery: models.JobListQuery{Page: 2, LocationIDs: []uint{2}},
+				}, gomock.Any()).Return(models.JobSearchPage{Results: []models.JobSearchHit{{
+					Job:            models.Job{JobTitle: "Golang developer"},
+					Rank:           0.6,
+					TitleHighlight: "<mark>Golang</mark> <mark>developer</mark>",
+				}}, Total: 21, Page: 2, PageSize: 20}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{name: "search failure",
+			url: "http://test.com:8080/jobs/search?q=golang",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().SearchJobs(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.JobSearchPage{}, errors.New("jobs cannot be searched"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+			expectedResponse:   `{"msg":"Internal Server Error"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "123")
+			ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+			c.Request = httpRequest.WithContext(ctx)
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			tt.setup(ms)
+			h := &handler{s: ms}
+			h.searchJobs(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			if tt.expectedResponse != "" {
+				assert.Equal(t, tt.expectedResponse, rr.Body.String())
+			}
+		})
+	}
+}
+
 func Test_handler_getOneJob(t *testing.T) {
 	tests := []struct {
 		name               string
@@ -528,7 +824,7 @@ func Test_handler_processApplications(t *testing.T) {
 					]}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Params = append(c.Params, gin.Param{Key: "cid", Value: "1"})
 				c.Request = httpRequest
@@ -603,7 +899,7 @@ func Test_handler_processApplications(t *testing.T) {
 								]}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "7")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				//c.Params = append(c.Params, gin.Param{Key: "id", Value: " abc"})
@@ -679,7 +975,7 @@ func Test_handler_processApplications(t *testing.T) {
 		// 			]}`))
 		// 		ctx := httpRequest.Context()
 		// 		ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
-		// 		ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+		// 		ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 		// 		httpRequest = httpRequest.WithContext(ctx)
 		// 		c.Request = httpRequest
 		// 		return c, rr, nil
@@ -724,12 +1020,12 @@ func Test_handler_processApplications(t *testing.T) {
 					]`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
 				ms := services.NewMockUserService(mc)
-				ms.EXPECT().ProcessJobApplications(gomock.Any()).Return([]models.NewUserApplication{}, nil).AnyTimes()
+				ms.EXPECT().ProcessJobApplications(gomock.Any(), gomock.Any()).Return([]models.ScreeningResult{}, nil).AnyTimes()
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusOK,
@@ -772,12 +1068,12 @@ func Test_handler_processApplications(t *testing.T) {
 					]`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
-				ctx = context.WithValue(ctx, auth.Key, jwt.RegisteredClaims{})
+				ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
 				httpRequest = httpRequest.WithContext(ctx)
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
 				ms := services.NewMockUserService(mc)
-				ms.EXPECT().ProcessJobApplications(gomock.Any()).Return([]models.NewUserApplication{}, errors.New("error")).AnyTimes()
+				ms.EXPECT().ProcessJobApplications(gomock.Any(), gomock.Any()).Return([]models.ScreeningResult{}, errors.New("error")).AnyTimes()
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusInternalServerError,
@@ -795,3 +1091,203 @@ func Test_handler_processApplications(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_changeJob(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		param              string
+		body               string
+		// status picks the status change a POST runs
+		status             string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+	}{
+		{name: "invalid job id", method: http.MethodPut, param: "abc",
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "put with missing fields", method: http.MethodPut, param: "5", body: `{"jobTitle":"sde"}`,
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "put on a job of another company", method: http.MethodPut, param: "5",
+			body: validJobBody,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().UpdateJob(gomock.Any(), uint64(5), gomock.Any(), gomock.Any()).Return(models.Job{}, services.ErrNotCompanyMember)
+			},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{name: "put replaces the job", method: http.MethodPut, param: "5",
+			body: validJobBody,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().UpdateJob(gomock.Any(), uint64(5), gomock.Any(), gomock.Any()).Return(models.Job{JobTitle: "sde"}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{name: "patch with a reversed salary range", method: http.MethodPatch, param: "5", body: `{"salaryMax":10}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().PatchJob(gomock.Any(), uint64(5), gomock.Any(), gomock.Any()).Return(models.Job{}, services.ErrInvalidSalaryRange)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "patch on a missing job", method: http.MethodPatch, param: "5", body: `{"jobTitle":"sde"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().PatchJob(gomock.Any(), uint64(5), gomock.Any(), gomock.Any()).Return(models.Job{}, services.ErrJobNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{name: "patch changes the job", method: http.MethodPatch, param: "5", body: `{"SkillIDs":[]}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().PatchJob(gomock.Any(), uint64(5), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, jid uint64, p models.JobPatchRequest, claims auth.Claims) (models.Job, error) {
+						if p.SkillIDs == nil || len(*p.SkillIDs) != 0 || p.JobTitle != nil {
+							t.Errorf("patch = %+v", p)
+						}
+						return models.Job{}, nil
+					})
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{name: "put with an expiry date in the past", method: http.MethodPut, param: "5",
+			body: validJobBody,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().UpdateJob(gomock.Any(), uint64(5), gomock.Any(), gomock.Any()).Return(models.Job{}, services.ErrJobExpiryPassed)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "publish without a body", method: http.MethodPost, param: "5", status: models.JobPublished,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().PublishJob(gomock.Any(), uint64(5), nil, gomock.Any()).Return(models.Job{Status: models.JobPublished}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{name: "publish with an expiry date", method: http.MethodPost, param: "5", status: models.JobPublished,
+			body: `{"expiresAt":"2030-01-02T15:04:05Z"}`,
+			setup: func(ms *services.MockUserService) {
+				expiry := time.Date(2030, 1, 2, 15, 4, 5, 0, time.UTC)
+				ms.EXPECT().PublishJob(gomock.Any(), uint64(5), &expiry, gomock.Any()).Return(models.Job{Status: models.JobPublished}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{name: "publish with a broken body", method: http.MethodPost, param: "5", status: models.JobPublished,
+			body:               `{"expiresAt":`,
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "publish a closed job", method: http.MethodPost, param: "5", status: models.JobPublished,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().PublishJob(gomock.Any(), uint64(5), nil, gomock.Any()).Return(models.Job{}, services.ErrJobTransition)
+			},
+			expectedStatusCode: http.StatusConflict,
+		},
+		{name: "pause a job of another company", method: http.MethodPost, param: "5", status: models.JobPaused,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().PauseJob(gomock.Any(), uint64(5), gomock.Any()).Return(models.Job{}, services.ErrNotCompanyMember)
+			},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{name: "close a job", method: http.MethodPost, param: "5", status: models.JobClosed,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().CloseJob(gomock.Any(), uint64(5), gomock.Any()).Return(models.Job{Status: models.JobClosed}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{name: "close a missing job", method: http.MethodPost, param: "5", status: models.JobClosed,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().CloseJob(gomock.Any(), uint64(5), gomock.Any()).Return(models.Job{}, services.ErrJobNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{name: "delete a job", method: http.MethodDelete, param: "5",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().DeleteJob(gomock.Any(), uint64(5), gomock.Any()).Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+		{name: "delete a missing job", method: http.MethodDelete, param: "5",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().DeleteJob(gomock.Any(), uint64(5), gomock.Any()).Return(services.ErrJobNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(tt.method, "http://tests.com", strings.NewReader(tt.body))
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+			ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+			c.Request = httpRequest.WithContext(ctx)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: tt.param})
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			tt.setup(ms)
+			h := &handler{s: ms}
+			switch tt.method {
+			case http.MethodPut:
+				h.updateJob(c)
+			case http.MethodPatch:
+				h.patchJob(c)
+			case http.MethodDelete:
+				h.deleteJob(c)
+			case http.MethodPost:
+				switch tt.status {
+				case models.JobPublished:
+					h.publishJob(c)
+				case models.JobPaused:
+					h.pauseJob(c)
+				case models.JobClosed:
+					h.closeJob(c)
+				}
+			}
+			assert.Equal(t, tt.expectedStatusCode, c.Writer.Status())
+		})
+	}
+}
+
+func Test_handler_streamApplications(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	body := `{"name":"ravi","jid":5}` + "\n\n" + `{"name":` + "\n" + `{"name":"asha","jid":6}` + "\n"
+	httpRequest, _ := http.NewRequest(http.MethodPost, "http://tests.com/process/applications", strings.NewReader(body))
+	httpRequest.Header.Set("Content-Type", "application/x-ndjson")
+	ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+	ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+	c.Request = httpRequest.WithContext(ctx)
+	mc := gomock.NewController(t)
+	ms := services.NewMockUserService(mc)
+	ms.EXPECT().StreamJobApplications(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, in <-chan models.StreamedApplication) <-chan models.ScreeningResult {
+			out := make(chan models.ScreeningResult)
+			go func() {
+				defer close(out)
+				for a := range in {
+					out <- models.ScreeningResult{Line: a.Line, Application: a.Application, Verdict: models.VerdictMatched}
+				}
+			}()
+			return out
+		})
+	h := &handler{s: ms}
+	h.processApplications(c)
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "application/x-ndjson", rr.Header().Get("Content-Type"))
+	results := map[int]models.ScreeningResult{}
+	for _, line := range strings.Split(strings.TrimSpace(rr.Body.String()), "\n") {
+		var r models.ScreeningResult
+		err := json.Unmarshal([]byte(line), &r)
+		if err != nil {
+			t.Fatalf("invalid result line %q: %v", line, err)
+		}
+		results[r.Line] = r
+	}
+	assert.Equal(t, 3, len(results))
+	assert.Equal(t, "ravi", results[1].Application.Name)
+	assert.Equal(t, models.VerdictError, results[3].Verdict)
+	assert.Equal(t, "line is not a valid application", results[3].Error)
+	assert.Equal(t, uint64(6), results[4].Application.ID)
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middlewares"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+)
+
+// Creating the profile of the logged in candidate API
+func (h *handler) createProfile(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	pr, ok := decodeProfileRequest(c, traceid)
+	if !ok {
+		return
+	}
+	p, err := h.s.CreateProfile(ctx, pr, claims)
+	if abortProfileError(c, traceid, err) {
+		return
+	}
+	c.JSON(http.StatusCreated, p)
+}
+
+// Viewing the profile of the logged in candidate API
+func (h *handler) getProfile(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	p, err := h.s.ViewProfile(ctx, claims)
+	if abortProfileError(c, traceid, err) {
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// Replacing the profile of the logged in candidate API
+func (h *handler) updateProfile(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	pr, ok := decodeProfileRequest(c, traceid)
+	if !ok {
+		return
+	}
+	p, err := h.s.UpdateProfile(ctx, pr, claims)
+	if abortProfileError(c, traceid, err) {
+		return
+	}
+	c.JSON(http.StatusOK, p)
+}
+
+// Deleting the profile of the logged in candidate API
+func (h *handler) deleteProfile(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	err := h.s.DeleteProfile(ctx, claims)
+	if abortProfileError(c, traceid, err) {
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// decodeProfileRequest reads and validates the body of a profile request,
+// answering 400 and returning false when it is not valid
+func decodeProfileRequest(c *gin.Context, traceid string) (models.ProfileRequest, bool) {
+	var pr models.ProfileRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&pr)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide proper data"})
+		return models.ProfileRequest{}, false
+	}
+	validate := validator.New()
+	err = validate.Struct(pr)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide proper data"})
+		return models.ProfileRequest{}, false
+	}
+	return pr, true
+}
+
+// abortProfileError writes the response for a profile error, false when there
+// is no error
+func abortProfileError(c *gin.Context, traceid string, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case abortInvalidReferences(c, err):
+	case errors.Is(err, services.ErrProfileNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrProfileExists):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+	}
+	return true
+}
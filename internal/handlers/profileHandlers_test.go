@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middlewares"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_handler_profile(t *testing.T) {
+	tests := []struct {
+		name               string
+		handle             func(h *handler) gin.HandlerFunc
+		body               string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{name: "create with a negative notice period",
+			handle:             func(h *handler) gin.HandlerFunc { return h.createProfile },
+			body:               `{"noticePeriod":-1,"experience":2}`,
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "create with unknown items",
+			handle: func(h *handler) gin.HandlerFunc { return h.createProfile },
+			body:   `{"noticePeriod":30,"experience":2,"technologyStack":[9]}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().CreateProfile(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(models.CandidateProfile{}, &services.InvalidReferencesError{Subject: "profile", Fields: map[string][]uint{"technologyStack": {9}}})
+			},
+			expectedStatusCode: http.StatusUnprocessableEntity,
+			expectedResponse:   `{"error":"profile refers to items that do not exist","invalid":{"technologyStack":[9]}}`,
+		},
+		{name: "create a second profile",
+			handle: func(h *handler) gin.HandlerFunc { return h.createProfile },
+			body:   `{"noticePeriod":30}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().CreateProfile(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.CandidateProfile{}, services.ErrProfileExists)
+			},
+			expectedStatusCode: http.StatusConflict,
+		},
+		{name: "created",
+			handle: func(h *handler) gin.HandlerFunc { return h.createProfile },
+			body:   `{"headline":"Go developer","noticePeriod":30,"experience":2,"location":[1],"technologyStack":[3]}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().CreateProfile(gomock.Any(), models.ProfileRequest{
+					Headline: "Go developer", NoticePeriod: 30, Experience: 2, LocationIDs: []uint{1}, SkillIDs: []uint{3},
+				}, gomock.Any()).Return(models.CandidateProfile{UserId: 4}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+		{name: "view without a profile",
+			handle: func(h *handler) gin.HandlerFunc { return h.getProfile },
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ViewProfile(gomock.Any(), gomock.Any()).Return(models.CandidateProfile{}, services.ErrProfileNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"error":"profile not found"}`,
+		},
+		{name: "update fails",
+			handle: func(h *handler) gin.HandlerFunc { return h.updateProfile },
+			body:   `{"noticePeriod":30}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().UpdateProfile(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.CandidateProfile{}, errors.New("profile update failed"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{name: "deleted",
+			handle: func(h *handler) gin.HandlerFunc { return h.deleteProfile },
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().DeleteProfile(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			expectedStatusCode: http.StatusNoContent,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodPost, "http://tests.com/me/profile", strings.NewReader(tt.body))
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+			ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+			c.Request = httpRequest.WithContext(ctx)
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			tt.setup(ms)
+			h := &handler{s: ms}
+			tt.handle(h)(c)
+			assert.Equal(t, tt.expectedStatusCode, c.Writer.Status())
+			if tt.expectedResponse != "" {
+				assert.Equal(t, tt.expectedResponse, rr.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middlewares"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+)
+
+// Recommending open jobs to the logged in candidate API, from the preferences
+// in the query string or else from the profile
+func (h *handler) getRecommendedJobs(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceid, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceid missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceid).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	var q models.RecommendationQuery
+	err := c.ShouldBindQuery(&q)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid query parameters"})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(q)
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid query parameters"})
+		return
+	}
+	page, err := h.s.RecommendJobs(ctx, q, claims)
+	if errors.Is(err, services.ErrNoPreferences) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("trace id", traceid).Msg("recommending jobs")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
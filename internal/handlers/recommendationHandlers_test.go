@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middlewares"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_handler_getRecommendedJobs(t *testing.T) {
+	np, exp := 30, 2.5
+	tests := []struct {
+		name               string
+		url                string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{name: "page size too large",
+			url:                "http://tests.com/me/recommended-jobs?page_size=500",
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "skill is not an id",
+			url:                "http://tests.com/me/recommended-jobs?skill=go",
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "no preferences and no profile",
+			url: "http://tests.com/me/recommended-jobs",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().RecommendJobs(gomock.Any(), models.RecommendationQuery{}, gomock.Any()).Return(models.RecommendationPage{}, services.ErrNoPreferences)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"send your preferences or create a profile first"}`,
+		},
+		{name: "ranking fails",
+			url: "http://tests.com/me/recommended-jobs",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().RecommendJobs(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.RecommendationPage{}, errors.New("jobs cannot be listed"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{name: "preferences from the query",
+			url: "http://tests.com/me/recommended-jobs?notice_period=30&experience=2.5&skill=3&skill=7&work_mode=2&page=2",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().RecommendJobs(gomock.Any(), models.RecommendationQuery{
+					Page: 2, NoticePeriod: &np, Experience: &exp, SkillIDs: []uint{3, 7}, WorkModeIDs: []uint{2},
+				}, gomock.Any()).Return(models.RecommendationPage{Jobs: []models.RecommendedJob{}, Page: 2, PageSize: 20}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"jobs":[],"total":0,"page":2,"page_size":20}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+			ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+			c.Request = httpRequest.WithContext(ctx)
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			tt.setup(ms)
+			h := &handler{s: ms}
+			h.getRecommendedJobs(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			if tt.expectedResponse != "" {
+				assert.Equal(t, tt.expectedResponse, rr.Body.String())
+			}
+		})
+	}
+}
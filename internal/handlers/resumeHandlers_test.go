@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middlewares"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+)
+
+// resumeForm builds a multipart body with the given file and form fields
+func resumeForm(t *testing.T, file []byte, fields map[string]string) (string, *bytes.Buffer) {
+	t.Helper()
+	var b bytes.Buffer
+	mw := multipart.NewWriter(&b)
+	for k, v := range fields {
+		mw.WriteField(k, v)
+	}
+	if file != nil {
+		fw, err := mw.CreateFormFile("file", "cv.pdf")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write(file)
+	}
+	mw.Close()
+	return mw.FormDataContentType(), &b
+}
+
+func Test_handler_uploadResume(t *testing.T) {
+	pdf := []byte("%PDF-1.4\n%%EOF\n")
+	tests := []struct {
+		name               string
+		file               []byte
+		fields             map[string]string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{name: "no file",
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "application is not an id", file: pdf, fields: map[string]string{"application": "abc"},
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "body over the limit", file: bytes.Repeat([]byte("a"), models.MaxResumeSize+2<<20),
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+			expectedResponse:   `{"error":"resume must not be larger than 5 MiB"}`,
+		},
+		{name: "not a pdf or docx", file: []byte("plain text"),
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().UploadResume(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Resume{}, services.ErrResumeType)
+			},
+			expectedStatusCode: http.StatusUnsupportedMediaType,
+			expectedResponse:   `{"error":"resume must be a PDF or DOCX file"}`,
+		},
+		{name: "application of someone else", file: pdf, fields: map[string]string{"application": "8"},
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().UploadResume(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Resume{}, services.ErrApplicationNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{name: "uploaded", file: pdf, fields: map[string]string{"application": "8"},
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().UploadResume(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, up models.ResumeUpload, claims auth.Claims) (models.Resume, error) {
+						b, _ := io.ReadAll(up.File)
+						if up.FileName != "cv.pdf" || up.Size != int64(len(pdf)) || !bytes.Equal(b, pdf) || *up.ApplicationId != 8 {
+							t.Errorf("unexpected upload %+v", up)
+						}
+						return models.Resume{UserId: 4, FileName: "cv.pdf", ContentType: models.ResumePDF}, nil
+					})
+			},
+			expectedStatusCode: http.StatusCreated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			contentType, body := resumeForm(t, tt.file, tt.fields)
+			httpRequest, _ := http.NewRequest(http.MethodPost, "http://tests.com/me/resumes", body)
+			httpRequest.Header.Set("Content-Type", contentType)
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+			ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+			c.Request = httpRequest.WithContext(ctx)
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			tt.setup(ms)
+			h := &handler{s: ms}
+			h.uploadResume(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			if tt.expectedResponse != "" {
+				assert.Equal(t, tt.expectedResponse, rr.Body.String())
+			}
+		})
+	}
+}
+
+func Test_handler_downloadResume(t *testing.T) {
+	tests := []struct {
+		name               string
+		url                string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+		expectedHeader     string
+	}{
+		{name: "link without expiry",
+			url:                "http://tests.com/resumes/3/download?sig=abc",
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{name: "bad signature",
+			url: "http://tests.com/resumes/3/download?expires=100&sig=abc",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().OpenResume(gomock.Any(), uint(3), int64(100), "abc").Return(models.Resume{}, nil, services.ErrResumeLink)
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"error":"download link is invalid or has expired"}`,
+		},
+		{name: "deleted resume",
+			url: "http://tests.com/resumes/3/download?expires=100&sig=abc",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().OpenResume(gomock.Any(), uint(3), int64(100), "abc").Return(models.Resume{}, nil, services.ErrResumeNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+		},
+		{name: "store unavailable",
+			url: "http://tests.com/resumes/3/download?expires=100&sig=abc",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().OpenResume(gomock.Any(), uint(3), int64(100), "abc").Return(models.Resume{}, nil, errors.New("s3 down"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{name: "file sent as an attachment",
+			url: "http://tests.com/resumes/3/download?expires=100&sig=abc",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().OpenResume(gomock.Any(), uint(3), int64(100), "abc").
+					Return(models.Resume{FileName: "cv.pdf", ContentType: models.ResumePDF, Size: 8}, io.NopCloser(strings.NewReader("%PDF-1.4")), nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   "%PDF-1.4",
+			expectedHeader:     `attachment; filename="cv.pdf"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+			c.Request = httpRequest.WithContext(ctx)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "3"})
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			tt.setup(ms)
+			h := &handler{s: ms}
+			h.downloadResume(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			if tt.expectedResponse != "" {
+				assert.Equal(t, tt.expectedResponse, rr.Body.String())
+			}
+			if tt.expectedHeader != "" {
+				assert.Equal(t, tt.expectedHeader, rr.Header().Get("Content-Disposition"))
+				assert.Equal(t, models.ResumePDF, rr.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/middlewares"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_handler_screenings(t *testing.T) {
+	tests := []struct {
+		name               string
+		handle             func(h *handler) gin.HandlerFunc
+		url                string
+		body               string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+		expectedLocation   string
+	}{
+		{name: "submit a body that is not a list",
+			handle:             func(h *handler) gin.HandlerFunc { return h.submitScreening },
+			url:                "http://tests.com/screenings",
+			body:               `{"name":"ravi"}`,
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "submit an empty batch",
+			handle: func(h *handler) gin.HandlerFunc { return h.submitScreening },
+			url:    "http://tests.com/screenings",
+			body:   `[]`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().SubmitScreening(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Screening{}, services.ErrScreeningSize)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"a screening takes from 1 to 50000 applications"}`,
+		},
+		{name: "submitted screening",
+			handle: func(h *handler) gin.HandlerFunc { return h.submitScreening },
+			url:    "http://tests.com/screenings",
+			body:   `[{"name":"ravi","jid":5,"job_application":{"noticePeriod":30}}]`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().SubmitScreening(gomock.Any(), []models.NewUserApplication{{Name: "ravi", ID: 5, Jobs: models.RequestFromUser{NoticePeriod: 30}}}, gomock.Any()).
+					Return(models.Screening{ID: "abc", Status: models.ScreeningRunning, Total: 1}, nil)
+			},
+			expectedStatusCode: http.StatusAccepted,
+			expectedLocation:   "/screenings/abc",
+		},
+		{name: "too many screenings running",
+			handle: func(h *handler) gin.HandlerFunc { return h.submitScreening },
+			url:    "http://tests.com/screenings",
+			body:   `[{"name":"ravi","jid":5}]`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().SubmitScreening(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Screening{}, services.ErrScreeningsBusy)
+			},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedResponse:   `{"error":"too many screenings are running, try again later"}`,
+		},
+		{name: "storing the screening fails",
+			handle: func(h *handler) gin.HandlerFunc { return h.submitScreening },
+			url:    "http://tests.com/screenings",
+			body:   `[{"name":"ravi","jid":5}]`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().SubmitScreening(gomock.Any(), gomock.Any(), gomock.Any()).Return(models.Screening{}, errors.New("redis down"))
+			},
+			expectedStatusCode: http.StatusInternalServerError,
+		},
+		{name: "page size too large",
+			handle:             func(h *handler) gin.HandlerFunc { return h.getScreening },
+			url:                "http://tests.com/screenings/abc?page_size=5000",
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "unknown screening",
+			handle: func(h *handler) gin.HandlerFunc { return h.getScreening },
+			url:    "http://tests.com/screenings/abc",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ViewScreening(gomock.Any(), "abc", models.ScreeningQuery{}, gomock.Any()).Return(models.ScreeningPage{}, services.ErrScreeningNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"error":"screening not found"}`,
+		},
+		{name: "page of results",
+			handle: func(h *handler) gin.HandlerFunc { return h.getScreening },
+			url:    "http://tests.com/screenings/abc?page=2&page_size=10",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ViewScreening(gomock.Any(), "abc", models.ScreeningQuery{Page: 2, PageSize: 10}, gomock.Any()).
+					Return(models.ScreeningPage{Screening: models.Screening{ID: "abc"}, Page: 2, PageSize: 10}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodPost, tt.url, strings.NewReader(tt.body))
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+			ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+			c.Request = httpRequest.WithContext(ctx)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: "abc"})
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			tt.setup(ms)
+			h := &handler{s: ms}
+			tt.handle(h)(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			if tt.expectedResponse != "" {
+				assert.Equal(t, tt.expectedResponse, rr.Body.String())
+			}
+			if tt.expectedLocation != "" {
+				assert.Equal(t, tt.expectedLocation, rr.Header().Get("Location"))
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"job-portal-api/internal/middlewares"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/rs/zerolog/log"
+)
+
+// The master-data handlers are built per taxonomy kind, see the routes in API
+
+// Listing the items of a taxonomy API
+func (h *handler) listTaxonomy(kind models.TaxonomyKind) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
+		if !ok {
+			log.Error().Msg("traceId missing from context")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+		items, err := h.s.ListTaxonomy(ctx, kind)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Msg("listing " + kind.Path)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+		c.JSON(http.StatusOK, items)
+	}
+}
+
+// Adding an item to a taxonomy API, admins only
+func (h *handler) addTaxonomy(kind models.TaxonomyKind) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
+		if !ok {
+			log.Error().Msg("traceId missing from context")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+		var ni models.NewTaxonomyItem
+		err := json.NewDecoder(c.Request.Body).Decode(&ni)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Send()
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+			return
+		}
+		validate := validator.New()
+		err = validate.Struct(ni)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Send()
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide name"})
+			return
+		}
+		item, err := h.s.AddTaxonomy(ctx, kind, ni.Name)
+		if errors.Is(err, services.ErrTaxonomyExists) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Msg("adding to " + kind.Path)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+		c.JSON(http.StatusCreated, item)
+	}
+}
+
+// Renaming an item of a taxonomy API, admins only
+func (h *handler) renameTaxonomy(kind models.TaxonomyKind) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
+		if !ok {
+			log.Error().Msg("traceId missing from context")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+			return
+		}
+		var ni models.NewTaxonomyItem
+		err = json.NewDecoder(c.Request.Body).Decode(&ni)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Send()
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+			return
+		}
+		validate := validator.New()
+		err = validate.Struct(ni)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Send()
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide name"})
+			return
+		}
+		item, err := h.s.RenameTaxonomy(ctx, kind, uint(id), ni.Name)
+		if errors.Is(err, services.ErrTaxonomyNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrTaxonomyExists) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Msg("renaming in " + kind.Path)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+		c.JSON(http.StatusOK, item)
+	}
+}
+
+// Deleting an unused item of a taxonomy API, admins only
+func (h *handler) deleteTaxonomy(kind models.TaxonomyKind) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
+		if !ok {
+			log.Error().Msg("traceId missing from context")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+			return
+		}
+		err = h.s.DeleteTaxonomy(ctx, kind, uint(id))
+		if errors.Is(err, services.ErrTaxonomyNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrTaxonomyInUse) {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Msg("deleting from " + kind.Path)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// Merging duplicate items into the item in the url API, admins only
+func (h *handler) mergeTaxonomy(kind models.TaxonomyKind) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
+		if !ok {
+			log.Error().Msg("traceId missing from context")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+		id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+			return
+		}
+		var merge models.MergeTaxonomyItems
+		err = json.NewDecoder(c.Request.Body).Decode(&merge)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Send()
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+			return
+		}
+		validate := validator.New()
+		err = validate.Struct(merge)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Send()
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "please provide the ids to merge"})
+			return
+		}
+		item, err := h.s.MergeTaxonomy(ctx, kind, uint(id), merge.IDs)
+		if errors.Is(err, services.ErrTaxonomyNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidMerge) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Msg("merging in " + kind.Path)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+		c.JSON(http.StatusOK, item)
+	}
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/services"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/assert/v2"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_API_taxonomies(t *testing.T) {
+	tests := []struct {
+		name               string
+		method             string
+		path               string
+		body               string
+		roles              []string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{name: "anyone can list", method: http.MethodGet, path: "/job-types",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ListTaxonomy(gomock.Any(), models.TaxonomyKinds[5]).Return([]models.TaxonomyItem{{ID: 1, Name: "Full time"}}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `[{"id":1,"name":"Full time"}]`,
+		},
+		{name: "recruiter cannot add", method: http.MethodPost, path: "/skills", body: `{"name":"Go"}`, roles: []string{models.RoleRecruiter},
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusForbidden,
+		},
+		{name: "admin adds", method: http.MethodPost, path: "/skills", body: `{"name":"Go"}`, roles: []string{models.RoleAdmin},
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().AddTaxonomy(gomock.Any(), models.TaxonomyKinds[1], "Go").Return(models.TaxonomyItem{ID: 2, Name: "Go"}, nil)
+			},
+			expectedStatusCode: http.StatusCreated,
+			expectedResponse:   `{"id":2,"name":"Go"}`,
+		},
+		{name: "duplicate name", method: http.MethodPost, path: "/skills", body: `{"name":"go"}`, roles: []string{models.RoleAdmin},
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().AddTaxonomy(gomock.Any(), models.TaxonomyKinds[1], "go").Return(models.TaxonomyItem{}, services.ErrTaxonomyExists)
+			},
+			expectedStatusCode: http.StatusConflict,
+		},
+		{name: "missing name", method: http.MethodPost, path: "/shifts", body: `{}`, roles: []string{models.RoleAdmin},
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "rename", method: http.MethodPut, path: "/locations/3", body: `{"name":"Karnataka"}`, roles: []string{models.RoleAdmin},
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().RenameTaxonomy(gomock.Any(), models.TaxonomyKinds[0], uint(3), "Karnataka").Return(models.TaxonomyItem{ID: 3, Name: "Karnataka"}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+		{name: "delete item in use", method: http.MethodDelete, path: "/work-modes/3", roles: []string{models.RoleAdmin},
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().DeleteTaxonomy(gomock.Any(), models.TaxonomyKinds[2], uint(3)).Return(services.ErrTaxonomyInUse)
+			},
+			expectedStatusCode: http.StatusConflict,
+		},
+		{name: "merge duplicates", method: http.MethodPost, path: "/qualifications/1/merge", body: `{"ids":[4,5]}`, roles: []string{models.RoleAdmin},
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().MergeTaxonomy(gomock.Any(), models.TaxonomyKinds[3], uint(1), []uint{4, 5}).Return(models.TaxonomyItem{ID: 1, Name: "B.E"}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"id":1,"name":"B.E"}`,
+		},
+		{name: "merge without ids", method: http.MethodPost, path: "/qualifications/1/merge", body: `{"ids":[]}`, roles: []string{models.RoleAdmin},
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			mc := gomock.NewController(t)
+			ma := auth.NewMockAuthentication(mc)
+			ma.EXPECT().ValidateToken(gomock.Any(), gomock.Any()).Return(auth.Claims{Roles: tt.roles}, nil).AnyTimes()
+			ms := services.NewMockUserService(mc)
+			tt.setup(ms)
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Authorization", "Bearer token")
+			API(ma, ms).ServeHTTP(rr, req)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			if tt.expectedResponse != "" {
+				assert.Equal(t, tt.expectedResponse, rr.Body.String())
+			}
+		})
+	}
+}
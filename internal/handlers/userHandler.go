@@ -2,11 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"job-portal-api/internal/auth"
 	"job-portal-api/internal/middlewares"
 	"job-portal-api/internal/models"
 	"job-portal-api/internal/services"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
@@ -43,6 +45,11 @@ func (h *handler) Registration(c *gin.Context) {
 		return
 	}
 	usr, err := h.s.Signup(ctx, nu)
+	if errors.Is(err, services.ErrEmailTaken) {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceId).Msg("user signup problem")
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
@@ -82,6 +89,11 @@ func (h *handler) Signin(c *gin.Context) {
 
 	// Attempt to authenticate the user with the email and password
 	claims, err := h.s.Login(ctx, login.Email, login.Password)
+	if errors.Is(err, services.ErrEmailNotVerified) {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"msg": err.Error()})
+		return
+	}
 	if err != nil {
 		log.Error().Err(err).Str("Trace Id", traceId).Send()
 		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"msg": "login failed"})
@@ -96,9 +108,201 @@ func (h *handler) Signin(c *gin.Context) {
 		return
 	}
 
+	// Hand out a refresh token so the client can renew the access token
+	rt, err := h.s.CreateRefreshToken(ctx, claims)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("creating refresh token")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+
 	// If everything goes right, respond with the token
-	c.JSON(http.StatusOK, tkn)
+	c.JSON(http.StatusOK, models.Token{Token: tkn, RefreshToken: rt})
+
+}
+
+// VerifyEmail is the target of the link in the verification mail
+func (h *handler) VerifyEmail(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	token := c.Query("token")
+	if token == "" {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "please provide token"})
+		return
+	}
+	err := h.s.VerifyEmail(ctx, token)
+	if errors.Is(err, services.ErrInvalidVerificationToken) {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("verifying email")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"msg": "email verified"})
+}
+
+// Resending the verification mail API
+func (h *handler) ResendVerification(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	var rv models.ResendVerification
+	err := json.NewDecoder(c.Request.Body).Decode(&rv)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("error in decoding")
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(rv)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "please provide email"})
+		return
+	}
+	msg, err := h.s.ResendVerification(ctx, rv.Email)
+	if errors.Is(err, services.ErrVerificationCooldown) {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"msg": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("resending verification")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"msg": msg})
+}
+
+// Refresh token API, exchanges a refresh token for a new token pair
+func (h *handler) RefreshToken(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	var rr models.RefreshRequest
+	err := json.NewDecoder(c.Request.Body).Decode(&rr)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("error in decoding")
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(rr)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": "please provide refresh_token"})
+		return
+	}
+	claims, rt, err := h.s.RefreshToken(ctx, rr.RefreshToken)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"msg": err.Error()})
+		return
+	}
+	tkn, err := h.a.GenerateToken(claims)
+	if err != nil {
+		log.Error().Err(err).Msg("generating token")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	c.JSON(http.StatusOK, models.Token{Token: tkn, RefreshToken: rt})
+}
+
+// Logout API, revokes the current access token and the given refresh token
+func (h *handler) Logout(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	claims, ok := ctx.Value(auth.Key).(auth.Claims)
+	if !ok {
+		log.Error().Str("Trace Id", traceId).Msg("login first")
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"msg": http.StatusText(http.StatusUnauthorized)})
+		return
+	}
+	// The body is optional, without it only the access token is revoked
+	var lr models.LogoutRequest
+	if c.Request.ContentLength != 0 {
+		err := json.NewDecoder(c.Request.Body).Decode(&lr)
+		if err != nil {
+			log.Error().Err(err).Str("Trace Id", traceId).Msg("error in decoding")
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"msg": http.StatusText(http.StatusBadRequest)})
+			return
+		}
+	}
+	err := h.s.Logout(ctx, claims, lr.RefreshToken)
+	if errors.Is(err, services.ErrForeignRefreshToken) {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"msg": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("logout failed")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"msg": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"msg": "logged out"})
+}
 
+// Changing the role of a user API, admins only
+func (h *handler) setUserRole(c *gin.Context) {
+	ctx := c.Request.Context()
+	traceId, ok := ctx.Value(middlewares.TraceIdKey).(string)
+	if !ok {
+		log.Error().Msg("traceId missing from context")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	uid, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		log.Error().Str("Trace Id", traceId).Msg("user id invalid")
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	var ur models.UserRole
+	err = json.NewDecoder(c.Request.Body).Decode(&ur)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("error in decoding")
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": http.StatusText(http.StatusBadRequest)})
+		return
+	}
+	validate := validator.New()
+	err = validate.Struct(ur)
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "role must be candidate or recruiter"})
+		return
+	}
+	u, err := h.s.SetUserRole(ctx, uint(uid), ur.Role)
+	if errors.Is(err, services.ErrUserNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		log.Error().Err(err).Str("Trace Id", traceId).Msg("changing role")
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		return
+	}
+	c.JSON(http.StatusOK, u)
 }
 
 func (h *handler) ForgotPassword(c *gin.Context) {
@@ -125,13 +329,19 @@ func (h *handler) ForgotPassword(c *gin.Context) {
 		return
 	}
 
-	otp, err := h.s.OTPGeneration(ctx, fp)
+	msg, err := h.s.OTPGeneration(ctx, fp)
+	if errors.Is(err, services.ErrOTPCooldown) {
+		log.Error().Err(err).Str("traceid", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
-		log.Error().Str("traceid", traceid).Msg("error in generating otp")
+		log.Error().Err(err).Str("traceid", traceid).Msg("error in generating otp")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
 		return
 	}
-	c.JSON(http.StatusOK, otp)
+	// The code itself only ever goes out by email
+	c.JSON(http.StatusOK, gin.H{"msg": msg})
 }
 
 func (h *handler) SetNewPassword(c *gin.Context) {
@@ -157,6 +367,16 @@ func (h *handler) SetNewPassword(c *gin.Context) {
 		return
 	}
 	pwd, err := h.s.ChangePassword(ctx, verifyotp)
+	if errors.Is(err, services.ErrInvalidOTP) || errors.Is(err, services.ErrPasswordMismatch) {
+		log.Error().Err(err).Str("traceid", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrTooManyOTPAttempts) {
+		log.Error().Err(err).Str("traceid", traceid).Send()
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		log.Error().Str("traceid", traceid).Msg("error in generating new password")
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
@@ -13,7 +13,6 @@ import (
 	"testing"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/assert/v2"
-	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/mock/gomock"
 )
 
@@ -84,7 +83,7 @@ func Test_handler_Registration(t *testing.T) {
 			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
-				httpRequest, _ := http.NewRequest(http.MethodGet, "http://tests.com", strings.NewReader(`{"name":"nikitha","email":"niki@gmail.com","password":"1234"}`))
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://tests.com", strings.NewReader(`{"name":"nikitha","dob":"1999-01-01","email":"niki@gmail.com","password":"1234"}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
 				httpRequest = httpRequest.WithContext(ctx)
@@ -96,13 +95,30 @@ func Test_handler_Registration(t *testing.T) {
 				return c, rr, ms
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse:   `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"name":"","email":""}`,
+			expectedResponse:   `{"ID":0,"CreatedAt":"0001-01-01T00:00:00Z","UpdatedAt":"0001-01-01T00:00:00Z","DeletedAt":null,"name":"","dob":"","email":"","role":"","verified":false}`,
+		},
+		{name: "email already registered",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://tests.com", strings.NewReader(`{"name":"nikitha","dob":"1999-01-01","email":"niki@gmail.com","password":"1234"}`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				mc := gomock.NewController(t)
+				ms := services.NewMockUserService(mc)
+				ms.EXPECT().Signup(gomock.Any(), gomock.Any()).Return(models.User{}, services.ErrEmailTaken)
+				return c, rr, ms
+			},
+			expectedStatusCode: http.StatusConflict,
+			expectedResponse:   `{"error":"an account with this email already exists"}`,
 		},
 		{name: "registration failure",
 			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService) {
 				rr := httptest.NewRecorder()
 				c, _ := gin.CreateTestContext(rr)
-				httpRequest, _ := http.NewRequest(http.MethodGet, "http://tests.com", strings.NewReader(`{"name":"nikitha","email":"niki@gmail.com","password":"1234"}`))
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://tests.com", strings.NewReader(`{"name":"nikitha","dob":"1999-01-01","email":"niki@gmail.com","password":"1234"}`))
 				ctx := httpRequest.Context()
 				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
 				httpRequest = httpRequest.WithContext(ctx)
@@ -207,13 +223,14 @@ func Test_handler_Login(t *testing.T) {
 				ms := services.NewMockUserService(mc)
 				ma := auth.NewMockAuthentication(mc)
 
-				ms.EXPECT().Login(gomock.Any(), gomock.Any(), gomock.Any()).Return(jwt.RegisteredClaims{}, nil)
-				ma.EXPECT().GenerateToken(gomock.Any()).Return("", nil)
+				ms.EXPECT().Login(gomock.Any(), gomock.Any(), gomock.Any()).Return(auth.Claims{}, nil)
+				ma.EXPECT().GenerateToken(gomock.Any()).Return("access", nil)
+				ms.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return("refresh", nil)
 
 				return c, rr, ms, ma
 			},
 			expectedStatusCode: http.StatusOK,
-			expectedResponse:   `{"token":""}`,
+			expectedResponse:   `{"token":"access","refresh_token":"refresh"}`,
 		},
 		{name: "login failure",
 			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService, auth.Authentication) {
@@ -226,13 +243,31 @@ func Test_handler_Login(t *testing.T) {
 				c.Request = httpRequest
 				mc := gomock.NewController(t)
 				ms := services.NewMockUserService(mc)
-				ms.EXPECT().Login(gomock.Any(), gomock.Any(), gomock.Any()).Return(jwt.RegisteredClaims{}, errors.New("error"))
+				ms.EXPECT().Login(gomock.Any(), gomock.Any(), gomock.Any()).Return(auth.Claims{}, errors.New("error"))
 
 				return c, rr, ms, nil
 			},
 			expectedStatusCode: http.StatusUnauthorized,
 			expectedResponse:   `{"msg":"login failed"}`,
 		},
+		{name: "email not verified",
+			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService, auth.Authentication) {
+				rr := httptest.NewRecorder()
+				c, _ := gin.CreateTestContext(rr)
+				httpRequest, _ := http.NewRequest(http.MethodGet, "http://tests.com", strings.NewReader(`{"email":"werty@gmail.com","password":"1234"}`))
+				ctx := httpRequest.Context()
+				ctx = context.WithValue(ctx, middlewares.TraceIdKey, "1")
+				httpRequest = httpRequest.WithContext(ctx)
+				c.Request = httpRequest
+				mc := gomock.NewController(t)
+				ms := services.NewMockUserService(mc)
+				ms.EXPECT().Login(gomock.Any(), gomock.Any(), gomock.Any()).Return(auth.Claims{}, services.ErrEmailNotVerified)
+
+				return c, rr, ms, nil
+			},
+			expectedStatusCode: http.StatusForbidden,
+			expectedResponse:   `{"msg":"email address is not verified"}`,
+		},
 		{name: " failure in generating token",
 			setup: func() (*gin.Context, *httptest.ResponseRecorder, services.UserService, auth.Authentication) {
 				rr := httptest.NewRecorder()
@@ -245,7 +280,7 @@ func Test_handler_Login(t *testing.T) {
 				mc := gomock.NewController(t)
 				ms := services.NewMockUserService(mc)
 				ma := auth.NewMockAuthentication(mc)
-				ms.EXPECT().Login(gomock.Any(), gomock.Any(), gomock.Any()).Return(jwt.RegisteredClaims{}, nil)
+				ms.EXPECT().Login(gomock.Any(), gomock.Any(), gomock.Any()).Return(auth.Claims{}, nil)
 				ma.EXPECT().GenerateToken(gomock.Any()).Return("", errors.New("error in generating token"))
 				return c, rr, ms, ma
 			},
@@ -265,3 +300,321 @@ func Test_handler_Login(t *testing.T) {
 		})
 	}
 }
+
+func Test_handler_RefreshToken(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		setup              func(ms *services.MockUserService, ma *auth.MockAuthentication)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{name: "missing refresh token",
+			body:               `{}`,
+			setup:              func(ms *services.MockUserService, ma *auth.MockAuthentication) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"msg":"please provide refresh_token"}`,
+		},
+		{name: "refresh token rejected",
+			body: `{"refresh_token":"old"}`,
+			setup: func(ms *services.MockUserService, ma *auth.MockAuthentication) {
+				ms.EXPECT().RefreshToken(gomock.Any(), "old").Return(auth.Claims{}, "", services.ErrInvalidRefreshToken)
+			},
+			expectedStatusCode: http.StatusUnauthorized,
+			expectedResponse:   `{"msg":"invalid or expired refresh token"}`,
+		},
+		{name: "refresh token rotated",
+			body: `{"refresh_token":"old"}`,
+			setup: func(ms *services.MockUserService, ma *auth.MockAuthentication) {
+				ms.EXPECT().RefreshToken(gomock.Any(), "old").Return(auth.Claims{}, "new", nil)
+				ma.EXPECT().GenerateToken(gomock.Any()).Return("access", nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"token":"access","refresh_token":"new"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodPost, "http://tests.com", strings.NewReader(tt.body))
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+			c.Request = httpRequest.WithContext(ctx)
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			ma := auth.NewMockAuthentication(mc)
+			tt.setup(ms, ma)
+			h := &handler{s: ms, a: ma}
+			h.RefreshToken(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_Logout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	httpRequest, _ := http.NewRequest(http.MethodPost, "http://tests.com", strings.NewReader(`{"refresh_token":"rt"}`))
+	ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+	ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+	c.Request = httpRequest.WithContext(ctx)
+	mc := gomock.NewController(t)
+	ms := services.NewMockUserService(mc)
+	ms.EXPECT().Logout(gomock.Any(), auth.Claims{}, "rt").Return(nil)
+	h := &handler{s: ms}
+	h.Logout(c)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, `{"msg":"logged out"}`, rr.Body.String())
+}
+
+func Test_handler_Logout_foreignRefreshToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rr := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rr)
+	httpRequest, _ := http.NewRequest(http.MethodPost, "http://tests.com", strings.NewReader(`{"refresh_token":"rt"}`))
+	ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+	ctx = context.WithValue(ctx, auth.Key, auth.Claims{})
+	c.Request = httpRequest.WithContext(ctx)
+	mc := gomock.NewController(t)
+	ms := services.NewMockUserService(mc)
+	ms.EXPECT().Logout(gomock.Any(), auth.Claims{}, "rt").Return(services.ErrForeignRefreshToken)
+	h := &handler{s: ms}
+	h.Logout(c)
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Equal(t, `{"msg":"refresh token belongs to another user"}`, rr.Body.String())
+}
+
+func Test_handler_setUserRole(t *testing.T) {
+	tests := []struct {
+		name               string
+		id                 string
+		body               string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{name: "admins cannot be made",
+			id:                 "4",
+			body:               `{"role":"admin"}`,
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"role must be candidate or recruiter"}`,
+		},
+		{name: "invalid id",
+			id:                 "abc",
+			body:               `{"role":"recruiter"}`,
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"error":"Bad Request"}`,
+		},
+		{name: "no such user",
+			id:   "4",
+			body: `{"role":"recruiter"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().SetUserRole(gomock.Any(), uint(4), models.RoleRecruiter).Return(models.User{}, services.ErrUserNotFound)
+			},
+			expectedStatusCode: http.StatusNotFound,
+			expectedResponse:   `{"error":"user not found"}`,
+		},
+		{name: "user made a recruiter",
+			id:   "4",
+			body: `{"role":"recruiter"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().SetUserRole(gomock.Any(), uint(4), models.RoleRecruiter).Return(models.User{Name: "Asha", Role: models.RoleRecruiter}, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodPut, "http://tests.com", strings.NewReader(tt.body))
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+			c.Request = httpRequest.WithContext(ctx)
+			c.Params = append(c.Params, gin.Param{Key: "id", Value: tt.id})
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			tt.setup(ms)
+			h := &handler{s: ms}
+			h.setUserRole(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			if tt.expectedResponse != "" {
+				assert.Equal(t, tt.expectedResponse, rr.Body.String())
+			}
+		})
+	}
+}
+
+func Test_handler_ForgotPassword(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{name: "cooldown active",
+			body: `{"email":"niki@gmail.com","dob":"1999-01-01"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().OTPGeneration(gomock.Any(), gomock.Any()).Return("", services.ErrOTPCooldown)
+			},
+			expectedStatusCode: http.StatusTooManyRequests,
+			expectedResponse:   `{"error":"a reset code was requested recently, try again later"}`,
+		},
+		{name: "code sent",
+			body: `{"email":"niki@gmail.com","dob":"1999-01-01"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().OTPGeneration(gomock.Any(), gomock.Any()).Return(services.OTPSentMessage, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"msg":"` + services.OTPSentMessage + `"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodPost, "http://tests.com", strings.NewReader(tt.body))
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+			c.Request = httpRequest.WithContext(ctx)
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			tt.setup(ms)
+			h := &handler{s: ms}
+			h.ForgotPassword(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_SetNewPassword(t *testing.T) {
+	body := `{"email":"niki@gmail.com","otp":"123456","password":"secret","confirmpassword":"secret"}`
+	tests := []struct {
+		name               string
+		err                error
+		expectedStatusCode int
+	}{
+		{name: "wrong code", err: services.ErrInvalidOTP, expectedStatusCode: http.StatusBadRequest},
+		{name: "passwords differ", err: services.ErrPasswordMismatch, expectedStatusCode: http.StatusBadRequest},
+		{name: "too many attempts", err: services.ErrTooManyOTPAttempts, expectedStatusCode: http.StatusTooManyRequests},
+		{name: "unexpected failure", err: errors.New("db down"), expectedStatusCode: http.StatusInternalServerError},
+		{name: "password changed", expectedStatusCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodPost, "http://tests.com", strings.NewReader(body))
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+			c.Request = httpRequest.WithContext(ctx)
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			ms.EXPECT().ChangePassword(gomock.Any(), gomock.Any()).Return("password updated", tt.err)
+			h := &handler{s: ms}
+			h.SetNewPassword(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+		})
+	}
+}
+
+func Test_handler_VerifyEmail(t *testing.T) {
+	tests := []struct {
+		name               string
+		query              string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+		expectedResponse   string
+	}{
+		{name: "missing token",
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"msg":"please provide token"}`,
+		},
+		{name: "invalid token",
+			query: "?token=bad",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().VerifyEmail(gomock.Any(), "bad").Return(services.ErrInvalidVerificationToken)
+			},
+			expectedStatusCode: http.StatusBadRequest,
+			expectedResponse:   `{"msg":"invalid or expired verification link"}`,
+		},
+		{name: "email verified",
+			query: "?token=good",
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().VerifyEmail(gomock.Any(), "good").Return(nil)
+			},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   `{"msg":"email verified"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodGet, "http://tests.com/verify-email"+tt.query, nil)
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+			c.Request = httpRequest.WithContext(ctx)
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			tt.setup(ms)
+			h := &handler{s: ms}
+			h.VerifyEmail(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+			assert.Equal(t, tt.expectedResponse, rr.Body.String())
+		})
+	}
+}
+
+func Test_handler_ResendVerification(t *testing.T) {
+	tests := []struct {
+		name               string
+		body               string
+		setup              func(ms *services.MockUserService)
+		expectedStatusCode int
+	}{
+		{name: "invalid email",
+			body:               `{"email":"niki"}`,
+			setup:              func(ms *services.MockUserService) {},
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{name: "cooldown active",
+			body: `{"email":"niki@gmail.com"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ResendVerification(gomock.Any(), "niki@gmail.com").Return("", services.ErrVerificationCooldown)
+			},
+			expectedStatusCode: http.StatusTooManyRequests,
+		},
+		{name: "link sent",
+			body: `{"email":"niki@gmail.com"}`,
+			setup: func(ms *services.MockUserService) {
+				ms.EXPECT().ResendVerification(gomock.Any(), "niki@gmail.com").Return(services.VerificationSentMessage, nil)
+			},
+			expectedStatusCode: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			rr := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rr)
+			httpRequest, _ := http.NewRequest(http.MethodPost, "http://tests.com", strings.NewReader(tt.body))
+			ctx := context.WithValue(httpRequest.Context(), middlewares.TraceIdKey, "1")
+			c.Request = httpRequest.WithContext(ctx)
+			mc := gomock.NewController(t)
+			ms := services.NewMockUserService(mc)
+			tt.setup(ms)
+			h := &handler{s: ms}
+			h.ResendVerification(c)
+			assert.Equal(t, tt.expectedStatusCode, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	msg, err := Render("password_reset", "niki@gmail.com", map[string]any{"Name": "Niki", "OTP": "123456", "ValidFor": "5 minutes"})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if msg.Subject != "Reset your Job Portal password" {
+		t.Errorf("Render() subject = %q", msg.Subject)
+	}
+	if len(msg.To) != 1 || msg.To[0] != "niki@gmail.com" {
+		t.Errorf("Render() to = %v", msg.To)
+	}
+	if !strings.Contains(msg.Body, "Hi Niki,") || !strings.Contains(msg.Body, "123456") {
+		t.Errorf("Render() body = %q", msg.Body)
+	}
+
+	_, err = Render("missing", "niki@gmail.com", nil)
+	if err == nil {
+		t.Errorf("Render() of an unknown template should fail")
+	}
+}
+
+func TestOutbox_Send(t *testing.T) {
+	dir := t.TempDir()
+	o, err := NewOutbox(dir, "no-reply@jobportal.local")
+	if err != nil {
+		t.Fatalf("NewOutbox() error = %v", err)
+	}
+	err = o.Send(context.Background(), Message{To: []string{"niki@gmail.com"}, Subject: "hello", Body: "line one\nline two"})
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("outbox files = %v", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	for _, want := range []string{"From: no-reply@jobportal.local\r\n", "To: niki@gmail.com\r\n", "Subject: hello\r\n", "line one\r\nline two"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("outbox mail missing %q in %q", want, data)
+		}
+	}
+}
+
+func TestNewMailer(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{name: "outbox by default", cfg: Config{}},
+		{name: "smtp needs a host", cfg: Config{Driver: "smtp"}, wantErr: true},
+		{name: "smtp", cfg: Config{Driver: "smtp", SMTPHost: "localhost", SMTPPort: "25"}},
+		{name: "unknown driver", cfg: Config{Driver: "pigeon"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMailer(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewMailer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
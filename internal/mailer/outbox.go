@@ -0,0 +1,59 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Outbox keeps mail on disk (or only in the log when no directory is set)
+// instead of sending it, for local development and tests
+type Outbox struct {
+	dir  string
+	from string
+	mu   sync.Mutex
+	n    int
+}
+
+func NewOutbox(dir, from string) (*Outbox, error) {
+	if dir != "" {
+		err := os.MkdirAll(dir, 0o755)
+		if err != nil {
+			return nil, fmt.Errorf("creating outbox dir %w", err)
+		}
+	}
+	return &Outbox{dir: dir, from: from}, nil
+}
+
+func (o *Outbox) Send(ctx context.Context, msg Message) error {
+	log.Info().Strs("To", msg.To).Str("Subject", msg.Subject).Msg("mail written to outbox")
+	if o.dir == "" {
+		log.Debug().Str("Body", msg.Body).Msg("outbox mail body")
+		return nil
+	}
+	o.mu.Lock()
+	o.n++
+	name := fmt.Sprintf("%s-%04d-%s.eml", time.Now().UTC().Format("20060102T150405.000000000"), o.n, sanitize(strings.Join(msg.To, "_")))
+	o.mu.Unlock()
+	err := os.WriteFile(filepath.Join(o.dir, name), format(o.from, msg), 0o600)
+	if err != nil {
+		return fmt.Errorf("writing outbox mail %w", err)
+	}
+	return nil
+}
+
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+			return r
+		}
+		return '_'
+	}, s)
+}
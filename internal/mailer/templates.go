@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+var templates = template.Must(template.ParseFS(templateFiles, "templates/*.tmpl"))
+
+// Render fills the named template (templates/<name>.tmpl) and addresses the
+// message to the given recipient. Each template defines a "<name>_subject"
+// and a "<name>_body" block.
+func Render(name string, to string, data any) (Message, error) {
+	var subject, body bytes.Buffer
+	err := templates.ExecuteTemplate(&subject, name+"_subject", data)
+	if err != nil {
+		return Message{}, fmt.Errorf("rendering %s subject %w", name, err)
+	}
+	err = templates.ExecuteTemplate(&body, name+"_body", data)
+	if err != nil {
+		return Message{}, fmt.Errorf("rendering %s body %w", name, err)
+	}
+	return Message{
+		To:      []string{to},
+		Subject: strings.TrimSpace(subject.String()),
+		Body:    body.String(),
+	}, nil
+}
+
+// format builds the raw RFC 5322 message
+func format(from string, msg Message) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return b.Bytes()
+}
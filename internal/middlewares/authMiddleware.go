@@ -30,7 +30,7 @@ func (m *Mid) AuthenticationMiddleware(next gin.HandlerFunc) gin.HandlerFunc {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 			return
 		}
-		claims, err := m.a.ValidateToken(parts[1])
+		claims, err := m.a.ValidateToken(ctx, parts[1])
 		if err != nil {
 			log.Error().Err(err).Str("Trace Id", traceId).Send()
 			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
@@ -42,3 +42,30 @@ func (m *Mid) AuthenticationMiddleware(next gin.HandlerFunc) gin.HandlerFunc {
 		next(c)
 	}
 }
+
+// Role based access middleware, lets the request through only when the
+// authenticated user holds one of the given roles
+func (m *Mid) Authorize(next gin.HandlerFunc, roles ...string) gin.HandlerFunc {
+
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+		traceId, ok := ctx.Value(TraceIdKey).(string)
+		if !ok {
+			log.Error().Msg("trace id not present in the context")
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+			return
+		}
+		claims, ok := ctx.Value(auth.Key).(auth.Claims)
+		if !ok {
+			log.Error().Str("Trace Id", traceId).Msg("claims not present in the context")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": http.StatusText(http.StatusUnauthorized)})
+			return
+		}
+		if !claims.HasRole(roles...) {
+			log.Error().Str("Trace Id", traceId).Strs("Roles", claims.Roles).Str("URL Path", c.Request.URL.Path).Msg("role not allowed")
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": http.StatusText(http.StatusForbidden)})
+			return
+		}
+		next(c)
+	}
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Stages an application goes through
+const (
+	StageApplied   = "applied"
+	StageScreening = "screening"
+	StageInterview = "interview"
+	StageOffer     = "offer"
+	StageHired     = "hired"
+	StageRejected  = "rejected"
+	StageWithdrawn = "withdrawn"
+)
+
+// ApplicationStages lists the stages an application may move to from each
+// stage. Hired, rejected and withdrawn applications are finished. Only the
+// candidate withdraws an application.
+var ApplicationStages = map[string][]string{
+	StageApplied:   {StageScreening, StageInterview, StageRejected, StageWithdrawn},
+	StageScreening: {StageInterview, StageRejected, StageWithdrawn},
+	StageInterview: {StageOffer, StageRejected, StageWithdrawn},
+	StageOffer:     {StageHired, StageRejected, StageWithdrawn},
+	StageHired:     {},
+	StageRejected:  {},
+	StageWithdrawn: {},
+}
+
+// Application is a candidate applying for a job, a candidate applies for a
+// job only once
+type Application struct {
+	gorm.Model
+	UserId  uint            `json:"uid" gorm:"not null;uniqueIndex:idx_application_user_job"`
+	User    User            `json:"-" gorm:"ForeignKey:UserId"`
+	JobId   uint64          `json:"jid" gorm:"not null;uniqueIndex:idx_application_user_job"`
+	Job     Job             `json:"-" gorm:"ForeignKey:JobId"`
+	Name    string          `json:"name"`
+	Age     string          `json:"age"`
+	Details RequestFromUser `json:"job_application" gorm:"serializer:json"`
+	// Matched and Score are the result of matching the application against
+	// the job at the time it was made
+	Matched bool    `json:"matched" gorm:"not null;default:false"`
+	Score   float64 `json:"score" gorm:"not null;default:0"`
+	Stage   string  `json:"stage" gorm:"not null;default:applied;index"`
+}
+
+// CanMoveTo reports whether ApplicationStages allows the move
+func (a Application) CanMoveTo(stage string) bool {
+	for _, s := range ApplicationStages[a.Stage] {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplicationStageChange records who moved an application to another stage
+// and when
+type ApplicationStageChange struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	ApplicationId uint      `json:"application_id" gorm:"not null;index"`
+	FromStage     string    `json:"from"`
+	ToStage       string    `json:"to"`
+	ChangedBy     uint      `json:"changed_by"`
+	Note          string    `json:"note"`
+	CreatedAt     time.Time `json:"changed_at"`
+}
+
+// NewApplication is the body of POST /jobs/:id/apply. Without a name the
+// name of the account is used, without job_application the candidate profile.
+type NewApplication struct {
+	Name string           `json:"name"`
+	Age  string           `json:"age"`
+	Jobs *RequestFromUser `json:"job_application"`
+}
+
+// MoveApplication is the body of POST /applications/:id/stage, withdrawing is
+// left to the candidate
+type MoveApplication struct {
+	Stage string `json:"stage" validate:"required,oneof=screening interview offer hired rejected"`
+	Note  string `json:"note" validate:"max=1000"`
+}
+
+// ApplicationListQuery holds the query string of GET /applications, repeat
+// stage for several stages
+type ApplicationListQuery struct {
+	JobId  uint64   `form:"job" validate:"required"`
+	Stages []string `form:"stage" validate:"dive,oneof=applied screening interview offer hired rejected withdrawn"`
+}
@@ -4,9 +4,32 @@ import (
 	"gorm.io/gorm"
 )
 
+// Roles a user can hold inside a company
+const (
+	MemberOwner     = "owner"
+	MemberRecruiter = "recruiter"
+)
+
 type Company struct {
 	gorm.Model
 	CompanyName string `json:"company_name" validate:"required"`
 	Address     string `json:"address" validate:"required"`
 	Domain      string `json:"domain" validate:"required"`
+	// MatchRules are used for the jobs of the company that set none
+	MatchRules *MatchRules `json:"match_rules,omitempty" gorm:"serializer:json"`
+}
+
+// CompanyMember links a user to a company they can hire for
+type CompanyMember struct {
+	gorm.Model
+	CompanyId uint64  `json:"company_id" gorm:"uniqueIndex:idx_company_user"`
+	Comp      Company `json:"-" gorm:"ForeignKey:CompanyId"`
+	UserId    uint    `json:"user_id" gorm:"uniqueIndex:idx_company_user"`
+	User      User    `json:"-" gorm:"ForeignKey:UserId"`
+	Role      string  `json:"role"`
+}
+
+type NewCompanyMember struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=owner recruiter"`
 }
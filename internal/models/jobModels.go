@@ -1,13 +1,56 @@
 package models
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
+// Pay periods of a salary
+const (
+	PayHourly  = "hourly"
+	PayMonthly = "monthly"
+	PayAnnual  = "annual"
+)
+
+// PayPeriodsPerYear converts a salary to an annual one, an hourly salary
+// assumes a 40 hour week
+var PayPeriodsPerYear = map[string]float64{
+	PayHourly:  2080,
+	PayMonthly: 12,
+	PayAnnual:  1,
+}
+
+// Job statuses, JobTransitions lists the moves between them
+const (
+	JobDraft     = "draft"
+	JobPublished = "published"
+	JobPaused    = "paused"
+	JobClosed    = "closed"
+	JobExpired   = "expired"
+)
+
+// JobTransitions maps a status to the statuses a job in it may move to. A
+// closed job stays closed, an expired one can be published again with a new
+// expiry date.
+var JobTransitions = map[string][]string{
+	JobDraft:     {JobPublished, JobClosed},
+	JobPublished: {JobPaused, JobClosed, JobExpired},
+	JobPaused:    {JobPublished, JobClosed, JobExpired},
+	JobExpired:   {JobPublished, JobClosed},
+	JobClosed:    {},
+}
+
 type Job struct {
 	gorm.Model
 	JobTitle            string          `json:"job_title" validate:"required"`
-	Salary              string          `json:"sal" validate:"required"`
+	Status              string          `json:"status" gorm:"not null;default:draft;index"`
+	ExpiresAt           *time.Time      `json:"expires_at"`
+	SalaryMin           float64         `json:"salary_min"`
+	SalaryMax           float64         `json:"salary_max"`
+	SalaryCurrency      string          `json:"salary_currency" gorm:"size:3"`
+	SalaryPeriod        string          `json:"salary_period"`
+	SalaryHidden        bool            `json:"salary_hidden" gorm:"not null;default:false"`
 	CompanyId           uint64          `json:"cid" validate:"required"`
 	Comp                Company         `gorm:"ForeignKey:CompanyId"`
 	MinimumNoticePeriod int             `json:"min_np" validate:"required"`
@@ -22,7 +65,25 @@ type Job struct {
 	Qualifications      []Qualification `gorm:"many2many:job_qualifications;"`
 	Shifts              []Shift         `gorm:"many2many:job_shifts;"`
 	JobTypes            []JobType       `gorm:"many2many:job_jobtypes;"`
+	// MatchRules override the match rules of the company for this job
+	MatchRules *MatchRules `json:"match_rules,omitempty" gorm:"serializer:json"`
 }
+
+// CanMoveTo reports whether the job may move from its status to status
+func (j Job) CanMoveTo(status string) bool {
+	for _, next := range JobTransitions[j.Status] {
+		if next == status {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOpen reports whether the job takes applications at t
+func (j Job) IsOpen(t time.Time) bool {
+	return j.Status == JobPublished && (j.ExpiresAt == nil || t.Before(*j.ExpiresAt))
+}
+
 type Location struct {
 	gorm.Model
 	State string `json:"state" validate:"required"`
@@ -49,21 +110,157 @@ type JobType struct {
 }
 
 type NewJobRequest struct {
-	JobTitle            string  `json:"jobTitle" validate:"required"`
-	Salary              string  `json:"sal" validate:"required"`
-	MinimumNoticePeriod int     `json:"minNp" validate:"required"`
-	MaximumNoticePeriod uint64  `json:"maxNp" validate:"required"`
-	Budget              float64 `json:"budget" validate:"required"`
-	JobDescription      string  `json:"jobDesc" validate:"required"`
-	MinExperience       float64 `json:"minExp" validate:"required"`
-	MaxExperience       float64 `json:"maxExp" validate:"required"`
+	JobTitle       string  `json:"jobTitle" validate:"required"`
+	SalaryMin      float64 `json:"salaryMin" validate:"required,gt=0"`
+	SalaryMax      float64 `json:"salaryMax" validate:"required,gtefield=SalaryMin"`
+	SalaryCurrency string  `json:"salaryCurrency" validate:"required,iso4217"`
+	SalaryPeriod   string  `json:"salaryPeriod" validate:"required,oneof=hourly monthly annual"`
+	SalaryHidden   bool    `json:"salaryHidden"`
+	// ExpiresAt closes the job automatically, it must be in the future
+	ExpiresAt           *time.Time `json:"expiresAt"`
+	MinimumNoticePeriod int        `json:"minNp" validate:"required"`
+	MaximumNoticePeriod uint64     `json:"maxNp" validate:"required"`
+	Budget              float64    `json:"budget" validate:"required"`
+	JobDescription      string     `json:"jobDesc" validate:"required"`
+	MinExperience       float64    `json:"minExp" validate:"required"`
+	MaxExperience       float64    `json:"maxExp" validate:"required"`
 	LocationIDs         []uint
 	SkillIDs            []uint
 	WorkModeIDs         []uint
 	QualificationIDs    []uint
 	ShiftIDs            []uint
 	JobTypeIDs          []uint
+	// MatchRules are left empty to use the rules of the company
+	MatchRules *MatchRules `json:"matchRules"`
+}
+
+// JobPatchRequest changes only the fields that are present, an ID list that
+// is present replaces the whole association (an empty list clears it). The
+// status is changed through the publish, pause and close endpoints.
+type JobPatchRequest struct {
+	JobTitle            *string    `json:"jobTitle" validate:"omitempty,min=1"`
+	SalaryMin           *float64   `json:"salaryMin" validate:"omitempty,gt=0"`
+	SalaryMax           *float64   `json:"salaryMax" validate:"omitempty,gt=0"`
+	SalaryCurrency      *string    `json:"salaryCurrency" validate:"omitempty,iso4217"`
+	SalaryPeriod        *string    `json:"salaryPeriod" validate:"omitempty,oneof=hourly monthly annual"`
+	SalaryHidden        *bool      `json:"salaryHidden"`
+	ExpiresAt           *time.Time `json:"expiresAt"`
+	MinimumNoticePeriod *int       `json:"minNp" validate:"omitempty,min=0"`
+	MaximumNoticePeriod *uint64    `json:"maxNp"`
+	Budget              *float64   `json:"budget" validate:"omitempty,min=0"`
+	JobDescription      *string    `json:"jobDesc" validate:"omitempty,min=1"`
+	MinExperience       *float64   `json:"minExp" validate:"omitempty,min=0"`
+	MaxExperience       *float64   `json:"maxExp" validate:"omitempty,min=0"`
+	LocationIDs         *[]uint
+	SkillIDs            *[]uint
+	WorkModeIDs         *[]uint
+	QualificationIDs    *[]uint
+	ShiftIDs            *[]uint
+	JobTypeIDs          *[]uint
+	MatchRules          *MatchRules `json:"matchRules"`
+}
+
+// PublishJobRequest optionally sets a new expiry date when publishing, an
+// expired job cannot be published again without one
+type PublishJobRequest struct {
+	ExpiresAt *time.Time `json:"expiresAt"`
 }
+
+// Job listing defaults, a page never holds more than MaxJobPageSize jobs
+const (
+	DefaultJobPageSize = 20
+	MaxJobPageSize     = 100
+)
+
+// JobListQuery holds the query string of GET /jobs. The id filters take
+// repeated keys (?skill=1&skill=2) and match jobs tagged with any of the ids.
+type JobListQuery struct {
+	Page     int    `form:"page" validate:"omitempty,min=1"`
+	PageSize int    `form:"page_size" validate:"omitempty,min=1,max=100"`
+	Sort     string `form:"sort" validate:"omitempty,oneof=created_at budget experience"`
+	Order    string `form:"order" validate:"omitempty,oneof=asc desc"`
+	// Statuses defaults to published, other statuses are only listed to the
+	// members of the company in CompanyId
+	Statuses []string `form:"status" validate:"dive,oneof=draft published paused closed expired"`
+
+	CompanyId   *uint64 `form:"company"`
+	LocationIDs []uint  `form:"location"`
+	SkillIDs    []uint  `form:"skill"`
+	WorkModeIDs []uint  `form:"work_mode"`
+	JobTypeIDs  []uint  `form:"job_type"`
+	ShiftIDs    []uint  `form:"shift"`
+	// QualificationIDs matches the qualification facet
+	QualificationIDs []uint `form:"qualification"`
+	// MinExperience and MaxExperience keep the jobs whose experience range
+	// overlaps the given range
+	MinExperience *float64 `form:"min_exp" validate:"omitempty,min=0"`
+	MaxExperience *float64 `form:"max_exp" validate:"omitempty,min=0"`
+	// NoticePeriod keeps the jobs that accept a notice period of this many days
+	NoticePeriod *int `form:"notice_period" validate:"omitempty,min=0"`
+	// MinSalary and MaxSalary keep the jobs paying in Currency whose salary
+	// range overlaps the given range, both read per SalaryPeriod (annual by
+	// default) whatever the pay period of the job. Jobs with a hidden salary
+	// never match a salary filter.
+	MinSalary    *float64 `form:"min_salary" validate:"omitempty,min=0"`
+	MaxSalary    *float64 `form:"max_salary" validate:"omitempty,min=0"`
+	Currency     string   `form:"currency" validate:"required_with=MinSalary MaxSalary,omitempty,iso4217"`
+	SalaryPeriod string   `form:"salary_period" validate:"omitempty,oneof=hourly monthly annual"`
+}
+
+// JobPage is one page of a job listing with the number of jobs on all pages
+type JobPage struct {
+	Jobs     []Job     `json:"jobs"`
+	Total    int64     `json:"total"`
+	Page     int       `json:"page"`
+	PageSize int       `json:"page_size"`
+	Facets   JobFacets `json:"facets"`
+}
+
+// FacetCount is the number of matching jobs tagged with one filter value
+type FacetCount struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// JobFacets counts the matching jobs per filter value, most jobs first. The
+// counts of a facet ignore the filter on that same facet, so they show how
+// many jobs selecting another value would add.
+type JobFacets struct {
+	Locations      []FacetCount `json:"locations"`
+	Skills         []FacetCount `json:"skills"`
+	WorkModes      []FacetCount `json:"work_modes"`
+	Shifts         []FacetCount `json:"shifts"`
+	JobTypes       []FacetCount `json:"job_types"`
+	Qualifications []FacetCount `json:"qualifications"`
+	Companies      []FacetCount `json:"companies"`
+}
+
+// JobSearchQuery holds the query string of GET /jobs/search, the listing
+// filters apply on top of the text search. Results are ordered by relevance
+// unless a sort is given.
+type JobSearchQuery struct {
+	Q string `form:"q" validate:"required,max=200"`
+	JobListQuery
+}
+
+// JobSearchHit is a job matching a search with its relevance and the matches
+// wrapped in <mark></mark>, the rest of the highlighted text is HTML-escaped
+type JobSearchHit struct {
+	Job            Job     `json:"job"`
+	Rank           float64 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
+
+type JobSearchPage struct {
+	Results  []JobSearchHit `json:"results"`
+	Total    int64          `json:"total"`
+	Page     int            `json:"page"`
+	PageSize int            `json:"page_size"`
+	Facets   JobFacets      `json:"facets"`
+}
+
 type Response struct {
 	ID uint64
 }
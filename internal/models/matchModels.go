@@ -0,0 +1,108 @@
+package models
+
+// Criteria an application is matched against a job on
+const (
+	CriterionNoticePeriod   = "notice_period"
+	CriterionExperience     = "experience"
+	CriterionLocation       = "location"
+	CriterionSkills         = "skills"
+	CriterionQualifications = "qualifications"
+	CriterionShift          = "shift"
+	CriterionJobType        = "job_type"
+)
+
+// MatchCriteria lists the criteria in the order they are checked
+var MatchCriteria = []string{
+	CriterionNoticePeriod,
+	CriterionExperience,
+	CriterionLocation,
+	CriterionSkills,
+	CriterionQualifications,
+	CriterionShift,
+	CriterionJobType,
+}
+
+// DefaultMatchThreshold is the score an application needs when neither the
+// job nor its company set a threshold, i.e. half of the criteria
+const DefaultMatchThreshold = 50
+
+// MatchRules decide how applications are matched against a job. A job uses
+// its own rules, else the rules of its company, else every criterion weighs 1
+// and the threshold is DefaultMatchThreshold.
+type MatchRules struct {
+	// Weights of the criteria, a criterion left out weighs 1 and a weight of 0
+	// leaves the criterion out of the score
+	Weights map[string]float64 `json:"weights,omitempty" validate:"dive,keys,oneof=notice_period experience location skills qualifications shift job_type,endkeys,min=0"`
+	// Threshold is the lowest score, from 0 to 100, that counts as a match
+	Threshold *float64 `json:"threshold,omitempty" validate:"omitempty,min=0,max=100"`
+	// Mandatory criteria must match whatever the score
+	Mandatory []string `json:"mandatory,omitempty" validate:"dive,oneof=notice_period experience location skills qualifications shift job_type"`
+	// The candidate must have every one of these skills and qualifications
+	RequiredSkillIDs         []uint `json:"requiredSkillIDs,omitempty"`
+	RequiredQualificationIDs []uint `json:"requiredQualificationIDs,omitempty"`
+}
+
+// Weight returns the weight of a criterion
+func (r MatchRules) Weight(criterion string) float64 {
+	w, ok := r.Weights[criterion]
+	if !ok {
+		return 1
+	}
+	return w
+}
+
+// MinScore returns the threshold of the rules or DefaultMatchThreshold
+func (r MatchRules) MinScore() float64 {
+	if r.Threshold == nil {
+		return DefaultMatchThreshold
+	}
+	return *r.Threshold
+}
+
+// MatchResult is the outcome of matching one application against a job
+type MatchResult struct {
+	// Score is the weighted share of the criteria met, from 0 to 100
+	Score     float64 `json:"score"`
+	Threshold float64 `json:"threshold"`
+	Matched   bool    `json:"matched"`
+	// Criteria explain the score, in the order of MatchCriteria
+	Criteria []CriterionResult `json:"criteria"`
+	// The required skills and qualifications the candidate does not have
+	MissingSkillIDs         []uint `json:"missingSkillIDs,omitempty"`
+	MissingQualificationIDs []uint `json:"missingQualificationIDs,omitempty"`
+}
+
+// CriterionResult is how one criterion added to the score
+type CriterionResult struct {
+	Criterion string  `json:"criterion"`
+	Weight    float64 `json:"weight"`
+	// Score is from 0 to 1, only skills can be met in part
+	Score     float64 `json:"score"`
+	Matched   bool    `json:"matched"`
+	Mandatory bool    `json:"mandatory,omitempty"`
+}
+
+// Verdicts of a screened application
+const (
+	VerdictMatched    = "matched"
+	VerdictNotMatched = "not_matched"
+	VerdictError      = "error"
+)
+
+// ScreeningResult is returned for every application sent to be screened,
+// Match is left out when the application could not be matched and Error
+// says why
+type ScreeningResult struct {
+	// Line is the line of the application in a streamed request
+	Line        int                `json:"line,omitempty"`
+	Application NewUserApplication `json:"application"`
+	Verdict     string             `json:"verdict"`
+	Match       *MatchResult       `json:"match,omitempty"`
+	Error       string             `json:"error,omitempty"`
+}
+
+// StreamedApplication is an application read from a line of a streamed request
+type StreamedApplication struct {
+	Line        int
+	Application NewUserApplication
+}
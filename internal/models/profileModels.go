@@ -0,0 +1,57 @@
+package models
+
+import "gorm.io/gorm"
+
+// CandidateProfile keeps what a candidate would otherwise send with every
+// application, applications fall back to it
+type CandidateProfile struct {
+	gorm.Model
+	UserId         uint            `json:"user_id" gorm:"not null;uniqueIndex"`
+	Headline       string          `json:"headline"`
+	NoticePeriod   int             `json:"noticePeriod" gorm:"not null;default:0"`
+	Experience     float64         `json:"experience" gorm:"not null;default:0"`
+	Locations      []Location      `json:"locations" gorm:"many2many:profile_locations;"`
+	Skills         []Skill         `json:"skills" gorm:"many2many:profile_skills;"`
+	WorkModes      []WorkMode      `json:"work_modes" gorm:"many2many:profile_work_modes;"`
+	Qualifications []Qualification `json:"qualifications" gorm:"many2many:profile_qualifications;"`
+	Shifts         []Shift         `json:"shifts" gorm:"many2many:profile_shifts;"`
+	JobTypes       []JobType       `json:"job_types" gorm:"many2many:profile_job_types;"`
+}
+
+// Details returns the profile the way an application carries it
+func (p CandidateProfile) Details() RequestFromUser {
+	d := RequestFromUser{NoticePeriod: p.NoticePeriod, Experience: p.Experience}
+	for _, l := range p.Locations {
+		d.Location = append(d.Location, l.ID)
+	}
+	for _, s := range p.Skills {
+		d.Skills = append(d.Skills, s.ID)
+	}
+	for _, w := range p.WorkModes {
+		d.WorkModeIDs = append(d.WorkModeIDs, w.ID)
+	}
+	for _, q := range p.Qualifications {
+		d.Qualifications = append(d.Qualifications, q.ID)
+	}
+	for _, s := range p.Shifts {
+		d.Shift = append(d.Shift, s.ID)
+	}
+	for _, j := range p.JobTypes {
+		d.JobType = append(d.JobType, j.ID)
+	}
+	return d
+}
+
+// ProfileRequest is the body of POST and PUT /me/profile, the id lists use
+// the names of the job_application of an application
+type ProfileRequest struct {
+	Headline         string  `json:"headline" validate:"max=200"`
+	NoticePeriod     int     `json:"noticePeriod" validate:"min=0,max=365"`
+	Experience       float64 `json:"experience" validate:"min=0,max=60"`
+	LocationIDs      []uint  `json:"location"`
+	SkillIDs         []uint  `json:"technologyStack"`
+	WorkModeIDs      []uint  `json:"work_modes"`
+	QualificationIDs []uint  `json:"qualifications"`
+	ShiftIDs         []uint  `json:"shifts"`
+	JobTypeIDs       []uint  `json:"job_type"`
+}
@@ -0,0 +1,74 @@
+package models
+
+// RecommendedJob is an open job with the score of the candidate for it. The
+// weights, threshold and required items of the match rules are left out.
+type RecommendedJob struct {
+	Job   Job     `json:"job"`
+	Score float64 `json:"score"`
+	// Criteria are the scores of each criterion, in the order of MatchCriteria
+	Criteria []CriterionScore `json:"criteria"`
+}
+
+// CriterionScore is how well the candidate meets one criterion, from 0 to 1
+type CriterionScore struct {
+	Criterion string  `json:"criterion"`
+	Score     float64 `json:"score"`
+}
+
+// Recommendations is the ranking cached for a user. Generation is the
+// recommendations generation it was ranked in, a job change starts a new one.
+type Recommendations struct {
+	Generation int64            `json:"generation"`
+	Jobs       []RecommendedJob `json:"jobs"`
+}
+
+// RecommendationQuery holds the query string of GET /me/recommended-jobs.
+// Without any preference the profile of the candidate is used.
+type RecommendationQuery struct {
+	Page     int `form:"page" validate:"omitempty,min=1"`
+	PageSize int `form:"page_size" validate:"omitempty,min=1,max=100"`
+
+	NoticePeriod     *int     `form:"notice_period" validate:"omitempty,min=0"`
+	Experience       *float64 `form:"experience" validate:"omitempty,min=0"`
+	LocationIDs      []uint   `form:"location"`
+	SkillIDs         []uint   `form:"skill"`
+	WorkModeIDs      []uint   `form:"work_mode"`
+	QualificationIDs []uint   `form:"qualification"`
+	ShiftIDs         []uint   `form:"shift"`
+	JobTypeIDs       []uint   `form:"job_type"`
+}
+
+// HasPreferences reports whether the query carries any preference
+func (q RecommendationQuery) HasPreferences() bool {
+	return q.NoticePeriod != nil || q.Experience != nil ||
+		len(q.LocationIDs) > 0 || len(q.SkillIDs) > 0 || len(q.WorkModeIDs) > 0 ||
+		len(q.QualificationIDs) > 0 || len(q.ShiftIDs) > 0 || len(q.JobTypeIDs) > 0
+}
+
+// Preferences returns the preferences of the query the way an application
+// carries them
+func (q RecommendationQuery) Preferences() RequestFromUser {
+	d := RequestFromUser{
+		Location:       q.LocationIDs,
+		Skills:         q.SkillIDs,
+		WorkModeIDs:    q.WorkModeIDs,
+		Qualifications: q.QualificationIDs,
+		Shift:          q.ShiftIDs,
+		JobType:        q.JobTypeIDs,
+	}
+	if q.NoticePeriod != nil {
+		d.NoticePeriod = *q.NoticePeriod
+	}
+	if q.Experience != nil {
+		d.Experience = *q.Experience
+	}
+	return d
+}
+
+// RecommendationPage is one page of the recommended jobs, best first
+type RecommendationPage struct {
+	Jobs     []RecommendedJob `json:"jobs"`
+	Total    int              `json:"total"`
+	Page     int              `json:"page"`
+	PageSize int              `json:"page_size"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"io"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Resume types accepted for upload, decided from the content of the file
+const (
+	ResumePDF  = "application/pdf"
+	ResumeDOCX = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+)
+
+// MaxResumeSize is the largest resume accepted, in bytes
+const MaxResumeSize = 5 << 20
+
+// ResumeLinkTTL is how long a download link works
+const ResumeLinkTTL = 15 * time.Minute
+
+// Resume is an uploaded CV of a user, optionally attached to one of the
+// user's applications. The file itself is in the blob store under BlobKey.
+type Resume struct {
+	gorm.Model
+	UserId        uint   `json:"user_id" gorm:"not null;index"`
+	ApplicationId *uint  `json:"application_id,omitempty" gorm:"index"`
+	FileName      string `json:"file_name" gorm:"not null"`
+	ContentType   string `json:"content_type" gorm:"not null"`
+	Size          int64  `json:"size" gorm:"not null"`
+	BlobKey       string `json:"-" gorm:"not null;uniqueIndex"`
+}
+
+// ResumeLink is a download link that works without a token until it expires
+type ResumeLink struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ResumeFile is the content of an uploaded resume, multipart.File is one
+type ResumeFile interface {
+	io.Reader
+	io.ReaderAt
+	io.Seeker
+}
+
+// ResumeUpload is a resume as received, before it is checked and stored
+type ResumeUpload struct {
+	FileName      string
+	Size          int64
+	ApplicationId *uint
+	File          ResumeFile
+}
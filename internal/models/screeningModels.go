@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+// Statuses of a batch screening
+const (
+	ScreeningRunning = "running"
+	ScreeningDone    = "done"
+	ScreeningFailed  = "failed"
+)
+
+const (
+	// MaxScreeningSize is the most applications one screening takes
+	MaxScreeningSize = 50000
+	// DefaultScreeningPageSize and MaxScreeningPageSize bound the results
+	// returned with a screening
+	DefaultScreeningPageSize = 100
+	MaxScreeningPageSize     = 1000
+)
+
+// Screening is a batch of applications screened in the background. Results
+// are stored in the order the applications were sent, Done counts them.
+type Screening struct {
+	ID         string     `json:"id"`
+	Status     string     `json:"status"`
+	CreatedBy  uint       `json:"created_by"`
+	Total      int        `json:"total"`
+	Done       int        `json:"done"`
+	Matched    int        `json:"matched"`
+	Errors     int        `json:"errors"`
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+}
+
+// ScreeningQuery pages through the results of a screening
+type ScreeningQuery struct {
+	Page     int `form:"page" validate:"omitempty,min=1"`
+	PageSize int `form:"page_size" validate:"omitempty,min=1,max=1000"`
+}
+
+// ScreeningPage is a screening with one page of its results
+type ScreeningPage struct {
+	Screening
+	Page     int               `json:"page"`
+	PageSize int               `json:"page_size"`
+	Results  []ScreeningResult `json:"results"`
+}
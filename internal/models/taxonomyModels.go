@@ -0,0 +1,44 @@
+package models
+
+// TaxonomyKind describes one of the master-data tables jobs are tagged with.
+// The tables differ only in the name of their name column and of the join
+// table linking them to jobs, so one set of endpoints serves all of them.
+type TaxonomyKind struct {
+	// Path is the url segment, e.g. /locations
+	Path       string
+	Table      string
+	NameColumn string
+	JoinTable  string
+	JoinColumn string
+	// ProfileJoinTable links the items to candidate profiles, by JoinColumn
+	ProfileJoinTable string
+	// MatchRulesKey is the list of MatchRules requiring the items, empty
+	// when match rules cannot require the kind
+	MatchRulesKey string
+}
+
+var (
+	LocationKind      = TaxonomyKind{Path: "locations", Table: "locations", NameColumn: "state", JoinTable: "job_locations", JoinColumn: "location_id", ProfileJoinTable: "profile_locations"}
+	SkillKind         = TaxonomyKind{Path: "skills", Table: "skills", NameColumn: "skillsets", JoinTable: "job_skills", JoinColumn: "skill_id", ProfileJoinTable: "profile_skills", MatchRulesKey: "requiredSkillIDs"}
+	WorkModeKind      = TaxonomyKind{Path: "work-modes", Table: "work_modes", NameColumn: "mode", JoinTable: "job_work_modes", JoinColumn: "work_mode_id", ProfileJoinTable: "profile_work_modes"}
+	QualificationKind = TaxonomyKind{Path: "qualifications", Table: "qualifications", NameColumn: "degree", JoinTable: "job_qualifications", JoinColumn: "qualification_id", ProfileJoinTable: "profile_qualifications", MatchRulesKey: "requiredQualificationIDs"}
+	ShiftKind         = TaxonomyKind{Path: "shifts", Table: "shifts", NameColumn: "shift_type", JoinTable: "job_shifts", JoinColumn: "shift_id", ProfileJoinTable: "profile_shifts"}
+	JobTypeKind       = TaxonomyKind{Path: "job-types", Table: "job_types", NameColumn: "typeofjob", JoinTable: "job_jobtypes", JoinColumn: "job_type_id", ProfileJoinTable: "profile_job_types"}
+)
+
+var TaxonomyKinds = []TaxonomyKind{LocationKind, SkillKind, WorkModeKind, QualificationKind, ShiftKind, JobTypeKind}
+
+// TaxonomyItem is a row of any taxonomy table
+type TaxonomyItem struct {
+	ID   uint   `json:"id"`
+	Name string `json:"name"`
+}
+
+type NewTaxonomyItem struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+// MergeTaxonomyItems lists the duplicates to fold into the item in the url
+type MergeTaxonomyItems struct {
+	IDs []uint `json:"ids" validate:"required,min=1"`
+}
@@ -1,28 +1,62 @@
 package models
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
+// Roles a user can hold
+const (
+	RoleCandidate = "candidate"
+	RoleRecruiter = "recruiter"
+	RoleAdmin     = "admin"
+)
+
 type User struct {
 	gorm.Model
 	Name         string `json:"name"`
 	Dob          string `json:"dob"`
 	Email        string `json:"email"`
+	Role         string `json:"role" gorm:"default:candidate"`
 	PasswordHash string `json:"-"`
+	// Accounts cannot log in until the email address is verified
+	Verified   bool       `json:"verified" gorm:"not null;default:false"`
+	VerifiedAt *time.Time `json:"verified_at,omitempty"`
 }
 
 type NewUser struct {
 	Name     string `json:"name" validate:"required"`
 	Dob      string `json:"dob" validate:"required"`
-	Email    string `json:"email" validate:"required"`
+	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
 }
 
+// UserRole is the body of PUT /users/:id/role, admins are only assigned in the
+// database
+type UserRole struct {
+	Role string `json:"role" validate:"required,oneof=candidate recruiter"`
+}
+
 type Login struct {
-	Email    string `json:"email" validate:"required"`
+	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
 }
+
+// Token pair handed out on login and refresh
+type Token struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+type ResendVerification struct {
+	Email string `json:"email" validate:"required,email"`
+}
 type ForgotPassword struct {
 	Email string `json:"email" validate:"required"`
 	Dob   string `json:"dob" validate:"required"`
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/models"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateApplication stores an application, gorm.ErrDuplicatedKey when the
+// user already applied for the job
+func (r *Repo) CreateApplication(ctx context.Context, a models.Application) (models.Application, error) {
+	// The unique index decides, two requests at once cannot both get in
+	res := r.DB.WithContext(ctx).Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&a)
+	if res.Error != nil {
+		log.Info().Err(res.Error).Send()
+		return models.Application{}, errors.New("application cannot be saved")
+	}
+	if res.RowsAffected == 0 {
+		return models.Application{}, gorm.ErrDuplicatedKey
+	}
+	return a, nil
+}
+
+// GetApplication loads an application with its job, also when the job has
+// been deleted since
+func (r *Repo) GetApplication(ctx context.Context, aid uint) (models.Application, error) {
+	var a models.Application
+	err := r.DB.WithContext(ctx).
+		Preload("Job", func(tx *gorm.DB) *gorm.DB { return tx.Unscoped() }).
+		Where("id = ?", aid).
+		First(&a).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.Application{}, err
+	}
+	return a, nil
+}
+
+// ListJobApplications returns the applications for a job, oldest first, only
+// those in one of the stages when stages are given
+func (r *Repo) ListJobApplications(ctx context.Context, jid uint64, stages []string) ([]models.Application, error) {
+	a := []models.Application{}
+	tx := r.DB.WithContext(ctx).Where("job_id = ?", jid)
+	if len(stages) > 0 {
+		tx = tx.Where("stage IN ?", stages)
+	}
+	err := tx.Order("created_at, id").Find(&a).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("applications cannot be fetched")
+	}
+	return a, nil
+}
+
+// ListUserApplications returns the applications of a user, newest first
+func (r *Repo) ListUserApplications(ctx context.Context, uid uint) ([]models.Application, error) {
+	a := []models.Application{}
+	err := r.DB.WithContext(ctx).Where("user_id = ?", uid).Order("created_at DESC, id DESC").Find(&a).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("applications cannot be fetched")
+	}
+	return a, nil
+}
+
+// MoveApplication moves an application from change.FromStage to
+// change.ToStage and records the change, gorm.ErrRecordNotFound when the
+// application is gone or no longer in change.FromStage
+func (r *Repo) MoveApplication(ctx context.Context, change models.ApplicationStageChange) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Application{}).
+			Where("id = ? AND stage = ?", change.ApplicationId, change.FromStage).
+			Update("stage", change.ToStage)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(&change).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("application cannot be moved")
+	}
+	return nil
+}
+
+// ApplicationHistory returns the stage changes of an application, oldest first
+func (r *Repo) ApplicationHistory(ctx context.Context, aid uint) ([]models.ApplicationStageChange, error) {
+	h := []models.ApplicationStageChange{}
+	err := r.DB.WithContext(ctx).Where("application_id = ?", aid).Order("created_at, id").Find(&h).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("application history cannot be fetched")
+	}
+	return h, nil
+}
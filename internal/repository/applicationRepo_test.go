@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"job-portal-api/internal/models"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestRepo_GetApplication_deletedJob(t *testing.T) {
+	r, statements := dryRunRepo(t)
+	// Stands in for the row a real query would find, so the job is preloaded
+	r.DB.Callback().Query().After("gorm:query").Before("gorm:preload").Register("test:application", func(tx *gorm.DB) {
+		if a, ok := tx.Statement.Dest.(*models.Application); ok {
+			a.JobId = 5
+		}
+	})
+	_, err := r.GetApplication(context.Background(), 3)
+	if err != nil {
+		t.Fatalf("Repo.GetApplication() error = %v", err)
+	}
+	var job string
+	for _, s := range statements() {
+		if strings.HasPrefix(s, `SELECT * FROM "jobs"`) {
+			job = s
+		}
+	}
+	if job == "" {
+		t.Fatalf("job not loaded, statements %q", statements())
+	}
+	// Recruiters still see applications to a job that was deleted
+	if strings.Contains(job, "deleted_at") {
+		t.Errorf("job of the application loaded without deleted jobs: %s", job)
+	}
+}
@@ -1,14 +1,28 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"job-portal-api/internal/models"
+	"time"
 
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
-func (r *Repo) CreateCom(nc models.Company) (models.Company, error) {
-	err := r.DB.Create(&nc).Error
+func (r *Repo) CreateCom(nc models.Company, ownerId uint) (models.Company, error) {
+	err := r.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&nc).Error
+		if err != nil {
+			return err
+		}
+		owner := models.CompanyMember{
+			CompanyId: uint64(nc.ID),
+			UserId:    ownerId,
+			Role:      models.MemberOwner,
+		}
+		return tx.Create(&owner).Error
+	})
 	if err != nil {
 		log.Info().Err(err).Send()
 		return models.Company{}, errors.New("company cannot be created")
@@ -35,3 +49,159 @@ func (r *Repo) GetCompany(id uint64) (models.Company, error) {
 	}
 	return z, nil
 }
+
+// UpdateCompany replaces the details of a company, its match rules are only
+// replaced when c has some
+func (r *Repo) UpdateCompany(ctx context.Context, c models.Company) (models.Company, error) {
+	columns := []string{"company_name", "address", "domain"}
+	if c.MatchRules != nil {
+		columns = append(columns, "match_rules")
+	}
+	// A struct so the match rules go through their serializer, Select makes
+	// empty fields count too
+	res := r.DB.WithContext(ctx).Model(&models.Company{}).Where("id = ?", c.ID).
+		Select(columns).
+		Updates(&c)
+	if res.Error != nil {
+		log.Info().Err(res.Error).Send()
+		return models.Company{}, errors.New("company cannot be updated")
+	}
+	if res.RowsAffected == 0 {
+		return models.Company{}, gorm.ErrRecordNotFound
+	}
+	return r.GetCompany(uint64(c.ID))
+}
+
+// CountCompanyJobs counts the jobs of a company that are not deleted and not
+// finished, i.e. drafts, published and paused jobs
+func (r *Repo) CountCompanyJobs(ctx context.Context, cid uint64) (int64, error) {
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&models.Job{}).
+		Where("company_id = ? AND status NOT IN ?", cid, []string{models.JobClosed, models.JobExpired}).
+		Count(&count).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return 0, err
+	}
+	return count, nil
+}
+
+// CompanyJobIDs returns the ids of the jobs of a company
+func (r *Repo) CompanyJobIDs(ctx context.Context, cid uint64) ([]uint, error) {
+	var ids []uint
+	err := r.DB.WithContext(ctx).Model(&models.Job{}).Where("company_id = ?", cid).Pluck("id", &ids).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("jobs of the company cannot be found")
+	}
+	return ids, nil
+}
+
+// DeleteCompany soft deletes a company together with its jobs and returns
+// the ids of the deleted jobs. Company and jobs get the same deleted_at so a
+// restore brings back exactly the jobs that went with the company.
+func (r *Repo) DeleteCompany(ctx context.Context, cid uint64) ([]uint, error) {
+	var jobIds []uint
+	now := time.Now()
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.Company{}).Where("id = ?", cid).Update("deleted_at", now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		err := tx.Model(&models.Job{}).Where("company_id = ?", cid).Pluck("id", &jobIds).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.Job{}).Where("company_id = ?", cid).Update("deleted_at", now).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("company cannot be deleted")
+	}
+	return jobIds, nil
+}
+
+// RestoreCompany undoes DeleteCompany and returns the ids of the restored
+// jobs, gorm.ErrRecordNotFound when there is
+// no deleted company with the id
+func (r *Repo) RestoreCompany(ctx context.Context, cid uint64) (models.Company, []uint, error) {
+	var c models.Company
+	var jobIds []uint
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", cid).First(&c).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Model(&models.Job{}).
+			Where("company_id = ? AND deleted_at = ?", cid, c.DeletedAt).
+			Pluck("id", &jobIds).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Model(&models.Job{}).
+			Where("company_id = ? AND deleted_at = ?", cid, c.DeletedAt).
+			Update("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&c).Update("deleted_at", nil).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Company{}, nil, err
+	}
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.Company{}, nil, errors.New("company cannot be restored")
+	}
+	c.DeletedAt = gorm.DeletedAt{}
+	return c, jobIds, nil
+}
+
+func (r *Repo) AddCompanyMember(m models.CompanyMember) (models.CompanyMember, error) {
+	err := r.DB.Create(&m).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.CompanyMember{}, errors.New("member cannot be added")
+	}
+	return m, nil
+}
+
+func (r *Repo) GetCompanyMember(cid uint64, uid uint) (models.CompanyMember, error) {
+	var m models.CompanyMember
+	err := r.DB.Where("company_id = ? AND user_id = ?", cid, uid).First(&m).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.CompanyMember{}, err
+	}
+	return m, nil
+}
+
+func (r *Repo) GetCompanyMembers(cid uint64) ([]models.CompanyMember, error) {
+	var m []models.CompanyMember
+	err := r.DB.Where("company_id = ?", cid).Find(&m).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, err
+	}
+	return m, nil
+}
+
+func (r *Repo) RemoveCompanyMember(cid uint64, uid uint) error {
+	// Hard delete so the user can be added back later without clashing
+	// with the unique company/user index
+	res := r.DB.Unscoped().Where("company_id = ? AND user_id = ?", cid, uid).Delete(&models.CompanyMember{})
+	if res.Error != nil {
+		log.Info().Err(res.Error).Send()
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
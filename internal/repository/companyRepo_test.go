@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/models"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestRepo_UpdateCompany(t *testing.T) {
+	tests := []struct {
+		name      string
+		rules     *models.MatchRules
+		wantRules bool
+	}{
+		{name: "rules left out are kept", rules: nil, wantRules: false},
+		{name: "rules sent are replaced", rules: &models.MatchRules{RequiredSkillIDs: []uint{3}}, wantRules: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, statements := dryRunRepo(t)
+			// Dry runs change no rows, so the company is never found
+			_, err := r.UpdateCompany(context.Background(), models.Company{Model: gorm.Model{ID: 3}, CompanyName: "tek", MatchRules: tt.rules})
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				t.Fatalf("Repo.UpdateCompany() error = %v", err)
+			}
+			got := statements()
+			if len(got) != 1 || !strings.HasPrefix(got[0], `UPDATE "companies"`) {
+				t.Fatalf("Repo.UpdateCompany() ran %v", got)
+			}
+			if rules := strings.Contains(got[0], `"match_rules"`); rules != tt.wantRules {
+				t.Errorf("Repo.UpdateCompany() updates match_rules = %v, want %v: %s", rules, tt.wantRules, got[0])
+			}
+		})
+	}
+}
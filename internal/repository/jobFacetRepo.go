@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/models"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// CountJobFacets counts the jobs matching q per location, skill, work mode,
+// shift, job type, qualification and company. An empty search text counts
+// over the plain listing. The filter of a facet is left out when counting
+// that facet.
+func (r *Repo) CountJobFacets(ctx context.Context, q models.JobSearchQuery) (models.JobFacets, error) {
+	var f models.JobFacets
+	facets := []struct {
+		kind   models.TaxonomyKind
+		counts *[]models.FacetCount
+		// without drops the filter of the facet from the query
+		without func(q *models.JobListQuery)
+	}{
+		{models.LocationKind, &f.Locations, func(q *models.JobListQuery) { q.LocationIDs = nil }},
+		{models.SkillKind, &f.Skills, func(q *models.JobListQuery) { q.SkillIDs = nil }},
+		{models.WorkModeKind, &f.WorkModes, func(q *models.JobListQuery) { q.WorkModeIDs = nil }},
+		{models.ShiftKind, &f.Shifts, func(q *models.JobListQuery) { q.ShiftIDs = nil }},
+		{models.JobTypeKind, &f.JobTypes, func(q *models.JobListQuery) { q.JobTypeIDs = nil }},
+		{models.QualificationKind, &f.Qualifications, func(q *models.JobListQuery) { q.QualificationIDs = nil }},
+	}
+	db := r.DB.WithContext(ctx)
+	for _, t := range facets {
+		fq := q
+		t.without(&fq.JobListQuery)
+		matching := matchJobs(db.Model(&models.Job{}).Select("jobs.id"), fq)
+		counts := []models.FacetCount{}
+		err := db.Table(t.kind.JoinTable+" AS tagged").
+			Select("t.id, t."+t.kind.NameColumn+" AS name, count(*) AS count").
+			Joins("JOIN "+t.kind.Table+" t ON t.id = tagged."+t.kind.JoinColumn+" AND t.deleted_at IS NULL").
+			Where("tagged.job_id IN (?)", matching).
+			Group("t.id, t." + t.kind.NameColumn).
+			Order("count DESC, name").
+			Scan(&counts).Error
+		if err != nil {
+			log.Info().Err(err).Send()
+			return models.JobFacets{}, errors.New("facets cannot be counted")
+		}
+		*t.counts = counts
+	}
+
+	f.Companies = []models.FacetCount{}
+	err := companyFacet(db, q).Scan(&f.Companies).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.JobFacets{}, errors.New("facets cannot be counted")
+	}
+	return f, nil
+}
+
+// companyFacet counts the jobs matching q per company, without the company
+// filter of q. The filter stays when statuses other than published are asked
+// for, only members of that company may see its drafts, paused and finished
+// jobs.
+func companyFacet(db *gorm.DB, q models.JobSearchQuery) *gorm.DB {
+	published := true
+	for _, status := range q.Statuses {
+		if status != models.JobPublished {
+			published = false
+		}
+	}
+	if published {
+		q.CompanyId = nil
+	}
+	return matchJobs(db.Model(&models.Job{}), q).
+		Select("c.id, c.company_name AS name, count(*) AS count").
+		Joins("JOIN companies c ON c.id = jobs.company_id AND c.deleted_at IS NULL").
+		Group("c.id, c.company_name").
+		Order("count DESC, name")
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"job-portal-api/internal/models"
+	"strings"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func Test_companyFacet(t *testing.T) {
+	cid := uint64(3)
+	tests := []struct {
+		name       string
+		statuses   []string
+		wantScoped bool
+	}{
+		{name: "published jobs of every company", statuses: []string{models.JobPublished}, wantScoped: false},
+		{name: "drafts stay within the company", statuses: []string{models.JobPublished, models.JobDraft}, wantScoped: true},
+		{name: "closed jobs stay within the company", statuses: []string{models.JobClosed}, wantScoped: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, _ := dryRunRepo(t)
+			q := models.JobSearchQuery{JobListQuery: models.JobListQuery{CompanyId: &cid, Statuses: tt.statuses}}
+			query := r.DB.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				return companyFacet(tx, q).Find(&[]models.FacetCount{})
+			})
+			if got := strings.Contains(query, "jobs.company_id = 3"); got != tt.wantScoped {
+				t.Errorf("company facet scoped to the company = %v, want %v: %s", got, tt.wantScoped, query)
+			}
+		})
+	}
+}
@@ -1,10 +1,16 @@
 package repository
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"job-portal-api/internal/models"
+	"reflect"
+	"time"
 
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 //go:generate mockgen -source=jobRepo.go -destination=jobRepo_mock.go -package=repository
@@ -18,9 +24,11 @@ func (r *Repo) PostJob(nj models.Job) (models.Response, error) {
 	}
 	return models.Response{ID: uint64(nj.ID)}, nil
 }
+
+// GetJobsFromCompany returns the open jobs of a company
 func (r *Repo) GetJobsFromCompany(comapny_id uint64) ([]models.Job, error) {
 	var l []models.Job
-	vx := r.DB.Where("company_id=?", comapny_id)
+	vx := filterJobs(r.DB.Where("company_id=?", comapny_id), models.JobListQuery{Statuses: []string{models.JobPublished}})
 	err := vx.Find(&l).Error
 	if err != nil {
 		log.Info().Err(err).Send()
@@ -28,14 +36,128 @@ func (r *Repo) GetJobsFromCompany(comapny_id uint64) ([]models.Job, error) {
 	}
 	return l, nil
 }
-func (r *Repo) GetAllJobs() ([]models.Job, error) {
-	var a []models.Job
-	err := r.DB.Find(&a).Error
+
+// jobSortColumns maps the sort names of a job listing to columns
+var jobSortColumns = map[string]string{
+	"created_at": "jobs.created_at",
+	"budget":     "jobs.budget",
+	"experience": "jobs.min_experience",
+}
+
+// ListJobs returns one page of the jobs matching the filters in q and the
+// number of matching jobs on all pages. Page, PageSize, Sort and Order must be
+// set.
+func (r *Repo) ListJobs(ctx context.Context, q models.JobListQuery) ([]models.Job, int64, error) {
+	var total int64
+	err := filterJobs(r.DB.WithContext(ctx).Model(&models.Job{}), q).Count(&total).Error
 	if err != nil {
-		return nil, err
+		log.Info().Err(err).Send()
+		return nil, 0, errors.New("jobs cannot be listed")
 	}
-	return a, nil
+	jobs := []models.Job{}
+	// The id keeps the order stable between pages when the sort column ties
+	err = filterJobs(r.DB.WithContext(ctx), q).
+		Preload("Locations").
+		Preload("Skills").
+		Preload("WorkModes").
+		Preload("Qualifications").
+		Preload("Shifts").
+		Preload("JobTypes").
+		Order(jobSortColumns[q.Sort] + " " + q.Order).
+		Order("jobs.id " + q.Order).
+		Limit(q.PageSize).
+		Offset((q.Page - 1) * q.PageSize).
+		Find(&jobs).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, 0, errors.New("jobs cannot be listed")
+	}
+	return jobs, total, nil
 }
+
+// OpenJobs returns up to limit published jobs that take applications, newest
+// first, with their company and associations
+func (r *Repo) OpenJobs(ctx context.Context, limit int) ([]models.Job, error) {
+	jobs := []models.Job{}
+	err := r.DB.WithContext(ctx).
+		Preload("Comp").
+		Preload("Locations").
+		Preload("Skills").
+		Preload("WorkModes").
+		Preload("Qualifications").
+		Preload("Shifts").
+		Preload("JobTypes").
+		Where("status = ? AND (expires_at IS NULL OR expires_at > now())", models.JobPublished).
+		Order("created_at desc").
+		Order("id desc").
+		Limit(limit).
+		Find(&jobs).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("jobs cannot be listed")
+	}
+	return jobs, nil
+}
+
+// filterJobs adds the filters of a job listing to the query
+func filterJobs(tx *gorm.DB, q models.JobListQuery) *gorm.DB {
+	if len(q.Statuses) > 0 {
+		// Published jobs past their expiry date may not have been expired yet
+		tx = tx.Where("jobs.status IN ? AND (jobs.status <> ? OR jobs.expires_at IS NULL OR jobs.expires_at > now())",
+			q.Statuses, models.JobPublished)
+	}
+	if q.CompanyId != nil {
+		tx = tx.Where("jobs.company_id = ?", *q.CompanyId)
+	}
+	tagged := []struct {
+		kind models.TaxonomyKind
+		ids  []uint
+	}{
+		{models.LocationKind, q.LocationIDs},
+		{models.SkillKind, q.SkillIDs},
+		{models.WorkModeKind, q.WorkModeIDs},
+		{models.JobTypeKind, q.JobTypeIDs},
+		{models.ShiftKind, q.ShiftIDs},
+		{models.QualificationKind, q.QualificationIDs},
+	}
+	for _, t := range tagged {
+		if len(t.ids) == 0 {
+			continue
+		}
+		tx = tx.Where("EXISTS (SELECT 1 FROM "+t.kind.JoinTable+" jt WHERE jt.job_id = jobs.id AND jt."+t.kind.JoinColumn+" IN ?)", t.ids)
+	}
+	if q.MinExperience != nil {
+		tx = tx.Where("jobs.max_experience >= ?", *q.MinExperience)
+	}
+	if q.MaxExperience != nil {
+		tx = tx.Where("jobs.min_experience <= ?", *q.MaxExperience)
+	}
+	if q.NoticePeriod != nil {
+		tx = tx.Where("jobs.minimum_notice_period <= ? AND jobs.maximum_notice_period >= ?", *q.NoticePeriod, *q.NoticePeriod)
+	}
+	if q.MinSalary != nil || q.MaxSalary != nil {
+		// Both sides are compared as annual salaries
+		perYear := models.PayPeriodsPerYear[models.PayAnnual]
+		if q.SalaryPeriod != "" {
+			perYear = models.PayPeriodsPerYear[q.SalaryPeriod]
+		}
+		tx = tx.Where("jobs.salary_hidden = false AND jobs.salary_currency = ?", q.Currency)
+		if q.MinSalary != nil {
+			tx = tx.Where("jobs.salary_max * "+annualSalaryFactor+" >= ?", *q.MinSalary*perYear)
+		}
+		if q.MaxSalary != nil {
+			tx = tx.Where("jobs.salary_min * "+annualSalaryFactor+" <= ?", *q.MaxSalary*perYear)
+		}
+	}
+	return tx
+}
+
+// annualSalaryFactor turns the salary of a job into an annual one in SQL, see
+// models.PayPeriodsPerYear
+var annualSalaryFactor = fmt.Sprintf("(CASE jobs.salary_period WHEN '%s' THEN %v WHEN '%s' THEN %v ELSE 1 END)",
+	models.PayHourly, models.PayPeriodsPerYear[models.PayHourly],
+	models.PayMonthly, models.PayPeriodsPerYear[models.PayMonthly])
+
 func (r *Repo) GetOneJob(jid uint64) ([]models.Job, error) {
 	var q []models.Job
 	ax := r.DB.Where("id=?", jid)
@@ -46,15 +168,119 @@ func (r *Repo) GetOneJob(jid uint64) ([]models.Job, error) {
 	return q, nil
 }
 
-func (r *Repo) FetchJobData(jid uint64) (models.Job, error) {
+// GetJob returns a job with all its associations, gorm.ErrRecordNotFound when
+// there is no such job or it was deleted
+func (r *Repo) GetJob(ctx context.Context, jid uint64) (models.Job, error) {
+	var j models.Job
+	err := r.DB.WithContext(ctx).
+		Preload("Comp").
+		Preload("Locations").
+		Preload("Skills").
+		Preload("WorkModes").
+		Preload("Qualifications").
+		Preload("Shifts").
+		Preload("JobTypes").
+		Where("id = ?", jid).
+		First(&j).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.Job{}, err
+	}
+	return j, nil
+}
+
+// UpdateJob saves the job fields and replaces every association with the
+// ones set on j. The status is left as it is in the database, it only changes
+// through SetJobStatus and ExpireJobs.
+func (r *Repo) UpdateJob(ctx context.Context, j models.Job) (models.Job, error) {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Omit(clause.Associations, "status").Save(&j).Error
+		if err != nil {
+			return err
+		}
+		associations := map[string]any{
+			"Locations":      j.Locations,
+			"Skills":         j.Skills,
+			"WorkModes":      j.WorkModes,
+			"Qualifications": j.Qualifications,
+			"Shifts":         j.Shifts,
+			"JobTypes":       j.JobTypes,
+		}
+		for name, values := range associations {
+			if reflect.ValueOf(values).Len() == 0 {
+				err = tx.Model(&j).Association(name).Clear()
+			} else {
+				err = tx.Model(&j).Association(name).Replace(values)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.Job{}, errors.New("job update failed")
+	}
+	return r.GetJob(ctx, uint64(j.ID))
+}
+
+// SetJobStatus moves a job from one status to another and stores its expiry
+// date, gorm.ErrRecordNotFound when the job is gone or no longer in from
+func (r *Repo) SetJobStatus(ctx context.Context, jid uint64, from, to string, expiresAt *time.Time) error {
+	res := r.DB.WithContext(ctx).Model(&models.Job{}).
+		Where("id = ? AND status = ?", jid, from).
+		Updates(map[string]any{"status": to, "expires_at": expiresAt})
+	if res.Error != nil {
+		log.Info().Err(res.Error).Send()
+		return errors.New("job status cannot be changed")
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ExpireJobs moves the published and paused jobs whose expiry date is before
+// now to expired and returns their ids
+func (r *Repo) ExpireJobs(ctx context.Context, now time.Time) ([]uint, error) {
+	var ids []uint
+	err := r.DB.WithContext(ctx).Raw("UPDATE jobs SET status = ?, updated_at = ? "+
+		"WHERE status IN ? AND expires_at <= ? AND deleted_at IS NULL RETURNING id",
+		models.JobExpired, now, []string{models.JobPublished, models.JobPaused}, now).Scan(&ids).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("jobs cannot be expired")
+	}
+	return ids, nil
+}
+
+// DeleteJob soft deletes a job, gorm.ErrRecordNotFound when there is no such job
+func (r *Repo) DeleteJob(ctx context.Context, jid uint64) error {
+	result := r.DB.WithContext(ctx).Delete(&models.Job{}, jid)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return errors.New("job deletion failed")
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FetchJobData returns a job with what it is matched on, gorm.ErrRecordNotFound
+// when there is no such job
+func (r *Repo) FetchJobData(ctx context.Context, jid uint64) (models.Job, error) {
 	var j models.Job
-	result := r.DB.Preload("Comp").
+	result := r.DB.WithContext(ctx).Preload("Comp").
 		Preload("Locations").
 		Preload("Skills").
+		Preload("WorkModes").
 		Preload("Qualifications").
 		Preload("Shifts").
+		Preload("JobTypes").
 		Where("id = ?", jid).
-		Find(&j)
+		First(&j)
 	if result.Error != nil {
 
 		log.Info().Err(result.Error).Send()
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"fmt"
+	"job-portal-api/internal/models"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// legacySalaryCurrency is assumed for old salaries that name no currency
+const legacySalaryCurrency = "INR"
+
+// MigrateSalaries converts the free-form salary column jobs used to have into
+// the structured salary fields. Salaries that cannot be read are hidden. The
+// old text is kept in salary_legacy, the migration runs once since the salary
+// column is gone afterwards.
+func MigrateSalaries(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.Job{}, "salary") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		var rows []struct {
+			ID     uint
+			Salary *string
+		}
+		// Deleted jobs are converted too, they can be restored
+		err := tx.Table("jobs").Select("id, salary").Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			var text string
+			if row.Salary != nil {
+				text = *row.Salary
+			}
+			err = tx.Table("jobs").Where("id = ?", row.ID).Updates(legacySalaryUpdate(text)).Error
+			if err != nil {
+				return fmt.Errorf("converting salary of job %d %w", row.ID, err)
+			}
+		}
+		return tx.Migrator().RenameColumn(&models.Job{}, "salary", "salary_legacy")
+	})
+}
+
+// legacySalaryUpdate is the change MigrateSalaries makes to a job with the
+// old salary text. A salary that cannot be read only hides the salary, the
+// other salary columns are left alone.
+func legacySalaryUpdate(text string) map[string]any {
+	min, max, currency, period, ok := parseLegacySalary(text)
+	if !ok {
+		return map[string]any{"salary_hidden": true}
+	}
+	return map[string]any{
+		"salary_min":      min,
+		"salary_max":      max,
+		"salary_currency": currency,
+		"salary_period":   period,
+		"salary_hidden":   false,
+	}
+}
+
+var (
+	salaryAmount = regexp.MustCompile(`(\d[\d,]*(?:\.\d+)?)\s*(k\b)?`)
+	salaryHourly = regexp.MustCompile(`hour|\bhr\b|/hr|\bph\b`)
+	salaryMonth  = regexp.MustCompile(`month|\bpm\b|/mo\b`)
+	salaryLakh   = regexp.MustCompile(`lpa|lakh|\blac\b|\blacs\b`)
+)
+
+// parseLegacySalary reads salaries the way recruiters used to type them, e.g.
+// "85000", "10,000", "5-10 LPA", "$40/hour" or "30k - 40k per month". ok is
+// false when no amount could be found.
+func parseLegacySalary(s string) (min, max float64, currency, period string, ok bool) {
+	text := strings.ToLower(strings.TrimSpace(s))
+
+	currency = legacySalaryCurrency
+	switch {
+	case strings.Contains(text, "$") || strings.Contains(text, "usd"):
+		currency = "USD"
+	case strings.Contains(text, "€") || strings.Contains(text, "eur"):
+		currency = "EUR"
+	case strings.Contains(text, "£") || strings.Contains(text, "gbp"):
+		currency = "GBP"
+	}
+
+	period = models.PayAnnual
+	switch {
+	case salaryHourly.MatchString(text):
+		period = models.PayHourly
+	case salaryMonth.MatchString(text):
+		period = models.PayMonthly
+	}
+
+	unit := 1.0
+	if salaryLakh.MatchString(text) {
+		unit = 100000
+	}
+
+	var amounts []float64
+	for _, m := range salaryAmount.FindAllStringSubmatch(text, 2) {
+		v, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+		if err != nil {
+			return 0, 0, currency, period, false
+		}
+		if m[2] != "" {
+			v *= 1000
+		}
+		amounts = append(amounts, v*unit)
+	}
+	switch len(amounts) {
+	case 0:
+		return 0, 0, currency, period, false
+	case 1:
+		min, max = amounts[0], amounts[0]
+	default:
+		min, max = amounts[0], amounts[1]
+		if max < min {
+			min, max = max, min
+		}
+	}
+	if min <= 0 {
+		return 0, 0, currency, period, false
+	}
+	return min, max, currency, period, true
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"job-portal-api/internal/models"
+	"reflect"
+	"testing"
+)
+
+func Test_parseLegacySalary(t *testing.T) {
+	tests := []struct {
+		text         string
+		wantMin      float64
+		wantMax      float64
+		wantCurrency string
+		wantPeriod   string
+		wantOk       bool
+	}{
+		{text: "85000", wantMin: 85000, wantMax: 85000, wantCurrency: "INR", wantPeriod: models.PayAnnual, wantOk: true},
+		{text: "10,000", wantMin: 10000, wantMax: 10000, wantCurrency: "INR", wantPeriod: models.PayAnnual, wantOk: true},
+		{text: "5-10 LPA", wantMin: 500000, wantMax: 1000000, wantCurrency: "INR", wantPeriod: models.PayAnnual, wantOk: true},
+		{text: "$40/hour", wantMin: 40, wantMax: 40, wantCurrency: "USD", wantPeriod: models.PayHourly, wantOk: true},
+		{text: "30k - 40k per month", wantMin: 30000, wantMax: 40000, wantCurrency: "INR", wantPeriod: models.PayMonthly, wantOk: true},
+		{text: "40k-30k", wantMin: 30000, wantMax: 40000, wantCurrency: "INR", wantPeriod: models.PayAnnual, wantOk: true},
+		{text: "€ 3,500.50 pm", wantMin: 3500.5, wantMax: 3500.5, wantCurrency: "EUR", wantPeriod: models.PayMonthly, wantOk: true},
+		{text: "negotiable", wantCurrency: "INR", wantPeriod: models.PayAnnual},
+		{text: "0", wantCurrency: "INR", wantPeriod: models.PayAnnual},
+		{text: "", wantCurrency: "INR", wantPeriod: models.PayAnnual},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			min, max, currency, period, ok := parseLegacySalary(tt.text)
+			if min != tt.wantMin || max != tt.wantMax || currency != tt.wantCurrency || period != tt.wantPeriod || ok != tt.wantOk {
+				t.Errorf("parseLegacySalary(%q) = %v, %v, %q, %q, %v, want %v, %v, %q, %q, %v", tt.text,
+					min, max, currency, period, ok, tt.wantMin, tt.wantMax, tt.wantCurrency, tt.wantPeriod, tt.wantOk)
+			}
+		})
+	}
+}
+
+func Test_legacySalaryUpdate(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want map[string]any
+	}{
+		{name: "salary read", text: "5-10 LPA",
+			want: map[string]any{"salary_min": 500000.0, "salary_max": 1000000.0, "salary_currency": "INR", "salary_period": models.PayAnnual, "salary_hidden": false},
+		},
+		{name: "unreadable salary only hidden", text: "as per industry standards",
+			want: map[string]any{"salary_hidden": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := legacySalaryUpdate(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("legacySalaryUpdate(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/models"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// tsQuery turns the search text into a tsquery, quoted phrases, "or" and
+// -excluded words are understood
+const tsQuery = "websearch_to_tsquery('english', ?)"
+
+// escapedHTML is the column with &, < and > escaped, so only the <mark> tags
+// ts_headline adds are markup in the highlights
+func escapedHTML(column string) string {
+	return "replace(replace(replace(coalesce(" + column + ", ''), '&', '&amp;'), '<', '&lt;'), '>', '&gt;')"
+}
+
+// MigrateJobSearch adds the search_vector column searched by SearchJobs. It is
+// generated by postgres from the title (weight A) and the description
+// (weight B), so it never needs to be written by the application.
+func MigrateJobSearch(db *gorm.DB) error {
+	err := db.Exec(`ALTER TABLE jobs ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+		setweight(to_tsvector('english', coalesce(job_title, '')), 'A') ||
+		setweight(to_tsvector('english', coalesce(job_description, '')), 'B')) STORED`).Error
+	if err != nil {
+		return err
+	}
+	return db.Exec("CREATE INDEX IF NOT EXISTS idx_jobs_search_vector ON jobs USING GIN (search_vector)").Error
+}
+
+// SearchJobs returns one page of the jobs matching the search text and the
+// listing filters in q, and the number of matching jobs on all pages. Page,
+// PageSize and Order must be set, an empty Sort orders by relevance.
+func (r *Repo) SearchJobs(ctx context.Context, q models.JobSearchQuery) ([]models.JobSearchHit, int64, error) {
+	var total int64
+	err := matchJobs(r.DB.WithContext(ctx).Model(&models.Job{}), q).Count(&total).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, 0, errors.New("jobs cannot be searched")
+	}
+
+	var rows []struct {
+		ID             uint
+		Rank           float64
+		TitleHighlight string
+		Snippet        string
+	}
+	tx := matchJobs(r.DB.WithContext(ctx).Model(&models.Job{}), q).
+		Select("jobs.id, "+
+			"ts_rank(jobs.search_vector, "+tsQuery+") AS rank, "+
+			"ts_headline('english', "+escapedHTML("jobs.job_title")+", "+tsQuery+", 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>') AS title_highlight, "+
+			"ts_headline('english', "+escapedHTML("jobs.job_description")+", "+tsQuery+", 'StartSel=<mark>, StopSel=</mark>, MinWords=15, MaxWords=35') AS snippet",
+			q.Q, q.Q, q.Q)
+	if q.Sort == "" {
+		tx = tx.Order("rank DESC")
+	} else {
+		tx = tx.Order(jobSortColumns[q.Sort] + " " + q.Order)
+	}
+	err = tx.Order("jobs.id " + q.Order).
+		Limit(q.PageSize).
+		Offset((q.Page - 1) * q.PageSize).
+		Scan(&rows).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, 0, errors.New("jobs cannot be searched")
+	}
+	if len(rows) == 0 {
+		return []models.JobSearchHit{}, total, nil
+	}
+
+	ids := make([]uint, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	var jobs []models.Job
+	err = r.DB.WithContext(ctx).
+		Preload("Locations").
+		Preload("Skills").
+		Preload("WorkModes").
+		Preload("Qualifications").
+		Preload("Shifts").
+		Preload("JobTypes").
+		Where("id IN ?", ids).
+		Find(&jobs).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, 0, errors.New("jobs cannot be searched")
+	}
+	byId := make(map[uint]models.Job, len(jobs))
+	for _, j := range jobs {
+		byId[j.ID] = j
+	}
+	hits := make([]models.JobSearchHit, 0, len(rows))
+	for _, row := range rows {
+		j, ok := byId[row.ID]
+		if !ok {
+			// Deleted between the two queries
+			continue
+		}
+		hits = append(hits, models.JobSearchHit{Job: j, Rank: row.Rank, TitleHighlight: row.TitleHighlight, Snippet: row.Snippet})
+	}
+	return hits, total, nil
+}
+
+// matchJobs adds the listing filters and, when there is search text, the text
+// search to the query
+func matchJobs(tx *gorm.DB, q models.JobSearchQuery) *gorm.DB {
+	tx = filterJobs(tx, q.JobListQuery)
+	if q.Q != "" {
+		tx = tx.Where("jobs.search_vector @@ "+tsQuery, q.Q)
+	}
+	return tx
+}
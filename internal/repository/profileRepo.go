@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/models"
+	"reflect"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CreateProfile stores the profile of a user, gorm.ErrDuplicatedKey when the
+// user already has one
+func (r *Repo) CreateProfile(ctx context.Context, p models.CandidateProfile) (models.CandidateProfile, error) {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&p)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrDuplicatedKey
+		}
+		return replaceProfileAssociations(tx, &p)
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return models.CandidateProfile{}, err
+	}
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.CandidateProfile{}, errors.New("profile cannot be saved")
+	}
+	return r.GetProfile(ctx, p.UserId)
+}
+
+// GetProfile loads the profile of a user with the items it refers to
+func (r *Repo) GetProfile(ctx context.Context, uid uint) (models.CandidateProfile, error) {
+	var p models.CandidateProfile
+	err := r.DB.WithContext(ctx).
+		Preload("Locations").
+		Preload("Skills").
+		Preload("WorkModes").
+		Preload("Qualifications").
+		Preload("Shifts").
+		Preload("JobTypes").
+		Where("user_id = ?", uid).
+		First(&p).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.CandidateProfile{}, err
+	}
+	return p, nil
+}
+
+// UpdateProfile saves the profile fields and replaces every association with
+// the ones set on p
+func (r *Repo) UpdateProfile(ctx context.Context, p models.CandidateProfile) (models.CandidateProfile, error) {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Omit(clause.Associations).Save(&p).Error
+		if err != nil {
+			return err
+		}
+		return replaceProfileAssociations(tx, &p)
+	})
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.CandidateProfile{}, errors.New("profile update failed")
+	}
+	return r.GetProfile(ctx, p.UserId)
+}
+
+func replaceProfileAssociations(tx *gorm.DB, p *models.CandidateProfile) error {
+	associations := map[string]any{
+		"Locations":      p.Locations,
+		"Skills":         p.Skills,
+		"WorkModes":      p.WorkModes,
+		"Qualifications": p.Qualifications,
+		"Shifts":         p.Shifts,
+		"JobTypes":       p.JobTypes,
+	}
+	for name, values := range associations {
+		var err error
+		if reflect.ValueOf(values).Len() == 0 {
+			err = tx.Model(p).Association(name).Clear()
+		} else {
+			err = tx.Model(p).Association(name).Replace(values)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteProfile removes the profile of a user for good so a new one can be
+// created, gorm.ErrRecordNotFound when there is none
+func (r *Repo) DeleteProfile(ctx context.Context, uid uint) error {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var p models.CandidateProfile
+		err := tx.Where("user_id = ?", uid).First(&p).Error
+		if err != nil {
+			return err
+		}
+		return tx.Select(clause.Associations).Unscoped().Delete(&p).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err != nil {
+		log.Info().Err(err).Send()
+		return errors.New("profile cannot be deleted")
+	}
+	return nil
+}
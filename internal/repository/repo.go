@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"job-portal-api/internal/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -15,17 +16,64 @@ type Repo struct {
 type UserRepo interface {
 	CreateUser(ctx context.Context, nu models.User) (models.User, error)
 	CheckEmail(ctx context.Context, email string) (models.User, error)
+	GetUserById(ctx context.Context, uid uint) (models.User, error)
+	EmailExists(ctx context.Context, email string) (bool, error)
+	MarkUserVerified(ctx context.Context, uid uint) error
+	SetUserRole(ctx context.Context, uid uint, role string) error
 
 	PostJob(nj models.Job) (models.Response, error)
 	GetJobsFromCompany(comapny_id uint64) ([]models.Job, error)
-	GetAllJobs() ([]models.Job, error)
+	ListJobs(ctx context.Context, q models.JobListQuery) ([]models.Job, int64, error)
+	OpenJobs(ctx context.Context, limit int) ([]models.Job, error)
+	SearchJobs(ctx context.Context, q models.JobSearchQuery) ([]models.JobSearchHit, int64, error)
+	CountJobFacets(ctx context.Context, q models.JobSearchQuery) (models.JobFacets, error)
+	SetJobStatus(ctx context.Context, jid uint64, from, to string, expiresAt *time.Time) error
+	ExpireJobs(ctx context.Context, now time.Time) ([]uint, error)
+
+	CreateApplication(ctx context.Context, a models.Application) (models.Application, error)
+	GetApplication(ctx context.Context, aid uint) (models.Application, error)
+	ListJobApplications(ctx context.Context, jid uint64, stages []string) ([]models.Application, error)
+	ListUserApplications(ctx context.Context, uid uint) ([]models.Application, error)
+	MoveApplication(ctx context.Context, change models.ApplicationStageChange) error
+	ApplicationHistory(ctx context.Context, aid uint) ([]models.ApplicationStageChange, error)
+	CreateResume(ctx context.Context, rs models.Resume) (models.Resume, error)
+	GetResume(ctx context.Context, id uint) (models.Resume, error)
+	ListUserResumes(ctx context.Context, uid uint) ([]models.Resume, error)
+	ListApplicationResumes(ctx context.Context, aid uint) ([]models.Resume, error)
+	DeleteResume(ctx context.Context, id uint) error
+	CreateProfile(ctx context.Context, p models.CandidateProfile) (models.CandidateProfile, error)
+	GetProfile(ctx context.Context, uid uint) (models.CandidateProfile, error)
+	UpdateProfile(ctx context.Context, p models.CandidateProfile) (models.CandidateProfile, error)
+	DeleteProfile(ctx context.Context, uid uint) error
 	GetOneJob(id uint64) ([]models.Job, error)
+	GetJob(ctx context.Context, jid uint64) (models.Job, error)
+	UpdateJob(ctx context.Context, j models.Job) (models.Job, error)
+	DeleteJob(ctx context.Context, jid uint64) error
 
-	CreateCom(nc models.Company) (models.Company, error)
+	CreateCom(nc models.Company, ownerId uint) (models.Company, error)
 	GetAllTheCompanies() ([]models.Company, error)
 	GetCompany(id uint64) (models.Company, error)
+	UpdateCompany(ctx context.Context, c models.Company) (models.Company, error)
+	CountCompanyJobs(ctx context.Context, cid uint64) (int64, error)
+	CompanyJobIDs(ctx context.Context, cid uint64) ([]uint, error)
+	DeleteCompany(ctx context.Context, cid uint64) ([]uint, error)
+	RestoreCompany(ctx context.Context, cid uint64) (models.Company, []uint, error)
+
+	AddCompanyMember(m models.CompanyMember) (models.CompanyMember, error)
+	GetCompanyMember(cid uint64, uid uint) (models.CompanyMember, error)
+	GetCompanyMembers(cid uint64) ([]models.CompanyMember, error)
+	RemoveCompanyMember(cid uint64, uid uint) error
+
+	ListTaxonomy(ctx context.Context, kind models.TaxonomyKind) ([]models.TaxonomyItem, error)
+	GetTaxonomy(ctx context.Context, kind models.TaxonomyKind, id uint) (models.TaxonomyItem, error)
+	CreateTaxonomy(ctx context.Context, kind models.TaxonomyKind, name string) (models.TaxonomyItem, error)
+	RenameTaxonomy(ctx context.Context, kind models.TaxonomyKind, id uint, name string) (models.TaxonomyItem, error)
+	CountTaxonomyUsage(ctx context.Context, kind models.TaxonomyKind, id uint) (int64, error)
+	DeleteTaxonomy(ctx context.Context, kind models.TaxonomyKind, id uint) error
+	MergeTaxonomy(ctx context.Context, kind models.TaxonomyKind, targetId uint, sourceIds []uint) ([]uint, error)
+	MissingTaxonomyIDs(ctx context.Context, kind models.TaxonomyKind, ids []uint) ([]uint, error)
 
-	FetchJobData(jid uint64) (models.Job, error)
+	FetchJobData(ctx context.Context, jid uint64) (models.Job, error)
 	UpdatePwdInDb(user models.User)error
 }
 
@@ -5,7 +5,6 @@
 //
 //	mockgen -source=repo.go -destination=repo_mock.go -package=repository
 //
-
 // Package repository is a generated GoMock package.
 package repository
 
@@ -13,6 +12,7 @@ import (
 	context "context"
 	models "job-portal-api/internal/models"
 	reflect "reflect"
+	time "time"
 
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,6 +40,36 @@ func (m *MockUserRepo) EXPECT() *MockUserRepoMockRecorder {
 	return m.recorder
 }
 
+// AddCompanyMember mocks base method.
+func (m_2 *MockUserRepo) AddCompanyMember(m models.CompanyMember) (models.CompanyMember, error) {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "AddCompanyMember", m)
+	ret0, _ := ret[0].(models.CompanyMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCompanyMember indicates an expected call of AddCompanyMember.
+func (mr *MockUserRepoMockRecorder) AddCompanyMember(m any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCompanyMember", reflect.TypeOf((*MockUserRepo)(nil).AddCompanyMember), m)
+}
+
+// ApplicationHistory mocks base method.
+func (m *MockUserRepo) ApplicationHistory(ctx context.Context, aid uint) ([]models.ApplicationStageChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplicationHistory", ctx, aid)
+	ret0, _ := ret[0].([]models.ApplicationStageChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplicationHistory indicates an expected call of ApplicationHistory.
+func (mr *MockUserRepoMockRecorder) ApplicationHistory(ctx, aid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplicationHistory", reflect.TypeOf((*MockUserRepo)(nil).ApplicationHistory), ctx, aid)
+}
+
 // CheckEmail mocks base method.
 func (m *MockUserRepo) CheckEmail(ctx context.Context, email string) (models.User, error) {
 	m.ctrl.T.Helper()
@@ -55,19 +85,139 @@ func (mr *MockUserRepoMockRecorder) CheckEmail(ctx, email any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckEmail", reflect.TypeOf((*MockUserRepo)(nil).CheckEmail), ctx, email)
 }
 
+// CompanyJobIDs mocks base method.
+func (m *MockUserRepo) CompanyJobIDs(ctx context.Context, cid uint64) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompanyJobIDs", ctx, cid)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompanyJobIDs indicates an expected call of CompanyJobIDs.
+func (mr *MockUserRepoMockRecorder) CompanyJobIDs(ctx, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompanyJobIDs", reflect.TypeOf((*MockUserRepo)(nil).CompanyJobIDs), ctx, cid)
+}
+
+// CountCompanyJobs mocks base method.
+func (m *MockUserRepo) CountCompanyJobs(ctx context.Context, cid uint64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCompanyJobs", ctx, cid)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCompanyJobs indicates an expected call of CountCompanyJobs.
+func (mr *MockUserRepoMockRecorder) CountCompanyJobs(ctx, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCompanyJobs", reflect.TypeOf((*MockUserRepo)(nil).CountCompanyJobs), ctx, cid)
+}
+
+// CountJobFacets mocks base method.
+func (m *MockUserRepo) CountJobFacets(ctx context.Context, q models.JobSearchQuery) (models.JobFacets, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountJobFacets", ctx, q)
+	ret0, _ := ret[0].(models.JobFacets)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountJobFacets indicates an expected call of CountJobFacets.
+func (mr *MockUserRepoMockRecorder) CountJobFacets(ctx, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountJobFacets", reflect.TypeOf((*MockUserRepo)(nil).CountJobFacets), ctx, q)
+}
+
+// CountTaxonomyUsage mocks base method.
+func (m *MockUserRepo) CountTaxonomyUsage(ctx context.Context, kind models.TaxonomyKind, id uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTaxonomyUsage", ctx, kind, id)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTaxonomyUsage indicates an expected call of CountTaxonomyUsage.
+func (mr *MockUserRepoMockRecorder) CountTaxonomyUsage(ctx, kind, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTaxonomyUsage", reflect.TypeOf((*MockUserRepo)(nil).CountTaxonomyUsage), ctx, kind, id)
+}
+
+// CreateApplication mocks base method.
+func (m *MockUserRepo) CreateApplication(ctx context.Context, a models.Application) (models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateApplication", ctx, a)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateApplication indicates an expected call of CreateApplication.
+func (mr *MockUserRepoMockRecorder) CreateApplication(ctx, a any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateApplication", reflect.TypeOf((*MockUserRepo)(nil).CreateApplication), ctx, a)
+}
+
 // CreateCom mocks base method.
-func (m *MockUserRepo) CreateCom(nc models.Company) (models.Company, error) {
+func (m *MockUserRepo) CreateCom(nc models.Company, ownerId uint) (models.Company, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateCom", nc)
+	ret := m.ctrl.Call(m, "CreateCom", nc, ownerId)
 	ret0, _ := ret[0].(models.Company)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateCom indicates an expected call of CreateCom.
-func (mr *MockUserRepoMockRecorder) CreateCom(nc any) *gomock.Call {
+func (mr *MockUserRepoMockRecorder) CreateCom(nc, ownerId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCom", reflect.TypeOf((*MockUserRepo)(nil).CreateCom), nc)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCom", reflect.TypeOf((*MockUserRepo)(nil).CreateCom), nc, ownerId)
+}
+
+// CreateProfile mocks base method.
+func (m *MockUserRepo) CreateProfile(ctx context.Context, p models.CandidateProfile) (models.CandidateProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProfile", ctx, p)
+	ret0, _ := ret[0].(models.CandidateProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProfile indicates an expected call of CreateProfile.
+func (mr *MockUserRepoMockRecorder) CreateProfile(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProfile", reflect.TypeOf((*MockUserRepo)(nil).CreateProfile), ctx, p)
+}
+
+// CreateResume mocks base method.
+func (m *MockUserRepo) CreateResume(ctx context.Context, rs models.Resume) (models.Resume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateResume", ctx, rs)
+	ret0, _ := ret[0].(models.Resume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateResume indicates an expected call of CreateResume.
+func (mr *MockUserRepoMockRecorder) CreateResume(ctx, rs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResume", reflect.TypeOf((*MockUserRepo)(nil).CreateResume), ctx, rs)
+}
+
+// CreateTaxonomy mocks base method.
+func (m *MockUserRepo) CreateTaxonomy(ctx context.Context, kind models.TaxonomyKind, name string) (models.TaxonomyItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTaxonomy", ctx, kind, name)
+	ret0, _ := ret[0].(models.TaxonomyItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTaxonomy indicates an expected call of CreateTaxonomy.
+func (mr *MockUserRepoMockRecorder) CreateTaxonomy(ctx, kind, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTaxonomy", reflect.TypeOf((*MockUserRepo)(nil).CreateTaxonomy), ctx, kind, name)
 }
 
 // CreateUser mocks base method.
@@ -85,34 +235,120 @@ func (mr *MockUserRepoMockRecorder) CreateUser(ctx, nu any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockUserRepo)(nil).CreateUser), ctx, nu)
 }
 
-// FetchJobData mocks base method.
-func (m *MockUserRepo) FetchJobData(jid uint64) (models.Job, error) {
+// DeleteCompany mocks base method.
+func (m *MockUserRepo) DeleteCompany(ctx context.Context, cid uint64) ([]uint, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FetchJobData", jid)
-	ret0, _ := ret[0].(models.Job)
+	ret := m.ctrl.Call(m, "DeleteCompany", ctx, cid)
+	ret0, _ := ret[0].([]uint)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// FetchJobData indicates an expected call of FetchJobData.
-func (mr *MockUserRepoMockRecorder) FetchJobData(jid any) *gomock.Call {
+// DeleteCompany indicates an expected call of DeleteCompany.
+func (mr *MockUserRepoMockRecorder) DeleteCompany(ctx, cid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchJobData", reflect.TypeOf((*MockUserRepo)(nil).FetchJobData), jid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCompany", reflect.TypeOf((*MockUserRepo)(nil).DeleteCompany), ctx, cid)
 }
 
-// GetAllJobs mocks base method.
-func (m *MockUserRepo) GetAllJobs() ([]models.Job, error) {
+// DeleteJob mocks base method.
+func (m *MockUserRepo) DeleteJob(ctx context.Context, jid uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllJobs")
-	ret0, _ := ret[0].([]models.Job)
+	ret := m.ctrl.Call(m, "DeleteJob", ctx, jid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteJob indicates an expected call of DeleteJob.
+func (mr *MockUserRepoMockRecorder) DeleteJob(ctx, jid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteJob", reflect.TypeOf((*MockUserRepo)(nil).DeleteJob), ctx, jid)
+}
+
+// DeleteProfile mocks base method.
+func (m *MockUserRepo) DeleteProfile(ctx context.Context, uid uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProfile", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProfile indicates an expected call of DeleteProfile.
+func (mr *MockUserRepoMockRecorder) DeleteProfile(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProfile", reflect.TypeOf((*MockUserRepo)(nil).DeleteProfile), ctx, uid)
+}
+
+// DeleteResume mocks base method.
+func (m *MockUserRepo) DeleteResume(ctx context.Context, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteResume", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteResume indicates an expected call of DeleteResume.
+func (mr *MockUserRepoMockRecorder) DeleteResume(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResume", reflect.TypeOf((*MockUserRepo)(nil).DeleteResume), ctx, id)
+}
+
+// DeleteTaxonomy mocks base method.
+func (m *MockUserRepo) DeleteTaxonomy(ctx context.Context, kind models.TaxonomyKind, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTaxonomy", ctx, kind, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTaxonomy indicates an expected call of DeleteTaxonomy.
+func (mr *MockUserRepoMockRecorder) DeleteTaxonomy(ctx, kind, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTaxonomy", reflect.TypeOf((*MockUserRepo)(nil).DeleteTaxonomy), ctx, kind, id)
+}
+
+// EmailExists mocks base method.
+func (m *MockUserRepo) EmailExists(ctx context.Context, email string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EmailExists", ctx, email)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EmailExists indicates an expected call of EmailExists.
+func (mr *MockUserRepoMockRecorder) EmailExists(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EmailExists", reflect.TypeOf((*MockUserRepo)(nil).EmailExists), ctx, email)
+}
+
+// ExpireJobs mocks base method.
+func (m *MockUserRepo) ExpireJobs(ctx context.Context, now time.Time) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireJobs", ctx, now)
+	ret0, _ := ret[0].([]uint)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetAllJobs indicates an expected call of GetAllJobs.
-func (mr *MockUserRepoMockRecorder) GetAllJobs() *gomock.Call {
+// ExpireJobs indicates an expected call of ExpireJobs.
+func (mr *MockUserRepoMockRecorder) ExpireJobs(ctx, now any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireJobs", reflect.TypeOf((*MockUserRepo)(nil).ExpireJobs), ctx, now)
+}
+
+// FetchJobData mocks base method.
+func (m *MockUserRepo) FetchJobData(ctx context.Context, jid uint64) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FetchJobData", ctx, jid)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FetchJobData indicates an expected call of FetchJobData.
+func (mr *MockUserRepoMockRecorder) FetchJobData(ctx, jid any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllJobs", reflect.TypeOf((*MockUserRepo)(nil).GetAllJobs))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FetchJobData", reflect.TypeOf((*MockUserRepo)(nil).FetchJobData), ctx, jid)
 }
 
 // GetAllTheCompanies mocks base method.
@@ -130,6 +366,21 @@ func (mr *MockUserRepoMockRecorder) GetAllTheCompanies() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTheCompanies", reflect.TypeOf((*MockUserRepo)(nil).GetAllTheCompanies))
 }
 
+// GetApplication mocks base method.
+func (m *MockUserRepo) GetApplication(ctx context.Context, aid uint) (models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApplication", ctx, aid)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApplication indicates an expected call of GetApplication.
+func (mr *MockUserRepoMockRecorder) GetApplication(ctx, aid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApplication", reflect.TypeOf((*MockUserRepo)(nil).GetApplication), ctx, aid)
+}
+
 // GetCompany mocks base method.
 func (m *MockUserRepo) GetCompany(id uint64) (models.Company, error) {
 	m.ctrl.T.Helper()
@@ -145,6 +396,51 @@ func (mr *MockUserRepoMockRecorder) GetCompany(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompany", reflect.TypeOf((*MockUserRepo)(nil).GetCompany), id)
 }
 
+// GetCompanyMember mocks base method.
+func (m *MockUserRepo) GetCompanyMember(cid uint64, uid uint) (models.CompanyMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCompanyMember", cid, uid)
+	ret0, _ := ret[0].(models.CompanyMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCompanyMember indicates an expected call of GetCompanyMember.
+func (mr *MockUserRepoMockRecorder) GetCompanyMember(cid, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompanyMember", reflect.TypeOf((*MockUserRepo)(nil).GetCompanyMember), cid, uid)
+}
+
+// GetCompanyMembers mocks base method.
+func (m *MockUserRepo) GetCompanyMembers(cid uint64) ([]models.CompanyMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCompanyMembers", cid)
+	ret0, _ := ret[0].([]models.CompanyMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCompanyMembers indicates an expected call of GetCompanyMembers.
+func (mr *MockUserRepoMockRecorder) GetCompanyMembers(cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompanyMembers", reflect.TypeOf((*MockUserRepo)(nil).GetCompanyMembers), cid)
+}
+
+// GetJob mocks base method.
+func (m *MockUserRepo) GetJob(ctx context.Context, jid uint64) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJob", ctx, jid)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJob indicates an expected call of GetJob.
+func (mr *MockUserRepoMockRecorder) GetJob(ctx, jid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJob", reflect.TypeOf((*MockUserRepo)(nil).GetJob), ctx, jid)
+}
+
 // GetJobsFromCompany mocks base method.
 func (m *MockUserRepo) GetJobsFromCompany(comapny_id uint64) ([]models.Job, error) {
 	m.ctrl.T.Helper()
@@ -175,6 +471,230 @@ func (mr *MockUserRepoMockRecorder) GetOneJob(id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOneJob", reflect.TypeOf((*MockUserRepo)(nil).GetOneJob), id)
 }
 
+// GetProfile mocks base method.
+func (m *MockUserRepo) GetProfile(ctx context.Context, uid uint) (models.CandidateProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", ctx, uid)
+	ret0, _ := ret[0].(models.CandidateProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockUserRepoMockRecorder) GetProfile(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockUserRepo)(nil).GetProfile), ctx, uid)
+}
+
+// GetResume mocks base method.
+func (m *MockUserRepo) GetResume(ctx context.Context, id uint) (models.Resume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResume", ctx, id)
+	ret0, _ := ret[0].(models.Resume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResume indicates an expected call of GetResume.
+func (mr *MockUserRepoMockRecorder) GetResume(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResume", reflect.TypeOf((*MockUserRepo)(nil).GetResume), ctx, id)
+}
+
+// GetTaxonomy mocks base method.
+func (m *MockUserRepo) GetTaxonomy(ctx context.Context, kind models.TaxonomyKind, id uint) (models.TaxonomyItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTaxonomy", ctx, kind, id)
+	ret0, _ := ret[0].(models.TaxonomyItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTaxonomy indicates an expected call of GetTaxonomy.
+func (mr *MockUserRepoMockRecorder) GetTaxonomy(ctx, kind, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTaxonomy", reflect.TypeOf((*MockUserRepo)(nil).GetTaxonomy), ctx, kind, id)
+}
+
+// GetUserById mocks base method.
+func (m *MockUserRepo) GetUserById(ctx context.Context, uid uint) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserById", ctx, uid)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserById indicates an expected call of GetUserById.
+func (mr *MockUserRepoMockRecorder) GetUserById(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockUserRepo)(nil).GetUserById), ctx, uid)
+}
+
+// ListApplicationResumes mocks base method.
+func (m *MockUserRepo) ListApplicationResumes(ctx context.Context, aid uint) ([]models.Resume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListApplicationResumes", ctx, aid)
+	ret0, _ := ret[0].([]models.Resume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListApplicationResumes indicates an expected call of ListApplicationResumes.
+func (mr *MockUserRepoMockRecorder) ListApplicationResumes(ctx, aid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListApplicationResumes", reflect.TypeOf((*MockUserRepo)(nil).ListApplicationResumes), ctx, aid)
+}
+
+// ListJobApplications mocks base method.
+func (m *MockUserRepo) ListJobApplications(ctx context.Context, jid uint64, stages []string) ([]models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJobApplications", ctx, jid, stages)
+	ret0, _ := ret[0].([]models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListJobApplications indicates an expected call of ListJobApplications.
+func (mr *MockUserRepoMockRecorder) ListJobApplications(ctx, jid, stages any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobApplications", reflect.TypeOf((*MockUserRepo)(nil).ListJobApplications), ctx, jid, stages)
+}
+
+// ListJobs mocks base method.
+func (m *MockUserRepo) ListJobs(ctx context.Context, q models.JobListQuery) ([]models.Job, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListJobs", ctx, q)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListJobs indicates an expected call of ListJobs.
+func (mr *MockUserRepoMockRecorder) ListJobs(ctx, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListJobs", reflect.TypeOf((*MockUserRepo)(nil).ListJobs), ctx, q)
+}
+
+// ListTaxonomy mocks base method.
+func (m *MockUserRepo) ListTaxonomy(ctx context.Context, kind models.TaxonomyKind) ([]models.TaxonomyItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaxonomy", ctx, kind)
+	ret0, _ := ret[0].([]models.TaxonomyItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaxonomy indicates an expected call of ListTaxonomy.
+func (mr *MockUserRepoMockRecorder) ListTaxonomy(ctx, kind any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaxonomy", reflect.TypeOf((*MockUserRepo)(nil).ListTaxonomy), ctx, kind)
+}
+
+// ListUserApplications mocks base method.
+func (m *MockUserRepo) ListUserApplications(ctx context.Context, uid uint) ([]models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserApplications", ctx, uid)
+	ret0, _ := ret[0].([]models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserApplications indicates an expected call of ListUserApplications.
+func (mr *MockUserRepoMockRecorder) ListUserApplications(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserApplications", reflect.TypeOf((*MockUserRepo)(nil).ListUserApplications), ctx, uid)
+}
+
+// ListUserResumes mocks base method.
+func (m *MockUserRepo) ListUserResumes(ctx context.Context, uid uint) ([]models.Resume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserResumes", ctx, uid)
+	ret0, _ := ret[0].([]models.Resume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserResumes indicates an expected call of ListUserResumes.
+func (mr *MockUserRepoMockRecorder) ListUserResumes(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserResumes", reflect.TypeOf((*MockUserRepo)(nil).ListUserResumes), ctx, uid)
+}
+
+// MarkUserVerified mocks base method.
+func (m *MockUserRepo) MarkUserVerified(ctx context.Context, uid uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkUserVerified", ctx, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkUserVerified indicates an expected call of MarkUserVerified.
+func (mr *MockUserRepoMockRecorder) MarkUserVerified(ctx, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkUserVerified", reflect.TypeOf((*MockUserRepo)(nil).MarkUserVerified), ctx, uid)
+}
+
+// MergeTaxonomy mocks base method.
+func (m *MockUserRepo) MergeTaxonomy(ctx context.Context, kind models.TaxonomyKind, targetId uint, sourceIds []uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeTaxonomy", ctx, kind, targetId, sourceIds)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeTaxonomy indicates an expected call of MergeTaxonomy.
+func (mr *MockUserRepoMockRecorder) MergeTaxonomy(ctx, kind, targetId, sourceIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeTaxonomy", reflect.TypeOf((*MockUserRepo)(nil).MergeTaxonomy), ctx, kind, targetId, sourceIds)
+}
+
+// MissingTaxonomyIDs mocks base method.
+func (m *MockUserRepo) MissingTaxonomyIDs(ctx context.Context, kind models.TaxonomyKind, ids []uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MissingTaxonomyIDs", ctx, kind, ids)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MissingTaxonomyIDs indicates an expected call of MissingTaxonomyIDs.
+func (mr *MockUserRepoMockRecorder) MissingTaxonomyIDs(ctx, kind, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MissingTaxonomyIDs", reflect.TypeOf((*MockUserRepo)(nil).MissingTaxonomyIDs), ctx, kind, ids)
+}
+
+// MoveApplication mocks base method.
+func (m *MockUserRepo) MoveApplication(ctx context.Context, change models.ApplicationStageChange) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveApplication", ctx, change)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveApplication indicates an expected call of MoveApplication.
+func (mr *MockUserRepoMockRecorder) MoveApplication(ctx, change any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveApplication", reflect.TypeOf((*MockUserRepo)(nil).MoveApplication), ctx, change)
+}
+
+// OpenJobs mocks base method.
+func (m *MockUserRepo) OpenJobs(ctx context.Context, limit int) ([]models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenJobs", ctx, limit)
+	ret0, _ := ret[0].([]models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenJobs indicates an expected call of OpenJobs.
+func (mr *MockUserRepoMockRecorder) OpenJobs(ctx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenJobs", reflect.TypeOf((*MockUserRepo)(nil).OpenJobs), ctx, limit)
+}
+
 // PostJob mocks base method.
 func (m *MockUserRepo) PostJob(nj models.Job) (models.Response, error) {
 	m.ctrl.T.Helper()
@@ -190,6 +710,140 @@ func (mr *MockUserRepoMockRecorder) PostJob(nj any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PostJob", reflect.TypeOf((*MockUserRepo)(nil).PostJob), nj)
 }
 
+// RemoveCompanyMember mocks base method.
+func (m *MockUserRepo) RemoveCompanyMember(cid uint64, uid uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCompanyMember", cid, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCompanyMember indicates an expected call of RemoveCompanyMember.
+func (mr *MockUserRepoMockRecorder) RemoveCompanyMember(cid, uid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCompanyMember", reflect.TypeOf((*MockUserRepo)(nil).RemoveCompanyMember), cid, uid)
+}
+
+// RenameTaxonomy mocks base method.
+func (m *MockUserRepo) RenameTaxonomy(ctx context.Context, kind models.TaxonomyKind, id uint, name string) (models.TaxonomyItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameTaxonomy", ctx, kind, id, name)
+	ret0, _ := ret[0].(models.TaxonomyItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameTaxonomy indicates an expected call of RenameTaxonomy.
+func (mr *MockUserRepoMockRecorder) RenameTaxonomy(ctx, kind, id, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameTaxonomy", reflect.TypeOf((*MockUserRepo)(nil).RenameTaxonomy), ctx, kind, id, name)
+}
+
+// RestoreCompany mocks base method.
+func (m *MockUserRepo) RestoreCompany(ctx context.Context, cid uint64) (models.Company, []uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreCompany", ctx, cid)
+	ret0, _ := ret[0].(models.Company)
+	ret1, _ := ret[1].([]uint)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RestoreCompany indicates an expected call of RestoreCompany.
+func (mr *MockUserRepoMockRecorder) RestoreCompany(ctx, cid any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreCompany", reflect.TypeOf((*MockUserRepo)(nil).RestoreCompany), ctx, cid)
+}
+
+// SearchJobs mocks base method.
+func (m *MockUserRepo) SearchJobs(ctx context.Context, q models.JobSearchQuery) ([]models.JobSearchHit, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchJobs", ctx, q)
+	ret0, _ := ret[0].([]models.JobSearchHit)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// SearchJobs indicates an expected call of SearchJobs.
+func (mr *MockUserRepoMockRecorder) SearchJobs(ctx, q any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchJobs", reflect.TypeOf((*MockUserRepo)(nil).SearchJobs), ctx, q)
+}
+
+// SetJobStatus mocks base method.
+func (m *MockUserRepo) SetJobStatus(ctx context.Context, jid uint64, from, to string, expiresAt *time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetJobStatus", ctx, jid, from, to, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetJobStatus indicates an expected call of SetJobStatus.
+func (mr *MockUserRepoMockRecorder) SetJobStatus(ctx, jid, from, to, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetJobStatus", reflect.TypeOf((*MockUserRepo)(nil).SetJobStatus), ctx, jid, from, to, expiresAt)
+}
+
+// SetUserRole mocks base method.
+func (m *MockUserRepo) SetUserRole(ctx context.Context, uid uint, role string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRole", ctx, uid, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserRole indicates an expected call of SetUserRole.
+func (mr *MockUserRepoMockRecorder) SetUserRole(ctx, uid, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRole", reflect.TypeOf((*MockUserRepo)(nil).SetUserRole), ctx, uid, role)
+}
+
+// UpdateCompany mocks base method.
+func (m *MockUserRepo) UpdateCompany(ctx context.Context, c models.Company) (models.Company, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCompany", ctx, c)
+	ret0, _ := ret[0].(models.Company)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCompany indicates an expected call of UpdateCompany.
+func (mr *MockUserRepoMockRecorder) UpdateCompany(ctx, c any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCompany", reflect.TypeOf((*MockUserRepo)(nil).UpdateCompany), ctx, c)
+}
+
+// UpdateJob mocks base method.
+func (m *MockUserRepo) UpdateJob(ctx context.Context, j models.Job) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", ctx, j)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockUserRepoMockRecorder) UpdateJob(ctx, j any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockUserRepo)(nil).UpdateJob), ctx, j)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockUserRepo) UpdateProfile(ctx context.Context, p models.CandidateProfile) (models.CandidateProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, p)
+	ret0, _ := ret[0].(models.CandidateProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockUserRepoMockRecorder) UpdateProfile(ctx, p any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockUserRepo)(nil).UpdateProfile), ctx, p)
+}
+
 // UpdatePwdInDb mocks base method.
 func (m *MockUserRepo) UpdatePwdInDb(user models.User) error {
 	m.ctrl.T.Helper()
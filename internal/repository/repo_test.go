@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// noConn stands in for the database, dry runs never reach it
+type noConn struct{}
+
+var errNoConn = errors.New("no database in tests")
+
+func (noConn) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return nil, errNoConn
+}
+func (noConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	return nil, errNoConn
+}
+func (noConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	return nil, errNoConn
+}
+func (noConn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	return nil
+}
+
+// dryRunRepo returns a Repo that only builds its statements, and the SQL of
+// every statement built so far
+func dryRunRepo(t *testing.T) (*Repo, func() []string) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: noConn{}}), &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("opening dry run db: %v", err)
+	}
+	var statements []string
+	capture := func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	}
+	cb := db.Callback()
+	cb.Query().After("gorm:query").Register("test:capture", capture)
+	cb.Row().After("gorm:row").Register("test:capture", capture)
+	cb.Raw().After("gorm:raw").Register("test:capture", capture)
+	cb.Create().After("gorm:create").Register("test:capture", capture)
+	cb.Update().After("gorm:update").Register("test:capture", capture)
+	cb.Delete().After("gorm:delete").Register("test:capture", capture)
+	return &Repo{DB: db}, func() []string { return statements }
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/models"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+func (r *Repo) CreateResume(ctx context.Context, rs models.Resume) (models.Resume, error) {
+	err := r.DB.WithContext(ctx).Create(&rs).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.Resume{}, errors.New("resume cannot be saved")
+	}
+	return rs, nil
+}
+
+func (r *Repo) GetResume(ctx context.Context, id uint) (models.Resume, error) {
+	var rs models.Resume
+	err := r.DB.WithContext(ctx).Where("id = ?", id).First(&rs).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.Resume{}, err
+	}
+	return rs, nil
+}
+
+// ListUserResumes returns the resumes of a user, newest first
+func (r *Repo) ListUserResumes(ctx context.Context, uid uint) ([]models.Resume, error) {
+	l := []models.Resume{}
+	err := r.DB.WithContext(ctx).Where("user_id = ?", uid).Order("created_at DESC, id DESC").Find(&l).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("resumes cannot be fetched")
+	}
+	return l, nil
+}
+
+// ListApplicationResumes returns the resumes attached to an application,
+// newest first
+func (r *Repo) ListApplicationResumes(ctx context.Context, aid uint) ([]models.Resume, error) {
+	l := []models.Resume{}
+	err := r.DB.WithContext(ctx).Where("application_id = ?", aid).Order("created_at DESC, id DESC").Find(&l).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("resumes cannot be fetched")
+	}
+	return l, nil
+}
+
+// DeleteResume removes the row for good, its file is gone with it
+func (r *Repo) DeleteResume(ctx context.Context, id uint) error {
+	res := r.DB.WithContext(ctx).Unscoped().Where("id = ?", id).Delete(&models.Resume{})
+	if res.Error != nil {
+		log.Info().Err(res.Error).Send()
+		return errors.New("resume cannot be deleted")
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
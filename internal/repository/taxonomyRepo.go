@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job-portal-api/internal/models"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+func (r *Repo) ListTaxonomy(ctx context.Context, kind models.TaxonomyKind) ([]models.TaxonomyItem, error) {
+	items := []models.TaxonomyItem{}
+	err := r.DB.WithContext(ctx).Table(kind.Table).
+		Select("id, " + kind.NameColumn + " AS name").
+		Where("deleted_at IS NULL").
+		Order(kind.NameColumn).
+		Scan(&items).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *Repo) GetTaxonomy(ctx context.Context, kind models.TaxonomyKind, id uint) (models.TaxonomyItem, error) {
+	var items []models.TaxonomyItem
+	err := r.DB.WithContext(ctx).Table(kind.Table).
+		Select("id, "+kind.NameColumn+" AS name").
+		Where("id = ? AND deleted_at IS NULL", id).
+		Scan(&items).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.TaxonomyItem{}, err
+	}
+	if len(items) == 0 {
+		return models.TaxonomyItem{}, gorm.ErrRecordNotFound
+	}
+	return items[0], nil
+}
+
+// CreateTaxonomy adds an item, gorm.ErrDuplicatedKey when the name is taken
+func (r *Repo) CreateTaxonomy(ctx context.Context, kind models.TaxonomyKind, name string) (models.TaxonomyItem, error) {
+	item := models.TaxonomyItem{Name: name}
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := taxonomyNameFree(tx, kind, name, 0)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		return tx.Raw("INSERT INTO "+kind.Table+" (created_at, updated_at, "+kind.NameColumn+") VALUES (?, ?, ?) RETURNING id",
+			now, now, name).Scan(&item.ID).Error
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return models.TaxonomyItem{}, err
+	}
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.TaxonomyItem{}, errors.New("item cannot be created")
+	}
+	return item, nil
+}
+
+// RenameTaxonomy changes the name of an item, gorm.ErrDuplicatedKey when the
+// name is taken by another item
+func (r *Repo) RenameTaxonomy(ctx context.Context, kind models.TaxonomyKind, id uint, name string) (models.TaxonomyItem, error) {
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := taxonomyNameFree(tx, kind, name, id)
+		if err != nil {
+			return err
+		}
+		res := tx.Exec("UPDATE "+kind.Table+" SET "+kind.NameColumn+" = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL",
+			name, time.Now(), id)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) || errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.TaxonomyItem{}, err
+	}
+	if err != nil {
+		log.Info().Err(err).Send()
+		return models.TaxonomyItem{}, errors.New("item cannot be updated")
+	}
+	return models.TaxonomyItem{ID: id, Name: name}, nil
+}
+
+// CountTaxonomyUsage counts the jobs and candidate profiles, deleted ones
+// excluded, tagged with an item, and the jobs and companies whose match rules
+// require it
+func (r *Repo) CountTaxonomyUsage(ctx context.Context, kind models.TaxonomyKind, id uint) (int64, error) {
+	var jobs, profiles int64
+	err := r.DB.WithContext(ctx).Table(kind.JoinTable+" AS jt").
+		Joins("JOIN jobs ON jobs.id = jt.job_id AND jobs.deleted_at IS NULL").
+		Where("jt."+kind.JoinColumn+" = ?", id).
+		Count(&jobs).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return 0, err
+	}
+	err = r.DB.WithContext(ctx).Table(kind.ProfileJoinTable+" AS pt").
+		Joins("JOIN candidate_profiles ON candidate_profiles.id = pt.candidate_profile_id AND candidate_profiles.deleted_at IS NULL").
+		Where("pt."+kind.JoinColumn+" = ?", id).
+		Count(&profiles).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return 0, err
+	}
+	if kind.MatchRulesKey == "" {
+		return jobs + profiles, nil
+	}
+	var ruled int64
+	for _, table := range []string{"jobs", "companies"} {
+		var count int64
+		err = r.DB.WithContext(ctx).Table(table).
+			Where("deleted_at IS NULL AND match_rules::jsonb -> '"+kind.MatchRulesKey+"' @> ?::jsonb", fmt.Sprintf("[%d]", id)).
+			Count(&count).Error
+		if err != nil {
+			log.Info().Err(err).Send()
+			return 0, err
+		}
+		ruled += count
+	}
+	return jobs + profiles + ruled, nil
+}
+
+func (r *Repo) DeleteTaxonomy(ctx context.Context, kind models.TaxonomyKind, id uint) error {
+	res := r.DB.WithContext(ctx).Exec("UPDATE "+kind.Table+" SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL", time.Now(), id)
+	if res.Error != nil {
+		log.Info().Err(res.Error).Send()
+		return errors.New("item cannot be deleted")
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// MergeTaxonomy moves every job tagged with one of the source items over to
+// the target item, makes match rules require the target instead of the
+// sources, deletes the sources and returns the ids of the jobs that changed.
+// gorm.ErrRecordNotFound when the target or a source does not exist.
+func (r *Repo) MergeTaxonomy(ctx context.Context, kind models.TaxonomyKind, targetId uint, sourceIds []uint) ([]uint, error) {
+	var jobIds []uint
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var found int64
+		err := tx.Table(kind.Table).Where("id IN ? AND deleted_at IS NULL", append([]uint{targetId}, sourceIds...)).Count(&found).Error
+		if err != nil {
+			return err
+		}
+		if found != int64(len(sourceIds)+1) {
+			return gorm.ErrRecordNotFound
+		}
+		err = tx.Table(kind.JoinTable).Distinct("job_id").Where(kind.JoinColumn+" IN ?", sourceIds).Pluck("job_id", &jobIds).Error
+		if err != nil {
+			return err
+		}
+		ruled, err := mergeMatchRules(tx, kind, targetId, sourceIds)
+		if err != nil {
+			return err
+		}
+		seen := map[uint]bool{}
+		for _, jid := range jobIds {
+			seen[jid] = true
+		}
+		for _, jid := range ruled {
+			if !seen[jid] {
+				seen[jid] = true
+				jobIds = append(jobIds, jid)
+			}
+		}
+		return mergeTaxonomy(tx, kind, targetId, sourceIds)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("items cannot be merged")
+	}
+	return jobIds, nil
+}
+
+func mergeTaxonomy(tx *gorm.DB, kind models.TaxonomyKind, targetId uint, sourceIds []uint) error {
+	joins := []struct{ table, owner string }{
+		{kind.JoinTable, "job_id"},
+		{kind.ProfileJoinTable, "candidate_profile_id"},
+	}
+	for _, join := range joins {
+		// Jobs and profiles tagged with both the target and a source keep a
+		// single row
+		err := tx.Exec("INSERT INTO "+join.table+" ("+join.owner+", "+kind.JoinColumn+") "+
+			"SELECT "+join.owner+", ?::bigint FROM "+join.table+" WHERE "+kind.JoinColumn+" IN ? ON CONFLICT DO NOTHING",
+			targetId, sourceIds).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Exec("DELETE FROM "+join.table+" WHERE "+kind.JoinColumn+" IN ?", sourceIds).Error
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Exec("UPDATE "+kind.Table+" SET deleted_at = ? WHERE id IN ?", time.Now(), sourceIds).Error
+}
+
+// mergeMatchRules replaces the sources by the target in the match rules of
+// jobs and companies, keeping each id once, and returns the ids of the jobs
+// whose rules or whose company's rules changed
+func mergeMatchRules(tx *gorm.DB, kind models.TaxonomyKind, targetId uint, sourceIds []uint) ([]uint, error) {
+	if kind.MatchRulesKey == "" {
+		return nil, nil
+	}
+	list := "match_rules::jsonb -> '" + kind.MatchRulesKey + "'"
+	set := "match_rules = jsonb_set(match_rules::jsonb, '{" + kind.MatchRulesKey + "}', " +
+		"(SELECT jsonb_agg(DISTINCT CASE WHEN e::bigint IN @sources THEN @target ELSE e::bigint END) " +
+		"FROM jsonb_array_elements_text(" + list + ") AS e))::text"
+	where := "EXISTS (SELECT 1 FROM jsonb_array_elements_text(" + list + ") AS e WHERE e::bigint IN @sources)"
+	args := map[string]any{"sources": sourceIds, "target": targetId}
+
+	var jobIds, companyJobIds []uint
+	err := tx.Raw("UPDATE jobs SET "+set+" WHERE "+where+" RETURNING id", args).Find(&jobIds).Error
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Raw("WITH changed AS (UPDATE companies SET "+set+" WHERE "+where+" RETURNING id) "+
+		"SELECT jobs.id FROM jobs JOIN changed ON changed.id = jobs.company_id", args).Find(&companyJobIds).Error
+	if err != nil {
+		return nil, err
+	}
+	return append(jobIds, companyJobIds...), nil
+}
+
+// taxonomyNameFree returns gorm.ErrDuplicatedKey when another item already
+// uses the name, names are compared case-insensitively
+func taxonomyNameFree(tx *gorm.DB, kind models.TaxonomyKind, name string, exceptId uint) error {
+	var count int64
+	err := tx.Table(kind.Table).
+		Where("lower("+kind.NameColumn+") = lower(?) AND id <> ? AND deleted_at IS NULL", name, exceptId).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return gorm.ErrDuplicatedKey
+	}
+	return nil
+}
+
+// MigrateTaxonomies merges the duplicate names that were inserted by hand
+// before names had to be unique, keeping the oldest row of each name, and
+// then adds the unique indexes
+func MigrateTaxonomies(db *gorm.DB) error {
+	for _, kind := range models.TaxonomyKinds {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var rows []struct {
+				ID   uint
+				Keep uint
+			}
+			err := tx.Raw("SELECT id, min(id) OVER (PARTITION BY lower(trim(" + kind.NameColumn + "))) AS keep FROM " + kind.Table +
+				" WHERE deleted_at IS NULL").Scan(&rows).Error
+			if err != nil {
+				return err
+			}
+			duplicates := map[uint][]uint{}
+			for _, row := range rows {
+				if row.ID != row.Keep {
+					duplicates[row.Keep] = append(duplicates[row.Keep], row.ID)
+				}
+			}
+			for keep, ids := range duplicates {
+				err = mergeTaxonomy(tx, kind, keep, ids)
+				if err != nil {
+					return err
+				}
+			}
+			return tx.Exec(fmt.Sprintf("CREATE UNIQUE INDEX IF NOT EXISTS idx_%s_%s_unique ON %s (lower(%s)) WHERE deleted_at IS NULL",
+				kind.Table, kind.NameColumn, kind.Table, kind.NameColumn)).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migrating %s %w", kind.Table, err)
+		}
+	}
+	return nil
+}
+
+// MissingTaxonomyIDs returns the ids, in the order given, that do not belong
+// to an existing item
+func (r *Repo) MissingTaxonomyIDs(ctx context.Context, kind models.TaxonomyKind, ids []uint) ([]uint, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var found []uint
+	err := r.DB.WithContext(ctx).Table(kind.Table).
+		Where("id IN ? AND deleted_at IS NULL", ids).
+		Pluck("id", &found).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return nil, errors.New("items cannot be checked")
+	}
+	exists := make(map[uint]bool, len(found))
+	for _, id := range found {
+		exists[id] = true
+	}
+	var missing []uint
+	for _, id := range ids {
+		if !exists[id] {
+			// Report a repeated id only once
+			exists[id] = true
+			missing = append(missing, id)
+		}
+	}
+	return missing, nil
+}
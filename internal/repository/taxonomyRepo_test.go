@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"job-portal-api/internal/models"
+	"strings"
+	"testing"
+)
+
+func TestRepo_CountTaxonomyUsage(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind      models.TaxonomyKind
+		wantRules bool
+	}{
+		{name: "skills required by match rules", kind: models.SkillKind, wantRules: true},
+		{name: "qualifications required by match rules", kind: models.QualificationKind, wantRules: true},
+		{name: "locations are never required", kind: models.LocationKind, wantRules: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, statements := dryRunRepo(t)
+			_, err := r.CountTaxonomyUsage(context.Background(), tt.kind, 4)
+			if err != nil {
+				t.Fatalf("Repo.CountTaxonomyUsage() error = %v", err)
+			}
+			var ruled []string
+			for _, s := range statements() {
+				if strings.Contains(s, "match_rules") {
+					ruled = append(ruled, s)
+				}
+			}
+			if !tt.wantRules {
+				if len(ruled) != 0 {
+					t.Errorf("Repo.CountTaxonomyUsage() counted match rules: %v", ruled)
+				}
+				return
+			}
+			if len(ruled) != 2 || !strings.Contains(ruled[0], `FROM "jobs"`) || !strings.Contains(ruled[1], `FROM "companies"`) {
+				t.Fatalf("Repo.CountTaxonomyUsage() match rule counts = %v, want jobs and companies", ruled)
+			}
+			for _, s := range ruled {
+				if !strings.Contains(s, "-> '"+tt.kind.MatchRulesKey+"' @>") {
+					t.Errorf("Repo.CountTaxonomyUsage() does not look in %s: %s", tt.kind.MatchRulesKey, s)
+				}
+			}
+		})
+	}
+}
+
+func Test_mergeMatchRules(t *testing.T) {
+	tests := []struct {
+		name      string
+		kind      models.TaxonomyKind
+		wantRules bool
+	}{
+		{name: "skills", kind: models.SkillKind, wantRules: true},
+		{name: "qualifications", kind: models.QualificationKind, wantRules: true},
+		{name: "shifts have no match rules", kind: models.ShiftKind, wantRules: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, statements := dryRunRepo(t)
+			_, err := mergeMatchRules(r.DB, tt.kind, 1, []uint{3, 4})
+			if err != nil {
+				t.Fatalf("mergeMatchRules() error = %v", err)
+			}
+			got := statements()
+			if !tt.wantRules {
+				if len(got) != 0 {
+					t.Errorf("mergeMatchRules() ran %v", got)
+				}
+				return
+			}
+			if len(got) != 2 || !strings.HasPrefix(got[0], "UPDATE jobs SET match_rules") || !strings.Contains(got[1], "UPDATE companies SET match_rules") {
+				t.Fatalf("mergeMatchRules() = %v, want the rules of jobs and companies rewritten", got)
+			}
+			for _, s := range got {
+				if !strings.Contains(s, "'{"+tt.kind.MatchRulesKey+"}'") || !strings.Contains(s, "IN ($1,$2) THEN $3") {
+					t.Errorf("mergeMatchRules() does not rewrite %s: %s", tt.kind.MatchRulesKey, s)
+				}
+			}
+		})
+	}
+}
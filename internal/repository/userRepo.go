@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"job-portal-api/internal/models"
+	"time"
 
 	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
 )
 
 func (r *Repo) CreateUser(ctx context.Context, nu models.User) (models.User, error) {
@@ -35,3 +37,51 @@ func (r *Repo) UpdatePwdInDb(user models.User) error {
 	}
 	return nil
 }
+
+func (r *Repo) GetUserById(ctx context.Context, uid uint) (models.User, error) {
+	var userDetails models.User
+	result := r.DB.Where("id = ?", uid).First(&userDetails)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return models.User{}, errors.New("user not found")
+	}
+	return userDetails, nil
+}
+
+func (r *Repo) EmailExists(ctx context.Context, email string) (bool, error) {
+	var count int64
+	err := r.DB.WithContext(ctx).Model(&models.User{}).Where("lower(email) = lower(?)", email).Count(&count).Error
+	if err != nil {
+		log.Info().Err(err).Send()
+		return false, errors.New("could not check email")
+	}
+	return count > 0, nil
+}
+
+func (r *Repo) MarkUserVerified(ctx context.Context, uid uint) error {
+	now := time.Now()
+	result := r.DB.WithContext(ctx).Model(&models.User{}).Where("id = ?", uid).
+		Updates(map[string]any{"verified": true, "verified_at": &now})
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return errors.New("could not verify user")
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// SetUserRole changes the role of a user, gorm.ErrRecordNotFound when there is
+// no such user
+func (r *Repo) SetUserRole(ctx context.Context, uid uint, role string) error {
+	result := r.DB.WithContext(ctx).Model(&models.User{}).Where("id = ?", uid).Update("role", role)
+	if result.Error != nil {
+		log.Info().Err(result.Error).Send()
+		return errors.New("could not change role")
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
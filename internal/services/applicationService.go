@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Errors returned for applications
+var (
+	ErrJobNotOpen            = errors.New("job is not open for applications")
+	ErrAlreadyApplied        = errors.New("you have already applied for this job")
+	ErrApplicationNotFound   = errors.New("application not found")
+	ErrApplicationTransition = errors.New("application cannot move to this stage")
+	ErrApplicationDetails    = errors.New("job_application is required until you create a profile")
+)
+
+// ApplyForJob stores the application of the logged in user together with the
+// result of matching it against the job
+func (s *Service) ApplyForJob(ctx context.Context, jid uint64, na models.NewApplication, claims auth.Claims) (models.Application, error) {
+	uid, err := claims.UserId()
+	if err != nil {
+		return models.Application{}, err
+	}
+	j, err := s.UserRepo.GetJob(ctx, jid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Application{}, ErrJobNotFound
+	}
+	if err != nil {
+		return models.Application{}, err
+	}
+	if !j.IsOpen(time.Now()) {
+		return models.Application{}, ErrJobNotOpen
+	}
+	details, err := s.applicationDetails(ctx, uid, na)
+	if err != nil {
+		return models.Application{}, err
+	}
+	result := s.compareData(details, j)
+	a, err := s.UserRepo.CreateApplication(ctx, models.Application{
+		UserId:  uid,
+		JobId:   jid,
+		Name:    details.Name,
+		Age:     details.Age,
+		Details: details.Jobs,
+		Matched: result.Matched,
+		Score:   result.Score,
+		Stage:   models.StageApplied,
+	})
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return models.Application{}, ErrAlreadyApplied
+	}
+	if err != nil {
+		return models.Application{}, err
+	}
+	return a, nil
+}
+
+// applicationDetails fills what the application leaves out, the name from the
+// account and the details from the candidate profile
+func (s *Service) applicationDetails(ctx context.Context, uid uint, na models.NewApplication) (models.NewUserApplication, error) {
+	d := models.NewUserApplication{Name: na.Name, Age: na.Age}
+	if na.Jobs != nil {
+		d.Jobs = *na.Jobs
+	} else {
+		p, err := s.UserRepo.GetProfile(ctx, uid)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.NewUserApplication{}, ErrApplicationDetails
+		}
+		if err != nil {
+			return models.NewUserApplication{}, err
+		}
+		d.Jobs = p.Details()
+	}
+	if d.Name == "" {
+		u, err := s.UserRepo.GetUserById(ctx, uid)
+		if err != nil {
+			return models.NewUserApplication{}, err
+		}
+		d.Name = u.Name
+	}
+	return d, nil
+}
+
+// MoveApplication moves an application to another stage, only members of the
+// company of the job may do that
+func (s *Service) MoveApplication(ctx context.Context, aid uint, m models.MoveApplication, claims auth.Claims) (models.Application, error) {
+	a, err := s.getApplication(ctx, aid)
+	if err != nil {
+		return models.Application{}, err
+	}
+	err = s.checkCompanyAccess(a.Job.CompanyId, claims)
+	if err != nil {
+		return models.Application{}, err
+	}
+	return s.moveApplication(ctx, a, m.Stage, m.Note, claims)
+}
+
+// WithdrawApplication lets candidates take back their own application
+func (s *Service) WithdrawApplication(ctx context.Context, aid uint, claims auth.Claims) (models.Application, error) {
+	a, err := s.ownApplication(ctx, aid, claims)
+	if err != nil {
+		return models.Application{}, err
+	}
+	return s.moveApplication(ctx, a, models.StageWithdrawn, "", claims)
+}
+
+// moveApplication changes the stage when models.ApplicationStages allows it
+// and records who did it
+func (s *Service) moveApplication(ctx context.Context, a models.Application, stage, note string, claims auth.Claims) (models.Application, error) {
+	uid, err := claims.UserId()
+	if err != nil {
+		return models.Application{}, err
+	}
+	if !a.CanMoveTo(stage) {
+		return models.Application{}, ErrApplicationTransition
+	}
+	err = s.UserRepo.MoveApplication(ctx, models.ApplicationStageChange{
+		ApplicationId: a.ID,
+		FromStage:     a.Stage,
+		ToStage:       stage,
+		ChangedBy:     uid,
+		Note:          note,
+	})
+	// A concurrent withdrawal or move leaves a.Stage stale
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Application{}, ErrApplicationTransition
+	}
+	if err != nil {
+		return models.Application{}, err
+	}
+	a.Stage = stage
+	return a, nil
+}
+
+// ViewJobApplications lists the applications for a job to the members of its
+// company, only those in one of the stages when stages are given
+func (s *Service) ViewJobApplications(ctx context.Context, jid uint64, stages []string, claims auth.Claims) ([]models.Application, error) {
+	_, err := s.jobForChange(ctx, jid, claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.UserRepo.ListJobApplications(ctx, jid, stages)
+}
+
+// ViewMyApplications lists the applications of the logged in user
+func (s *Service) ViewMyApplications(ctx context.Context, claims auth.Claims) ([]models.Application, error) {
+	uid, err := claims.UserId()
+	if err != nil {
+		return nil, err
+	}
+	return s.UserRepo.ListUserApplications(ctx, uid)
+}
+
+// ViewApplicationHistory returns the stage changes of an application to the
+// candidate who made it and to the members of the company of the job
+func (s *Service) ViewApplicationHistory(ctx context.Context, aid uint, claims auth.Claims) ([]models.ApplicationStageChange, error) {
+	_, err := s.visibleApplication(ctx, aid, claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.UserRepo.ApplicationHistory(ctx, aid)
+}
+
+// visibleApplication loads an application for the candidate who made it or a
+// member of the company of the job
+func (s *Service) visibleApplication(ctx context.Context, aid uint, claims auth.Claims) (models.Application, error) {
+	a, err := s.getApplication(ctx, aid)
+	if err != nil {
+		return models.Application{}, err
+	}
+	uid, err := claims.UserId()
+	if err != nil {
+		return models.Application{}, err
+	}
+	if a.UserId != uid {
+		err = s.checkCompanyAccess(a.Job.CompanyId, claims)
+		if err != nil {
+			return models.Application{}, err
+		}
+	}
+	return a, nil
+}
+
+func (s *Service) getApplication(ctx context.Context, aid uint) (models.Application, error) {
+	a, err := s.UserRepo.GetApplication(ctx, aid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Application{}, ErrApplicationNotFound
+	}
+	if err != nil {
+		return models.Application{}, err
+	}
+	return a, nil
+}
+
+// ownApplication loads an application of the logged in user, the
+// applications of others are reported as not found
+func (s *Service) ownApplication(ctx context.Context, aid uint, claims auth.Claims) (models.Application, error) {
+	uid, err := claims.UserId()
+	if err != nil {
+		return models.Application{}, err
+	}
+	a, err := s.getApplication(ctx, aid)
+	if err != nil {
+		return models.Application{}, err
+	}
+	if a.UserId != uid {
+		return models.Application{}, ErrApplicationNotFound
+	}
+	return a, nil
+}
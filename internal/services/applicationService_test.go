@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/caching"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestService_ApplyForJob(t *testing.T) {
+	candidate := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "4"}, Roles: []string{models.RoleCandidate}}
+	open := models.Job{
+		Model:               gorm.Model{ID: 5},
+		Status:              models.JobPublished,
+		MinimumNoticePeriod: 0,
+		MaximumNoticePeriod: 60,
+		MinExperience:       1,
+		MaxExperience:       5,
+		Locations:           []models.Location{{Model: gorm.Model{ID: 1}}},
+		Skills:              []models.Skill{{Model: gorm.Model{ID: 3}}},
+	}
+	past := time.Now().Add(-time.Hour)
+	na := models.NewApplication{Name: "ravi", Jobs: &models.RequestFromUser{NoticePeriod: 30, Experience: 2, Location: []uint{1}, Skills: []uint{3}}}
+	tests := []struct {
+		name        string
+		setupMocks  func(mr *repository.MockUserRepo)
+		wantMatched bool
+		wantErr     error
+	}{
+		{name: "job does not exist",
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrJobNotFound,
+		},
+		{name: "draft takes no applications",
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{Model: gorm.Model{ID: 5}, Status: models.JobDraft}, nil)
+			},
+			wantErr: ErrJobNotOpen,
+		},
+		{name: "published job past its expiry date",
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{Model: gorm.Model{ID: 5}, Status: models.JobPublished, ExpiresAt: &past}, nil)
+			},
+			wantErr: ErrJobNotOpen,
+		},
+		{name: "second application for the job",
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(open, nil)
+				mr.EXPECT().CreateApplication(gomock.Any(), gomock.Any()).Return(models.Application{}, gorm.ErrDuplicatedKey)
+			},
+			wantErr: ErrAlreadyApplied,
+		},
+		{name: "application stored with the match result",
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(open, nil)
+				mr.EXPECT().CreateApplication(gomock.Any(), models.Application{
+					UserId: 4, JobId: 5, Name: "ravi", Details: *na.Jobs, Matched: true, Score: 57.14, Stage: models.StageApplied,
+				}).DoAndReturn(func(ctx context.Context, a models.Application) (models.Application, error) {
+					a.ID = 8
+					return a, nil
+				})
+			},
+			wantMatched: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			tt.setupMocks(MockUserRepo)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+			got, err := s.ApplyForJob(context.Background(), 5, na, candidate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Service.ApplyForJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Matched != tt.wantMatched {
+				t.Errorf("Service.ApplyForJob() matched = %v, want %v", got.Matched, tt.wantMatched)
+			}
+		})
+	}
+}
+
+func TestService_ApplyForJobFromProfile(t *testing.T) {
+	candidate := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "4"}, Roles: []string{models.RoleCandidate}}
+	open := models.Job{Model: gorm.Model{ID: 5}, Status: models.JobPublished, MaximumNoticePeriod: 60, MaxExperience: 5}
+	profile := models.CandidateProfile{
+		UserId:       4,
+		NoticePeriod: 30,
+		Experience:   2,
+		Locations:    []models.Location{{Model: gorm.Model{ID: 1}}},
+		Skills:       []models.Skill{{Model: gorm.Model{ID: 3}}, {Model: gorm.Model{ID: 7}}},
+	}
+	tests := []struct {
+		name        string
+		na          models.NewApplication
+		setupMocks  func(mr *repository.MockUserRepo)
+		wantName    string
+		wantDetails models.RequestFromUser
+		wantErr     error
+	}{
+		{name: "details and name filled in",
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetProfile(gomock.Any(), uint(4)).Return(profile, nil)
+				mr.EXPECT().GetUserById(gomock.Any(), uint(4)).Return(models.User{Name: "Ravi Kumar"}, nil)
+			},
+			wantName:    "Ravi Kumar",
+			wantDetails: models.RequestFromUser{NoticePeriod: 30, Experience: 2, Location: []uint{1}, Skills: []uint{3, 7}},
+		},
+		{name: "details sent win over the profile",
+			na:          models.NewApplication{Name: "ravi", Jobs: &models.RequestFromUser{NoticePeriod: 10, Experience: 1}},
+			setupMocks:  func(mr *repository.MockUserRepo) {},
+			wantName:    "ravi",
+			wantDetails: models.RequestFromUser{NoticePeriod: 10, Experience: 1},
+		},
+		{name: "no details and no profile",
+			na: models.NewApplication{Name: "ravi"},
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetProfile(gomock.Any(), uint(4)).Return(models.CandidateProfile{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrApplicationDetails,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockUserRepo.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(open, nil)
+			tt.setupMocks(MockUserRepo)
+			if tt.wantErr == nil {
+				MockUserRepo.EXPECT().CreateApplication(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, a models.Application) (models.Application, error) {
+					return a, nil
+				})
+			}
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+			got, err := s.ApplyForJob(context.Background(), 5, tt.na, candidate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Service.ApplyForJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Name != tt.wantName || !reflect.DeepEqual(got.Details, tt.wantDetails) {
+				t.Errorf("Service.ApplyForJob() = %s %+v, want %s %+v", got.Name, got.Details, tt.wantName, tt.wantDetails)
+			}
+		})
+	}
+}
+
+func TestService_MoveApplication(t *testing.T) {
+	recruiter := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Roles: []string{models.RoleRecruiter}}
+	applied := models.Application{Model: gorm.Model{ID: 8}, UserId: 4, JobId: 5, Job: models.Job{CompanyId: 2}, Stage: models.StageApplied}
+	tests := []struct {
+		name       string
+		stage      string
+		setupMocks func(mr *repository.MockUserRepo)
+		wantStage  string
+		wantErr    error
+	}{
+		{name: "application does not exist", stage: models.StageInterview,
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetApplication(gomock.Any(), uint(8)).Return(models.Application{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrApplicationNotFound,
+		},
+		{name: "recruiter of another company", stage: models.StageInterview,
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetApplication(gomock.Any(), uint(8)).Return(applied, nil)
+				mr.EXPECT().GetCompanyMember(uint64(2), uint(1)).Return(models.CompanyMember{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrNotCompanyMember,
+		},
+		{name: "offer before the interview", stage: models.StageOffer,
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetApplication(gomock.Any(), uint(8)).Return(applied, nil)
+				mr.EXPECT().GetCompanyMember(uint64(2), uint(1)).Return(models.CompanyMember{Role: models.MemberRecruiter}, nil)
+			},
+			wantErr: ErrApplicationTransition,
+		},
+		{name: "moved by another request", stage: models.StageInterview,
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetApplication(gomock.Any(), uint(8)).Return(applied, nil)
+				mr.EXPECT().GetCompanyMember(uint64(2), uint(1)).Return(models.CompanyMember{Role: models.MemberRecruiter}, nil)
+				mr.EXPECT().MoveApplication(gomock.Any(), gomock.Any()).Return(gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrApplicationTransition,
+		},
+		{name: "moved and recorded", stage: models.StageInterview,
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetApplication(gomock.Any(), uint(8)).Return(applied, nil)
+				mr.EXPECT().GetCompanyMember(uint64(2), uint(1)).Return(models.CompanyMember{Role: models.MemberRecruiter}, nil)
+				mr.EXPECT().MoveApplication(gomock.Any(), models.ApplicationStageChange{
+					ApplicationId: 8, FromStage: models.StageApplied, ToStage: models.StageInterview, ChangedBy: 1, Note: "strong go skills",
+				}).Return(nil)
+			},
+			wantStage: models.StageInterview,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			tt.setupMocks(MockUserRepo)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+			got, err := s.MoveApplication(context.Background(), 8, models.MoveApplication{Stage: tt.stage, Note: "strong go skills"}, recruiter)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Service.MoveApplication() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Stage != tt.wantStage {
+				t.Errorf("Service.MoveApplication() stage = %q, want %q", got.Stage, tt.wantStage)
+			}
+		})
+	}
+}
+
+func TestService_WithdrawApplication(t *testing.T) {
+	candidate := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "4"}, Roles: []string{models.RoleCandidate}}
+	tests := []struct {
+		name       string
+		setupMocks func(mr *repository.MockUserRepo)
+		wantErr    error
+	}{
+		{name: "application of another candidate",
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetApplication(gomock.Any(), uint(8)).Return(models.Application{Model: gorm.Model{ID: 8}, UserId: 6, Stage: models.StageApplied}, nil)
+			},
+			wantErr: ErrApplicationNotFound,
+		},
+		{name: "hired application",
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetApplication(gomock.Any(), uint(8)).Return(models.Application{Model: gorm.Model{ID: 8}, UserId: 4, Stage: models.StageHired}, nil)
+			},
+			wantErr: ErrApplicationTransition,
+		},
+		{name: "withdrawn",
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetApplication(gomock.Any(), uint(8)).Return(models.Application{Model: gorm.Model{ID: 8}, UserId: 4, Stage: models.StageInterview}, nil)
+				mr.EXPECT().MoveApplication(gomock.Any(), models.ApplicationStageChange{
+					ApplicationId: 8, FromStage: models.StageInterview, ToStage: models.StageWithdrawn, ChangedBy: 4,
+				}).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			tt.setupMocks(MockUserRepo)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+			_, err := s.WithdrawApplication(context.Background(), 8, candidate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Service.WithdrawApplication() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_ViewApplicationHistory(t *testing.T) {
+	history := []models.ApplicationStageChange{{ID: 1, ApplicationId: 8, FromStage: models.StageApplied, ToStage: models.StageScreening, ChangedBy: 1}}
+	tests := []struct {
+		name       string
+		claims     auth.Claims
+		setupMocks func(mr *repository.MockUserRepo)
+		wantErr    error
+	}{
+		{name: "candidate sees own history",
+			claims: auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "4"}, Roles: []string{models.RoleCandidate}},
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetApplication(gomock.Any(), uint(8)).Return(models.Application{Model: gorm.Model{ID: 8}, UserId: 4, Job: models.Job{CompanyId: 2}}, nil)
+				mr.EXPECT().ApplicationHistory(gomock.Any(), uint(8)).Return(history, nil)
+			},
+		},
+		{name: "another candidate",
+			claims: auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "6"}, Roles: []string{models.RoleCandidate}},
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetApplication(gomock.Any(), uint(8)).Return(models.Application{Model: gorm.Model{ID: 8}, UserId: 4, Job: models.Job{CompanyId: 2}}, nil)
+				mr.EXPECT().GetCompanyMember(uint64(2), uint(6)).Return(models.CompanyMember{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrNotCompanyMember,
+		},
+		{name: "member of the company",
+			claims: auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Roles: []string{models.RoleRecruiter}},
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetApplication(gomock.Any(), uint(8)).Return(models.Application{Model: gorm.Model{ID: 8}, UserId: 4, Job: models.Job{CompanyId: 2}}, nil)
+				mr.EXPECT().GetCompanyMember(uint64(2), uint(1)).Return(models.CompanyMember{Role: models.MemberOwner}, nil)
+				mr.EXPECT().ApplicationHistory(gomock.Any(), uint(8)).Return(history, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			tt.setupMocks(MockUserRepo)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+			_, err := s.ViewApplicationHistory(context.Background(), 8, tt.claims)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Service.ViewApplicationHistory() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_ViewJobApplications(t *testing.T) {
+	recruiter := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Roles: []string{models.RoleRecruiter}}
+	mc := gomock.NewController(t)
+	MockUserRepo := repository.NewMockUserRepo(mc)
+	MockUserRepo.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{Model: gorm.Model{ID: 5}, CompanyId: 2}, nil)
+	MockUserRepo.EXPECT().GetCompanyMember(uint64(2), uint(1)).Return(models.CompanyMember{Role: models.MemberRecruiter}, nil)
+	MockUserRepo.EXPECT().ListJobApplications(gomock.Any(), uint64(5), []string{models.StageInterview}).
+		Return([]models.Application{{Model: gorm.Model{ID: 8}, Stage: models.StageInterview}}, nil)
+
+	s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+	got, err := s.ViewJobApplications(context.Background(), 5, []string{models.StageInterview}, recruiter)
+	if err != nil {
+		t.Fatalf("Service.ViewJobApplications() error = %v", err)
+	}
+	if len(got) != 1 || got[0].ID != 8 {
+		t.Errorf("Service.ViewJobApplications() = %v", got)
+	}
+}
@@ -2,11 +2,20 @@ package services
 
 import (
 	"context"
+	"errors"
+	"job-portal-api/internal/auth"
 	"job-portal-api/internal/models"
+
+	"gorm.io/gorm"
 )
 
-func (s *Service) AddCompanyDetails(ctx context.Context, companyData models.Company) (models.Company, error) {
-	companyData, err := s.UserRepo.CreateCom(companyData)
+func (s *Service) AddCompanyDetails(ctx context.Context, companyData models.Company, claims auth.Claims) (models.Company, error) {
+	uid, err := claims.UserId()
+	if err != nil {
+		return models.Company{}, err
+	}
+	// The creator becomes the first owner of the company
+	companyData, err = s.UserRepo.CreateCom(companyData, uid)
 	if err != nil {
 		return models.Company{}, err
 	}
@@ -18,6 +27,10 @@ func (s *Service) ViewAllCompanies(ctx context.Context) ([]models.Company, error
 	if err != nil {
 		return nil, err
 	}
+	// Open to every role, the match rules are only shown to those who set them
+	for i := range companyDetails {
+		companyDetails[i].MatchRules = nil
+	}
 	return companyDetails, nil
 }
 
@@ -26,5 +39,161 @@ func (s *Service) ViewCompanyDetails(ctx context.Context, id uint64) (models.Com
 	if err != nil {
 		return models.Company{}, err
 	}
+	companyData.MatchRules = nil
+	return companyData, nil
+}
+
+// UpdateCompany changes the details of a company, only its owners may do that
+func (s *Service) UpdateCompany(ctx context.Context, cid uint64, companyData models.Company, claims auth.Claims) (models.Company, error) {
+	err := s.checkCompanyAccess(cid, claims, models.MemberOwner)
+	if err != nil {
+		return models.Company{}, err
+	}
+	companyData.ID = uint(cid)
+	companyData, err = s.UserRepo.UpdateCompany(ctx, companyData)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Company{}, ErrCompanyNotFound
+	}
+	if err != nil {
+		return models.Company{}, err
+	}
+	// The cached jobs carry the match rules of the company
+	jobIds, err := s.UserRepo.CompanyJobIDs(ctx, cid)
+	if err != nil {
+		return models.Company{}, err
+	}
+	s.invalidateJobs(ctx, jobIds...)
 	return companyData, nil
 }
+
+// DeleteCompany soft deletes a company. A company that still has open jobs,
+// i.e. jobs not closed or expired, is only deleted when cascade is set, its
+// jobs are then deleted with it.
+func (s *Service) DeleteCompany(ctx context.Context, cid uint64, cascade bool, claims auth.Claims) error {
+	err := s.checkCompanyAccess(cid, claims, models.MemberOwner)
+	if err != nil {
+		return err
+	}
+	jobs, err := s.UserRepo.CountCompanyJobs(ctx, cid)
+	if err != nil {
+		return err
+	}
+	if jobs > 0 && !cascade {
+		return ErrCompanyHasJobs
+	}
+	jobIds, err := s.UserRepo.DeleteCompany(ctx, cid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrCompanyNotFound
+	}
+	if err != nil {
+		return err
+	}
+	s.invalidateJobs(ctx, jobIds...)
+	return nil
+}
+
+// RestoreCompany brings back a deleted company and the jobs deleted with it
+func (s *Service) RestoreCompany(ctx context.Context, cid uint64, claims auth.Claims) (models.Company, error) {
+	err := s.checkCompanyAccess(cid, claims, models.MemberOwner)
+	if err != nil {
+		return models.Company{}, err
+	}
+	company, jobIds, err := s.UserRepo.RestoreCompany(ctx, cid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Company{}, ErrCompanyNotFound
+	}
+	if err != nil {
+		return models.Company{}, err
+	}
+	s.invalidateJobs(ctx, jobIds...)
+	return company, nil
+}
+
+func (s *Service) AddCompanyMember(ctx context.Context, cid uint64, nm models.NewCompanyMember, claims auth.Claims) (models.CompanyMember, error) {
+	err := s.checkCompanyAccess(cid, claims, models.MemberOwner)
+	if err != nil {
+		return models.CompanyMember{}, err
+	}
+	user, err := s.UserRepo.CheckEmail(ctx, nm.Email)
+	if err != nil {
+		return models.CompanyMember{}, err
+	}
+	if user.Role != models.RoleRecruiter && user.Role != models.RoleAdmin {
+		return models.CompanyMember{}, errors.New("only recruiters can be added to a company")
+	}
+	member, err := s.UserRepo.AddCompanyMember(models.CompanyMember{
+		CompanyId: cid,
+		UserId:    user.ID,
+		Role:      nm.Role,
+	})
+	if err != nil {
+		return models.CompanyMember{}, err
+	}
+	return member, nil
+}
+
+func (s *Service) ViewCompanyMembers(ctx context.Context, cid uint64, claims auth.Claims) ([]models.CompanyMember, error) {
+	err := s.checkCompanyAccess(cid, claims)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.UserRepo.GetCompanyMembers(cid)
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (s *Service) RemoveCompanyMember(ctx context.Context, cid uint64, uid uint, claims auth.Claims) error {
+	err := s.checkCompanyAccess(cid, claims, models.MemberOwner)
+	if err != nil {
+		return err
+	}
+	members, err := s.UserRepo.GetCompanyMembers(cid)
+	if err != nil {
+		return err
+	}
+	owners := 0
+	removingOwner := false
+	for _, m := range members {
+		if m.Role == models.MemberOwner {
+			owners++
+			if m.UserId == uid {
+				removingOwner = true
+			}
+		}
+	}
+	if removingOwner && owners == 1 {
+		return ErrLastOwner
+	}
+	return s.UserRepo.RemoveCompanyMember(cid, uid)
+}
+
+// checkCompanyAccess makes sure the user is a member of the company holding
+// one of the given member roles (any role when none are given). Admins are
+// always allowed.
+func (s *Service) checkCompanyAccess(cid uint64, claims auth.Claims, memberRoles ...string) error {
+	if claims.HasRole(models.RoleAdmin) {
+		return nil
+	}
+	uid, err := claims.UserId()
+	if err != nil {
+		return err
+	}
+	member, err := s.UserRepo.GetCompanyMember(cid, uid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotCompanyMember
+	}
+	if err != nil {
+		return err
+	}
+	if len(memberRoles) == 0 {
+		return nil
+	}
+	for _, r := range memberRoles {
+		if member.Role == r {
+			return nil
+		}
+	}
+	return ErrNotCompanyMember
+}
@@ -10,7 +10,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 func TestService_AddCompanyDetails(t *testing.T) {
@@ -63,10 +65,10 @@ func TestService_AddCompanyDetails(t *testing.T) {
 			mc := gomock.NewController(t)
 			MockUserRepo := repository.NewMockUserRepo(mc)
 			if tt.mockRepoResponse != nil {
-				MockUserRepo.EXPECT().CreateCom(gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
+				MockUserRepo.EXPECT().CreateCom(gomock.Any(), gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
 			}
-			s, _ := NewService(MockUserRepo, &auth.Auth{},&caching.Redis{})
-			got, err := s.AddCompanyDetails(tt.args.ctx, tt.args.companyData)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+			got, err := s.AddCompanyDetails(tt.args.ctx, tt.args.companyData, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.AddCompanyDetails() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -119,7 +121,7 @@ func TestService_ViewAllCompanies(t *testing.T) {
 			if tt.mockRepoResponse != nil {
 				MockUserRepo.EXPECT().GetAllTheCompanies().Return(tt.mockRepoResponse()).AnyTimes()
 			}
-			s, _ := NewService(MockUserRepo, &auth.Auth{},&caching.Redis{})
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
 			got, err := s.ViewAllCompanies(tt.args.ctx)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.ViewAllCompanies() error = %v, wantErr %v", err, tt.wantErr)
@@ -161,7 +163,8 @@ func TestService_ViewCompanyDetails(t *testing.T) {
 			return models.Company{
 				CompanyName: "infosys",
 				Address:     "bangalore",
-				Domain:      "software"}, nil
+				Domain:      "software",
+				MatchRules:  &models.MatchRules{RequiredSkillIDs: []uint{3}}}, nil
 		},
 	
        },
@@ -185,7 +188,7 @@ func TestService_ViewCompanyDetails(t *testing.T) {
 			if tt.mockRepoResponse != nil {
 				MockUserRepo.EXPECT().GetCompany(gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
 			}
-			s, _ := NewService(MockUserRepo, &auth.Auth{},&caching.Redis{})
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
 			got, err := s.ViewCompanyDetails( tt.args.ctx,tt.args.id)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.ViewCompanyDetails() error = %v, wantErr %v", err, tt.wantErr)
@@ -197,3 +200,214 @@ func TestService_ViewCompanyDetails(t *testing.T) {
 		})
 	}
 }
+
+func TestService_AddCompanyMember(t *testing.T) {
+	type args struct {
+		cid    uint64
+		nm     models.NewCompanyMember
+		claims auth.Claims
+	}
+	tests := []struct {
+		name       string
+		args       args
+		want       models.CompanyMember
+		wantErr    error
+		setupMocks func(m *repository.MockUserRepo)
+	}{
+		{name: "owner adds a recruiter",
+			args: args{cid: 1, nm: models.NewCompanyMember{Email: "rec@gmail.com", Role: models.MemberRecruiter},
+				claims: auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Roles: []string{models.RoleRecruiter}}},
+			want: models.CompanyMember{CompanyId: 1, UserId: 2, Role: models.MemberRecruiter},
+			setupMocks: func(m *repository.MockUserRepo) {
+				m.EXPECT().GetCompanyMember(uint64(1), uint(1)).Return(models.CompanyMember{Role: models.MemberOwner}, nil)
+				m.EXPECT().CheckEmail(gomock.Any(), "rec@gmail.com").Return(models.User{Model: gorm.Model{ID: 2}, Role: models.RoleRecruiter}, nil)
+				m.EXPECT().AddCompanyMember(models.CompanyMember{CompanyId: 1, UserId: 2, Role: models.MemberRecruiter}).Return(models.CompanyMember{CompanyId: 1, UserId: 2, Role: models.MemberRecruiter}, nil)
+			},
+		},
+		{name: "recruiter member cannot add members",
+			args: args{cid: 1, nm: models.NewCompanyMember{Email: "rec@gmail.com", Role: models.MemberRecruiter},
+				claims: auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Roles: []string{models.RoleRecruiter}}},
+			want:    models.CompanyMember{},
+			wantErr: ErrNotCompanyMember,
+			setupMocks: func(m *repository.MockUserRepo) {
+				m.EXPECT().GetCompanyMember(uint64(1), uint(1)).Return(models.CompanyMember{Role: models.MemberRecruiter}, nil)
+			},
+		},
+		{name: "non member cannot add members",
+			args: args{cid: 1, nm: models.NewCompanyMember{Email: "rec@gmail.com", Role: models.MemberRecruiter},
+				claims: auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Roles: []string{models.RoleRecruiter}}},
+			want:    models.CompanyMember{},
+			wantErr: ErrNotCompanyMember,
+			setupMocks: func(m *repository.MockUserRepo) {
+				m.EXPECT().GetCompanyMember(uint64(1), uint(1)).Return(models.CompanyMember{}, gorm.ErrRecordNotFound)
+			},
+		},
+		{name: "candidates cannot join a company",
+			args: args{cid: 1, nm: models.NewCompanyMember{Email: "cand@gmail.com", Role: models.MemberRecruiter},
+				claims: auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Roles: []string{models.RoleAdmin}}},
+			want:    models.CompanyMember{},
+			wantErr: errors.New("only recruiters can be added to a company"),
+			setupMocks: func(m *repository.MockUserRepo) {
+				m.EXPECT().CheckEmail(gomock.Any(), "cand@gmail.com").Return(models.User{Model: gorm.Model{ID: 3}, Role: models.RoleCandidate}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			tt.setupMocks(MockUserRepo)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+			got, err := s.AddCompanyMember(context.Background(), tt.args.cid, tt.args.nm, tt.args.claims)
+			if tt.wantErr != nil && (err == nil || err.Error() != tt.wantErr.Error()) {
+				t.Errorf("Service.AddCompanyMember() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && err != nil {
+				t.Errorf("Service.AddCompanyMember() unexpected error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Service.AddCompanyMember() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_RemoveCompanyMember(t *testing.T) {
+	owner := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Roles: []string{models.RoleRecruiter}}
+	tests := []struct {
+		name    string
+		uid     uint
+		members []models.CompanyMember
+		wantErr error
+	}{
+		{name: "owner removes a recruiter",
+			uid:     2,
+			members: []models.CompanyMember{{UserId: 1, Role: models.MemberOwner}, {UserId: 2, Role: models.MemberRecruiter}},
+		},
+		{name: "last owner cannot be removed",
+			uid:     1,
+			members: []models.CompanyMember{{UserId: 1, Role: models.MemberOwner}, {UserId: 2, Role: models.MemberRecruiter}},
+			wantErr: ErrLastOwner,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockUserRepo.EXPECT().GetCompanyMember(uint64(1), uint(1)).Return(models.CompanyMember{Role: models.MemberOwner}, nil)
+			MockUserRepo.EXPECT().GetCompanyMembers(uint64(1)).Return(tt.members, nil)
+			if tt.wantErr == nil {
+				MockUserRepo.EXPECT().RemoveCompanyMember(uint64(1), tt.uid).Return(nil)
+			}
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+			err := s.RemoveCompanyMember(context.Background(), 1, tt.uid, owner)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Service.RemoveCompanyMember() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_DeleteCompany(t *testing.T) {
+	owner := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Roles: []string{models.RoleRecruiter}}
+	tests := []struct {
+		name       string
+		cascade    bool
+		setupMocks func(mr *repository.MockUserRepo, mc *caching.MockCache)
+		wantErr    error
+	}{
+		{name: "recruiter who is not an owner",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetCompanyMember(uint64(3), uint(1)).Return(models.CompanyMember{Role: models.MemberRecruiter}, nil)
+			},
+			wantErr: ErrNotCompanyMember,
+		},
+		{name: "company with jobs is kept without cascade",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetCompanyMember(uint64(3), uint(1)).Return(models.CompanyMember{Role: models.MemberOwner}, nil)
+				mr.EXPECT().CountCompanyJobs(gomock.Any(), uint64(3)).Return(int64(2), nil)
+			},
+			wantErr: ErrCompanyHasJobs,
+		},
+		{name: "cascade deletes the jobs and clears them from the cache",
+			cascade: true,
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetCompanyMember(uint64(3), uint(1)).Return(models.CompanyMember{Role: models.MemberOwner}, nil)
+				mr.EXPECT().CountCompanyJobs(gomock.Any(), uint64(3)).Return(int64(2), nil)
+				mr.EXPECT().DeleteCompany(gomock.Any(), uint64(3)).Return([]uint{7, 8}, nil)
+				mc.EXPECT().DeleteCache(gomock.Any(), uint(7)).Return(nil)
+				mc.EXPECT().DeleteCache(gomock.Any(), uint(8)).Return(nil)
+				mc.EXPECT().InvalidateRecommendations(gomock.Any()).Return(nil)
+			},
+		},
+		{name: "company without jobs",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetCompanyMember(uint64(3), uint(1)).Return(models.CompanyMember{Role: models.MemberOwner}, nil)
+				mr.EXPECT().CountCompanyJobs(gomock.Any(), uint64(3)).Return(int64(0), nil)
+				mr.EXPECT().DeleteCompany(gomock.Any(), uint64(3)).Return(nil, nil)
+			},
+		},
+		{name: "company already deleted",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetCompanyMember(uint64(3), uint(1)).Return(models.CompanyMember{Role: models.MemberOwner}, nil)
+				mr.EXPECT().CountCompanyJobs(gomock.Any(), uint64(3)).Return(int64(0), nil)
+				mr.EXPECT().DeleteCompany(gomock.Any(), uint64(3)).Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrCompanyNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockCache := caching.NewMockCache(mc)
+			tt.setupMocks(MockUserRepo, MockCache)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+			err := s.DeleteCompany(context.Background(), 3, tt.cascade, owner)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Service.DeleteCompany() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_RestoreCompany(t *testing.T) {
+	admin := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "9"}, Roles: []string{models.RoleAdmin}}
+	mc := gomock.NewController(t)
+	MockUserRepo := repository.NewMockUserRepo(mc)
+	MockUserRepo.EXPECT().RestoreCompany(gomock.Any(), uint64(3)).Return(models.Company{}, nil, gorm.ErrRecordNotFound)
+	MockUserRepo.EXPECT().RestoreCompany(gomock.Any(), uint64(4)).Return(models.Company{CompanyName: "tek"}, []uint{7, 8}, nil)
+	// The restored jobs were cached as deleted and left out of the rankings
+	MockCache := caching.NewMockCache(mc)
+	MockCache.EXPECT().DeleteCache(gomock.Any(), uint(7)).Return(nil)
+	MockCache.EXPECT().DeleteCache(gomock.Any(), uint(8)).Return(nil)
+	MockCache.EXPECT().InvalidateRecommendations(gomock.Any()).Return(nil)
+	s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+
+	_, err := s.RestoreCompany(context.Background(), 3, admin)
+	if !errors.Is(err, ErrCompanyNotFound) {
+		t.Errorf("Service.RestoreCompany() error = %v, wantErr %v", err, ErrCompanyNotFound)
+	}
+	got, err := s.RestoreCompany(context.Background(), 4, admin)
+	if err != nil || got.CompanyName != "tek" {
+		t.Errorf("Service.RestoreCompany() = %v, %v", got, err)
+	}
+}
+
+func TestService_UpdateCompany(t *testing.T) {
+	owner := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Roles: []string{models.RoleRecruiter}}
+	mc := gomock.NewController(t)
+	MockUserRepo := repository.NewMockUserRepo(mc)
+	MockUserRepo.EXPECT().GetCompanyMember(uint64(3), uint(1)).Return(models.CompanyMember{Role: models.MemberOwner}, nil)
+	MockUserRepo.EXPECT().UpdateCompany(gomock.Any(), models.Company{Model: gorm.Model{ID: 3}, CompanyName: "tek", Address: "pune", Domain: "it"}).
+		Return(models.Company{Model: gorm.Model{ID: 3}, CompanyName: "tek", Address: "pune", Domain: "it"}, nil)
+	MockUserRepo.EXPECT().CompanyJobIDs(gomock.Any(), uint64(3)).Return(nil, nil)
+	s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+
+	got, err := s.UpdateCompany(context.Background(), 3, models.Company{CompanyName: "tek", Address: "pune", Domain: "it"}, owner)
+	if err != nil || got.ID != 3 || got.Address != "pune" {
+		t.Errorf("Service.UpdateCompany() = %v, %v", got, err)
+	}
+}
@@ -3,77 +3,452 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"job-portal-api/internal/auth"
 	"job-portal-api/internal/models"
 	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog/log"
 	"gorm.io/gorm"
 )
 
-func (s *Service) AddJobDetails(ctx context.Context, cj models.NewJobRequest, cid uint64) (models.Response, error) {
+func (s *Service) AddJobDetails(ctx context.Context, cj models.NewJobRequest, cid uint64, claims auth.Claims) (models.Response, error) {
+	// Only members of the company may post jobs under it
+	err := s.checkCompanyAccess(cid, claims)
+	if err != nil {
+		return models.Response{}, err
+	}
+	invalid := map[string][]uint{}
+	// Deleted companies cannot take new jobs
+	_, err = s.UserRepo.GetCompany(cid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		invalid["companyId"] = []uint{uint(cid)}
+	} else if err != nil {
+		return models.Response{}, err
+	}
+	err = s.checkJobReferences(ctx, invalid, jobRequestReferences(cj))
+	if err != nil {
+		return models.Response{}, err
+	}
+	if expired(cj.ExpiresAt) {
+		return models.Response{}, ErrJobExpiryPassed
+	}
 	// cj.CompanyId = uint64(cid)
-	app := models.Job{
-		CompanyId:           cid,
-		JobTitle:            cj.JobTitle,
-		Salary:              cj.Salary,
-		MinimumNoticePeriod: cj.MinimumNoticePeriod,
-		MaximumNoticePeriod: cj.MaximumNoticePeriod,
-		Budget:              cj.Budget,
-		JobDescription:      cj.JobDescription,
-		MinExperience:       cj.MinExperience,
-	}
-	for _, v := range cj.QualificationIDs {
-		tempData := models.Qualification{
-			Model: gorm.Model{
-				ID: v,
-			},
-		}
-		app.Qualifications = append(app.Qualifications, tempData)
+	// New jobs stay hidden until they are published
+	app := models.Job{CompanyId: cid, Status: models.JobDraft}
+	applyJobRequest(&app, cj)
+	jobData, err := s.UserRepo.PostJob(app)
+	if err != nil {
+		return models.Response{}, err
 	}
-	for _, v := range cj.LocationIDs {
-		tempData := models.Location{
-			Model: gorm.Model{
-				ID: v,
-			},
-		}
-		app.Locations = append(app.Locations, tempData)
+	return jobData, nil
+}
+
+// Errors returned for job changes
+var (
+	ErrJobNotFound        = errors.New("job not found")
+	ErrInvalidSalaryRange = errors.New("salaryMax must not be below salaryMin")
+	ErrJobExpiryPassed    = errors.New("expiresAt must be in the future")
+	ErrJobTransition      = errors.New("job cannot move to this status")
+)
+
+// Errors reported for applications that could not be screened
+var (
+	ErrJobCache = errors.New("cache error")
+	ErrJobLoad  = errors.New("job cannot be loaded")
+)
+
+// InvalidReferencesError lists the ids in a job request that do not point to
+// an existing row, keyed by the request field they were given in
+type InvalidReferencesError struct {
+	// Subject names what refers to the items, a job when empty
+	Subject string
+	Fields  map[string][]uint
+}
+
+func (e *InvalidReferencesError) Error() string {
+	if e.Subject == "" {
+		return "job refers to items that do not exist"
 	}
-	for _, v := range cj.SkillIDs {
-		tempData := models.Skill{
-			Model: gorm.Model{
-				ID: v,
-			},
+	return e.Subject + " refers to items that do not exist"
+}
+
+// jobReference is one id list of a job request and the taxonomy it points into
+type jobReference struct {
+	field string
+	kind  models.TaxonomyKind
+	ids   []uint
+}
+
+func jobRequestReferences(cj models.NewJobRequest) []jobReference {
+	return append([]jobReference{
+		{field: "LocationIDs", kind: models.LocationKind, ids: cj.LocationIDs},
+		{field: "SkillIDs", kind: models.SkillKind, ids: cj.SkillIDs},
+		{field: "WorkModeIDs", kind: models.WorkModeKind, ids: cj.WorkModeIDs},
+		{field: "QualificationIDs", kind: models.QualificationKind, ids: cj.QualificationIDs},
+		{field: "ShiftIDs", kind: models.ShiftKind, ids: cj.ShiftIDs},
+		{field: "JobTypeIDs", kind: models.JobTypeKind, ids: cj.JobTypeIDs},
+	}, matchRulesReferences(cj.MatchRules)...)
+}
+
+// jobPatchReferences returns only the id lists present in the patch
+func jobPatchReferences(p models.JobPatchRequest) []jobReference {
+	var refs []jobReference
+	add := func(field string, kind models.TaxonomyKind, ids *[]uint) {
+		if ids != nil {
+			refs = append(refs, jobReference{field: field, kind: kind, ids: *ids})
 		}
-		app.Skills = append(app.Skills, tempData)
 	}
-	for _, v := range cj.WorkModeIDs {
-		tempData := models.WorkMode{
-			Model: gorm.Model{
-				ID: v,
-			},
-		}
-		app.WorkModes = append(app.WorkModes, tempData)
+	add("LocationIDs", models.LocationKind, p.LocationIDs)
+	add("SkillIDs", models.SkillKind, p.SkillIDs)
+	add("WorkModeIDs", models.WorkModeKind, p.WorkModeIDs)
+	add("QualificationIDs", models.QualificationKind, p.QualificationIDs)
+	add("ShiftIDs", models.ShiftKind, p.ShiftIDs)
+	add("JobTypeIDs", models.JobTypeKind, p.JobTypeIDs)
+	return append(refs, matchRulesReferences(p.MatchRules)...)
+}
+
+// matchRulesReferences returns the id lists of match rules, nil rules have none
+func matchRulesReferences(r *models.MatchRules) []jobReference {
+	if r == nil {
+		return nil
 	}
-	for _, v := range cj.ShiftIDs {
-		tempData := models.Shift{
-			Model: gorm.Model{
-				ID: v,
-			},
-		}
-		app.Shifts = append(app.Shifts, tempData)
+	return []jobReference{
+		{field: "matchRules.requiredSkillIDs", kind: models.SkillKind, ids: r.RequiredSkillIDs},
+		{field: "matchRules.requiredQualificationIDs", kind: models.QualificationKind, ids: r.RequiredQualificationIDs},
 	}
-	for _, v := range cj.JobTypeIDs {
-		tempData := models.JobType{
-			Model: gorm.Model{
-				ID: v,
-			},
+}
+
+// checkJobReferences adds the ids that do not exist to invalid and returns an
+// InvalidReferencesError when anything is in it, so gorm never gets to create
+// empty rows for unknown ids
+func (s *Service) checkJobReferences(ctx context.Context, invalid map[string][]uint, refs []jobReference) error {
+	for _, ref := range refs {
+		if len(ref.ids) == 0 {
+			continue
+		}
+		missing, err := s.UserRepo.MissingTaxonomyIDs(ctx, ref.kind, ref.ids)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			invalid[ref.field] = missing
 		}
-		app.JobTypes = append(app.JobTypes, tempData)
 	}
-	jobData, err := s.UserRepo.PostJob(app)
+	if len(invalid) > 0 {
+		return &InvalidReferencesError{Fields: invalid}
+	}
+	return nil
+}
+
+// applyJobRequest copies every field of a job request onto the job
+func applyJobRequest(j *models.Job, cj models.NewJobRequest) {
+	j.JobTitle = cj.JobTitle
+	j.SalaryMin = cj.SalaryMin
+	j.SalaryMax = cj.SalaryMax
+	j.SalaryCurrency = cj.SalaryCurrency
+	j.SalaryPeriod = cj.SalaryPeriod
+	j.SalaryHidden = cj.SalaryHidden
+	j.MinimumNoticePeriod = cj.MinimumNoticePeriod
+	j.MaximumNoticePeriod = cj.MaximumNoticePeriod
+	j.Budget = cj.Budget
+	j.JobDescription = cj.JobDescription
+	j.MinExperience = cj.MinExperience
+	j.MaxExperience = cj.MaxExperience
+	j.Locations = locationsFromIDs(cj.LocationIDs)
+	j.Skills = skillsFromIDs(cj.SkillIDs)
+	j.WorkModes = workModesFromIDs(cj.WorkModeIDs)
+	j.Qualifications = qualificationsFromIDs(cj.QualificationIDs)
+	j.Shifts = shiftsFromIDs(cj.ShiftIDs)
+	j.JobTypes = jobTypesFromIDs(cj.JobTypeIDs)
+	j.ExpiresAt = cj.ExpiresAt
+	j.MatchRules = cj.MatchRules
+}
+
+// applyJobPatch copies the fields present in a patch onto the job
+func applyJobPatch(j *models.Job, p models.JobPatchRequest) {
+	if p.JobTitle != nil {
+		j.JobTitle = *p.JobTitle
+	}
+	if p.SalaryMin != nil {
+		j.SalaryMin = *p.SalaryMin
+	}
+	if p.SalaryMax != nil {
+		j.SalaryMax = *p.SalaryMax
+	}
+	if p.SalaryCurrency != nil {
+		j.SalaryCurrency = *p.SalaryCurrency
+	}
+	if p.SalaryPeriod != nil {
+		j.SalaryPeriod = *p.SalaryPeriod
+	}
+	if p.SalaryHidden != nil {
+		j.SalaryHidden = *p.SalaryHidden
+	}
+	if p.MinimumNoticePeriod != nil {
+		j.MinimumNoticePeriod = *p.MinimumNoticePeriod
+	}
+	if p.MaximumNoticePeriod != nil {
+		j.MaximumNoticePeriod = *p.MaximumNoticePeriod
+	}
+	if p.Budget != nil {
+		j.Budget = *p.Budget
+	}
+	if p.JobDescription != nil {
+		j.JobDescription = *p.JobDescription
+	}
+	if p.MinExperience != nil {
+		j.MinExperience = *p.MinExperience
+	}
+	if p.MaxExperience != nil {
+		j.MaxExperience = *p.MaxExperience
+	}
+	if p.LocationIDs != nil {
+		j.Locations = locationsFromIDs(*p.LocationIDs)
+	}
+	if p.SkillIDs != nil {
+		j.Skills = skillsFromIDs(*p.SkillIDs)
+	}
+	if p.WorkModeIDs != nil {
+		j.WorkModes = workModesFromIDs(*p.WorkModeIDs)
+	}
+	if p.QualificationIDs != nil {
+		j.Qualifications = qualificationsFromIDs(*p.QualificationIDs)
+	}
+	if p.ShiftIDs != nil {
+		j.Shifts = shiftsFromIDs(*p.ShiftIDs)
+	}
+	if p.JobTypeIDs != nil {
+		j.JobTypes = jobTypesFromIDs(*p.JobTypeIDs)
+	}
+	if p.MatchRules != nil {
+		j.MatchRules = p.MatchRules
+	}
+	if p.ExpiresAt != nil {
+		j.ExpiresAt = p.ExpiresAt
+	}
+}
+
+func locationsFromIDs(ids []uint) []models.Location {
+	l := make([]models.Location, 0, len(ids))
+	for _, v := range ids {
+		l = append(l, models.Location{Model: gorm.Model{ID: v}})
+	}
+	return l
+}
+
+func skillsFromIDs(ids []uint) []models.Skill {
+	l := make([]models.Skill, 0, len(ids))
+	for _, v := range ids {
+		l = append(l, models.Skill{Model: gorm.Model{ID: v}})
+	}
+	return l
+}
+
+func workModesFromIDs(ids []uint) []models.WorkMode {
+	l := make([]models.WorkMode, 0, len(ids))
+	for _, v := range ids {
+		l = append(l, models.WorkMode{Model: gorm.Model{ID: v}})
+	}
+	return l
+}
+
+func qualificationsFromIDs(ids []uint) []models.Qualification {
+	l := make([]models.Qualification, 0, len(ids))
+	for _, v := range ids {
+		l = append(l, models.Qualification{Model: gorm.Model{ID: v}})
+	}
+	return l
+}
+
+func shiftsFromIDs(ids []uint) []models.Shift {
+	l := make([]models.Shift, 0, len(ids))
+	for _, v := range ids {
+		l = append(l, models.Shift{Model: gorm.Model{ID: v}})
+	}
+	return l
+}
+
+func jobTypesFromIDs(ids []uint) []models.JobType {
+	l := make([]models.JobType, 0, len(ids))
+	for _, v := range ids {
+		l = append(l, models.JobType{Model: gorm.Model{ID: v}})
+	}
+	return l
+}
+
+// jobForChange loads a job the caller is allowed to change
+func (s *Service) jobForChange(ctx context.Context, jid uint64, claims auth.Claims) (models.Job, error) {
+	j, err := s.UserRepo.GetJob(ctx, jid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Job{}, ErrJobNotFound
+	}
 	if err != nil {
-		return models.Response{}, err
+		return models.Job{}, err
+	}
+	err = s.checkCompanyAccess(j.CompanyId, claims)
+	if err != nil {
+		return models.Job{}, err
+	}
+	return j, nil
+}
+
+// invalidateJob drops the job from the cache used by ProcessJobApplications,
+// the database change already happened so a failure is only logged
+func (s *Service) invalidateJob(ctx context.Context, jid uint) {
+	err := s.rdb.DeleteCache(ctx, jid)
+	if err != nil {
+		log.Error().Err(err).Uint("Job Id", jid).Msg("invalidating cached job")
 	}
-	return jobData, nil
+}
+
+// invalidateJobs drops the changed jobs from the cache and, when there are
+// any, outdates the recommendations ranked with them once for the batch
+func (s *Service) invalidateJobs(ctx context.Context, jobIds ...uint) {
+	if len(jobIds) == 0 {
+		return
+	}
+	for _, jid := range jobIds {
+		s.invalidateJob(ctx, jid)
+	}
+	s.invalidateRecommendations(ctx)
+}
+
+// UpdateJob replaces a job and all its associations
+func (s *Service) UpdateJob(ctx context.Context, jid uint64, cj models.NewJobRequest, claims auth.Claims) (models.Job, error) {
+	j, err := s.jobForChange(ctx, jid, claims)
+	if err != nil {
+		return models.Job{}, err
+	}
+	err = s.checkJobReferences(ctx, map[string][]uint{}, jobRequestReferences(cj))
+	if err != nil {
+		return models.Job{}, err
+	}
+	if expired(cj.ExpiresAt) {
+		return models.Job{}, ErrJobExpiryPassed
+	}
+	applyJobRequest(&j, cj)
+	j, err = s.UserRepo.UpdateJob(ctx, j)
+	if err != nil {
+		return models.Job{}, err
+	}
+	s.invalidateJobs(ctx, uint(jid))
+	return j, nil
+}
+
+// PatchJob changes the fields present in the patch
+func (s *Service) PatchJob(ctx context.Context, jid uint64, p models.JobPatchRequest, claims auth.Claims) (models.Job, error) {
+	j, err := s.jobForChange(ctx, jid, claims)
+	if err != nil {
+		return models.Job{}, err
+	}
+	err = s.checkJobReferences(ctx, map[string][]uint{}, jobPatchReferences(p))
+	if err != nil {
+		return models.Job{}, err
+	}
+	if expired(p.ExpiresAt) {
+		return models.Job{}, ErrJobExpiryPassed
+	}
+	applyJobPatch(&j, p)
+	// The patch may change only one end of the range
+	if j.SalaryMax < j.SalaryMin {
+		return models.Job{}, ErrInvalidSalaryRange
+	}
+	j, err = s.UserRepo.UpdateJob(ctx, j)
+	if err != nil {
+		return models.Job{}, err
+	}
+	s.invalidateJobs(ctx, uint(jid))
+	return j, nil
+}
+
+// DeleteJob soft deletes a job, it stops showing up in listings and matching
+func (s *Service) DeleteJob(ctx context.Context, jid uint64, claims auth.Claims) error {
+	_, err := s.jobForChange(ctx, jid, claims)
+	if err != nil {
+		return err
+	}
+	err = s.UserRepo.DeleteJob(ctx, jid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrJobNotFound
+	}
+	if err != nil {
+		return err
+	}
+	s.invalidateJobs(ctx, uint(jid))
+	return nil
+}
+
+// PublishJob makes a job visible to candidates and open for applications. A
+// new expiry date may be given, publishing an expired job needs one.
+func (s *Service) PublishJob(ctx context.Context, jid uint64, expiresAt *time.Time, claims auth.Claims) (models.Job, error) {
+	j, err := s.jobForChange(ctx, jid, claims)
+	if err != nil {
+		return models.Job{}, err
+	}
+	if expiresAt == nil {
+		expiresAt = j.ExpiresAt
+	}
+	if expired(expiresAt) {
+		return models.Job{}, ErrJobExpiryPassed
+	}
+	return s.moveJob(ctx, j, models.JobPublished, expiresAt)
+}
+
+// PauseJob hides a published job until it is published again
+func (s *Service) PauseJob(ctx context.Context, jid uint64, claims auth.Claims) (models.Job, error) {
+	j, err := s.jobForChange(ctx, jid, claims)
+	if err != nil {
+		return models.Job{}, err
+	}
+	return s.moveJob(ctx, j, models.JobPaused, j.ExpiresAt)
+}
+
+// CloseJob ends a job for good, closed jobs cannot be published again
+func (s *Service) CloseJob(ctx context.Context, jid uint64, claims auth.Claims) (models.Job, error) {
+	j, err := s.jobForChange(ctx, jid, claims)
+	if err != nil {
+		return models.Job{}, err
+	}
+	return s.moveJob(ctx, j, models.JobClosed, j.ExpiresAt)
+}
+
+// moveJob changes the status of a job when models.JobTransitions allows it
+func (s *Service) moveJob(ctx context.Context, j models.Job, status string, expiresAt *time.Time) (models.Job, error) {
+	if !j.CanMoveTo(status) {
+		return models.Job{}, ErrJobTransition
+	}
+	// The status is checked again in the update, another request may have
+	// moved the job since it was loaded
+	err := s.UserRepo.SetJobStatus(ctx, uint64(j.ID), j.Status, status, expiresAt)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Job{}, ErrJobTransition
+	}
+	if err != nil {
+		return models.Job{}, err
+	}
+	s.invalidateJobs(ctx, j.ID)
+	j.Status = status
+	j.ExpiresAt = expiresAt
+	return j, nil
+}
+
+// ExpireJobs moves every published or paused job past its expiry date to
+// expired, it is run periodically from main
+func (s *Service) ExpireJobs(ctx context.Context) error {
+	ids, err := s.UserRepo.ExpireJobs(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	s.invalidateJobs(ctx, ids...)
+	return nil
+}
+
+// expired reports whether an expiry date is set and not in the future
+func expired(expiresAt *time.Time) bool {
+	return expiresAt != nil && !expiresAt.After(time.Now())
 }
 
 func (s *Service) ViewJobFromCompany(cid uint64) ([]models.Job, error) {
@@ -81,22 +456,123 @@ func (s *Service) ViewJobFromCompany(cid uint64) ([]models.Job, error) {
 	if err != nil {
 		return []models.Job{}, err
 	}
+	hideForListings(jobData)
 	return jobData, nil
 }
 
-func (s *Service) ViewAllJobs(ctx context.Context) ([]models.Job, error) {
-	jobDatas, err := s.UserRepo.GetAllJobs()
+// ViewAllJobs returns one page of the jobs matching the query with the facet
+// counts of all matching jobs, the paging and sorting fields left empty get
+// their defaults
+func (s *Service) ViewAllJobs(ctx context.Context, q models.JobListQuery, claims auth.Claims) (models.JobPage, error) {
+	err := s.checkStatusAccess(&q, claims)
 	if err != nil {
-		return nil, err
+		return models.JobPage{}, err
+	}
+	pageDefaults(&q)
+	if q.Sort == "" {
+		q.Sort = "created_at"
+	}
+	jobs, total, err := s.UserRepo.ListJobs(ctx, q)
+	if err != nil {
+		return models.JobPage{}, err
+	}
+	facets, err := s.UserRepo.CountJobFacets(ctx, models.JobSearchQuery{JobListQuery: q})
+	if err != nil {
+		return models.JobPage{}, err
+	}
+	hideForListings(jobs)
+	return models.JobPage{Jobs: jobs, Total: total, Page: q.Page, PageSize: q.PageSize, Facets: facets}, nil
+}
+
+// SearchJobs returns one page of the jobs matching the search text, most
+// relevant first unless the query asks for another sort
+func (s *Service) SearchJobs(ctx context.Context, q models.JobSearchQuery, claims auth.Claims) (models.JobSearchPage, error) {
+	err := s.checkStatusAccess(&q.JobListQuery, claims)
+	if err != nil {
+		return models.JobSearchPage{}, err
+	}
+	pageDefaults(&q.JobListQuery)
+	hits, total, err := s.UserRepo.SearchJobs(ctx, q)
+	if err != nil {
+		return models.JobSearchPage{}, err
+	}
+	facets, err := s.UserRepo.CountJobFacets(ctx, q)
+	if err != nil {
+		return models.JobSearchPage{}, err
+	}
+	for i := range hits {
+		hideSalary(&hits[i].Job)
+		hideMatchRules(&hits[i].Job)
+	}
+	return models.JobSearchPage{Results: hits, Total: total, Page: q.Page, PageSize: q.PageSize, Facets: facets}, nil
+}
+
+// hideSalary blanks the salary amounts of a job that keeps its salary hidden,
+// the listings are open to every role
+func hideSalary(j *models.Job) {
+	if j.SalaryHidden {
+		j.SalaryMin = 0
+		j.SalaryMax = 0
+	}
+}
+
+// hideMatchRules blanks the screening rules of a job and of its company. A
+// candidate who could read them could apply with exactly what they ask for.
+func hideMatchRules(j *models.Job) {
+	j.MatchRules = nil
+	j.Comp.MatchRules = nil
+}
+
+// hideForListings blanks what the listings must not show of the jobs
+func hideForListings(jobs []models.Job) {
+	for i := range jobs {
+		hideSalary(&jobs[i])
+		hideMatchRules(&jobs[i])
+	}
+}
+
+// checkStatusAccess limits the listings to published jobs unless other
+// statuses are asked for, only admins and members of the company the query is
+// filtered by may see drafts, paused and finished jobs
+func (s *Service) checkStatusAccess(q *models.JobListQuery, claims auth.Claims) error {
+	if len(q.Statuses) == 0 {
+		q.Statuses = []string{models.JobPublished}
+		return nil
 	}
-	return jobDatas, nil
+	if len(q.Statuses) == 1 && q.Statuses[0] == models.JobPublished {
+		return nil
+	}
+	if claims.HasRole(models.RoleAdmin) {
+		return nil
+	}
+	if q.CompanyId == nil {
+		return ErrNotCompanyMember
+	}
+	return s.checkCompanyAccess(*q.CompanyId, claims)
+}
 
+// pageDefaults fills in the paging fields left empty and caps the page size
+func pageDefaults(q *models.JobListQuery) {
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.PageSize == 0 {
+		q.PageSize = models.DefaultJobPageSize
+	}
+	if q.PageSize > models.MaxJobPageSize {
+		q.PageSize = models.MaxJobPageSize
+	}
+	if q.Order == "" {
+		q.Order = "desc"
+	}
 }
+
 func (s *Service) ViewJobById(ctx context.Context, jid uint64) ([]models.Job, error) {
 	jobData, err := s.UserRepo.GetOneJob(jid)
 	if err != nil {
 		return []models.Job{}, nil
 	}
+	hideForListings(jobData)
 	return jobData, nil
 }
 
@@ -230,141 +706,132 @@ func (s *Service) ViewJobById(ctx context.Context, jid uint64) ([]models.Job, er
 
 
 
-func(s *Service)ProcessJobApplications(applications []models.NewUserApplication)([]models.NewUserApplication,error){
-	ctx:=context.Background()
+// ProcessJobApplications screens every application against its job and
+// returns a result for each of them, in the order they were given. At most
+// screeningWorkers applications are screened at once and screening stops
+// with the error of ctx once it is done.
+func (s *Service) ProcessJobApplications(ctx context.Context, applications []models.NewUserApplication) ([]models.ScreeningResult, error) {
+	results := make([]models.ScreeningResult, len(applications))
+	next := make(chan int)
 	wg := new(sync.WaitGroup)
-	ch := make(chan models.NewUserApplication)
-	var finalData []models.NewUserApplication
-
-	for _,v := range applications{
+	for w := 0; w < screeningWorkers && w < len(applications); w++ {
 		wg.Add(1)
-		go func (application models.NewUserApplication)  {
+		go func() {
 			defer wg.Done()
+			for i := range next {
+				results[i] = s.screenApplication(ctx, applications[i])
+			}
+		}()
+	}
 
-			var jobData models.Job
+feed:
+	for i := range applications {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
 
-			val,err := s.rdb.GetCache(ctx,uint(application.ID))
+	// Results screened after ctx was done may hold errors caused by it
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
 
-			if err!=nil {
-				jobDataFromDB, err := s.UserRepo.FetchJobData(application.ID)
-				if err!=nil{
-					log.Error().Err(err).Msg("invalid application job id does not exists")
-					return
-				}
-				err = s.rdb.AddCache(ctx,uint(application.ID),jobDataFromDB)
-				if err!=nil{
+// StreamJobApplications screens the applications received on in and sends
+// the result of each on the returned channel as soon as it is screened, so
+// results come in the order they finish. The channel is closed once in is
+// closed and drained, or ctx is done.
+func (s *Service) StreamJobApplications(ctx context.Context, in <-chan models.StreamedApplication) <-chan models.ScreeningResult {
+	out := make(chan models.ScreeningResult)
+	wg := new(sync.WaitGroup)
+	for w := 0; w < screeningWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var a models.StreamedApplication
+				var ok bool
+				select {
+				case a, ok = <-in:
+					if !ok {
+						return
+					}
+				case <-ctx.Done():
 					return
 				}
-				jobData = jobDataFromDB
-			}else{
-				err = json.Unmarshal([]byte(val),&jobData)
-				if err!=nil{
+				r := s.screenApplication(ctx, a.Application)
+				r.Line = a.Line
+				select {
+				case out <- r:
+				case <-ctx.Done():
 					return
 				}
 			}
-			check := s.compareData(application,jobData)
-
-			if check {
-				ch <- application
-			}
-
-		}(v)
+		}()
 	}
-
-	go func ()  {
+	go func() {
 		wg.Wait()
-		close(ch)
+		close(out)
 	}()
-
-	for v := range ch {
-		finalData = append(finalData, v)
-	}
-
-	return finalData, nil
+	return out
 }
 
-func(s *Service) compareData(application models.NewUserApplication, jobData models.Job)bool{
-	totalFields := 0
-	matchedFields := 0
-
-	totalFields++
-	if application.Jobs.NoticePeriod>=jobData.MinimumNoticePeriod && application.Jobs.NoticePeriod<=int(jobData.MaximumNoticePeriod){
-		matchedFields++
-	}
-
-	totalFields++
-	if application.Jobs.Experience>=jobData.MinExperience && application.Jobs.Experience<=(jobData.MaxExperience){
-		matchedFields++
-	}
-
-	count := 0
-	totalFields++
-	for _,v := range application.Jobs.Location{
-		for _,v1 := range jobData.Locations{
-			if v == v1.ID{
-				count++
-			}
-		}
+// screenApplication matches one application against its job
+func (s *Service) screenApplication(ctx context.Context, application models.NewUserApplication) models.ScreeningResult {
+	result := models.ScreeningResult{Application: application, Verdict: models.VerdictError}
+	jobData, err := s.screeningJob(ctx, application.ID)
+	if err != nil {
+		result.Error = err.Error()
+		return result
 	}
-	if count!=0 {
-		matchedFields++
+	// Drafts, paused and finished jobs take no applications
+	if !jobData.IsOpen(time.Now()) {
+		result.Error = ErrJobNotOpen.Error()
+		return result
 	}
-
-	count = 0
-	totalFields++
-	for _,v := range application.Jobs.Skills{
-		for _,v1 := range jobData.Skills{
-			if v == v1.ID{
-				count++
-			}
-		}
-	}
-	if count!=0 {
-		matchedFields++
-	}
-
-	count = 0
-	totalFields++
-	for _,v := range application.Jobs.Qualifications{
-		for _,v1 := range jobData.Qualifications{
-			if v == v1.ID{
-				count++
-			}
-		}
-	}
-	if count!=0 {
-		matchedFields++
+	match := s.compareData(application, jobData)
+	result.Match = &match
+	result.Verdict = models.VerdictNotMatched
+	if match.Matched {
+		result.Verdict = models.VerdictMatched
 	}
+	return result
+}
 
-	count = 0
-	totalFields++
-	for _,v := range application.Jobs.Shift{
-		for _,v1 := range jobData.Shifts{
-			if v == v1.ID{
-				count++
-			}
+// screeningJob returns the job from the cache, or from the database when it
+// is not cached yet
+func (s *Service) screeningJob(ctx context.Context, jid uint64) (models.Job, error) {
+	var jobData models.Job
+	val, err := s.rdb.GetCache(ctx, uint(jid))
+	if err == nil {
+		err = json.Unmarshal([]byte(val), &jobData)
+		if err != nil {
+			log.Error().Err(err).Uint64("Job Id", jid).Msg("reading cached job")
+			return models.Job{}, ErrJobCache
 		}
+		return jobData, nil
 	}
-	if count!=0 {
-		matchedFields++
+	if !errors.Is(err, redis.Nil) {
+		log.Error().Err(err).Uint64("Job Id", jid).Msg("reading cached job")
+		return models.Job{}, ErrJobCache
 	}
-
-	count = 0
-	totalFields++
-	for _,v := range application.Jobs.JobType{
-		for _,v1 := range jobData.JobTypes{
-			if v == v1.ID{
-				count++
-			}
-		}
+	jobData, err = s.UserRepo.FetchJobData(ctx, jid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Job{}, ErrJobNotFound
 	}
-	if count!=0 {
-		matchedFields++
+	if err != nil {
+		return models.Job{}, ErrJobLoad
 	}
-
-	if matchedFields*2 >= totalFields {
-		return true
+	// The job was found, failing to cache it only costs the next lookup
+	err = s.rdb.AddCache(ctx, uint(jid), jobData)
+	if err != nil {
+		log.Error().Err(err).Uint64("Job Id", jid).Msg("caching job")
 	}
-
-	return false
-}
\ No newline at end of file
+	return jobData, nil
+}
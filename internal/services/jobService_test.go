@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"job-portal-api/internal/auth"
 	"job-portal-api/internal/caching"
@@ -9,8 +10,12 @@ import (
 	"job-portal-api/internal/repository"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 func TestService_ViewJobFromCompany(t *testing.T) {
@@ -27,10 +32,10 @@ func TestService_ViewJobFromCompany(t *testing.T) {
 	}{
 		{name: "success if jobs are retrieved from cid",
 			args:    args{cid: 10},
-			want:    []models.Job{{JobTitle: "sde", Salary: "10,000"}, {JobTitle: "qa tester", Salary: "5,000"}},
+			want:    []models.Job{{JobTitle: "sde", SalaryMin: 10000}, {JobTitle: "qa tester", SalaryMin: 5000}},
 			wantErr: false,
 			mockRepoResponse: func() ([]models.Job, error) {
-				return []models.Job{{JobTitle: "sde", Salary: "10,000"}, {JobTitle: "qa tester", Salary: "5,000"}}, nil
+				return []models.Job{{JobTitle: "sde", SalaryMin: 10000}, {JobTitle: "qa tester", SalaryMin: 5000}}, nil
 
 			},
 		},
@@ -51,7 +56,7 @@ func TestService_ViewJobFromCompany(t *testing.T) {
 			if tt.mockRepoResponse != nil {
 				MockUserRepo.EXPECT().GetJobsFromCompany(gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
 			}
-			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{})
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
 			got, err := s.ViewJobFromCompany(tt.args.cid)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.ViewJobFromCompany() error = %v, wantErr %v", err, tt.wantErr)
@@ -65,45 +70,53 @@ func TestService_ViewJobFromCompany(t *testing.T) {
 }
 
 func TestService_ViewAllJobs(t *testing.T) {
-	type args struct {
-		ctx context.Context
-	}
+	published := []string{models.JobPublished}
 	tests := []struct {
-		name string
-		//s       *Service
-		args             args
-		want             []models.Job
-		wantErr          bool
-		mockRepoResponse func() ([]models.Job, error)
+		name      string
+		query     models.JobListQuery
+		wantQuery models.JobListQuery
+		repoErr   error
+		facetErr  error
+		want      models.JobPage
+		wantErr   bool
 	}{
-		{name: "success if all jobs are retrieved ",
-			args:    args{context.Background()},
-			want:    []models.Job{{JobTitle: "sde", Salary: "10,000"}, {JobTitle: "qa tester", Salary: "5,000"}},
-			wantErr: false,
-			mockRepoResponse: func() ([]models.Job, error) {
-				return []models.Job{{JobTitle: "sde", Salary: "10,000"}, {JobTitle: "qa tester", Salary: "5,000"}}, nil
-
-			},
+		{name: "defaults filled in",
+			query:     models.JobListQuery{SkillIDs: []uint{1}},
+			wantQuery: models.JobListQuery{Page: 1, PageSize: models.DefaultJobPageSize, Sort: "created_at", Order: "desc", Statuses: published, SkillIDs: []uint{1}},
+			want: models.JobPage{Jobs: []models.Job{{JobTitle: "sde", SalaryMin: 10000}, {JobTitle: "qa tester", SalaryMin: 5000}},
+				Total: 12, Page: 1, PageSize: models.DefaultJobPageSize,
+				Facets: models.JobFacets{Skills: []models.FacetCount{{ID: 1, Name: "go", Count: 12}, {ID: 2, Name: "java", Count: 4}}}},
+		},
+		{name: "page size capped",
+			query:     models.JobListQuery{Page: 3, PageSize: 1000, Sort: "budget", Order: "asc"},
+			wantQuery: models.JobListQuery{Page: 3, PageSize: models.MaxJobPageSize, Sort: "budget", Order: "asc", Statuses: published},
+			want: models.JobPage{Jobs: []models.Job{{JobTitle: "sde", SalaryMin: 10000}, {JobTitle: "qa tester", SalaryMin: 5000}},
+				Total: 12, Page: 3, PageSize: models.MaxJobPageSize},
 		},
 		{name: "failure if jobs are  not retrieved ",
-			args:    args{context.Background()},
-			want:    nil,
-			wantErr: true,
-			mockRepoResponse: func() ([]models.Job, error) {
-				return []models.Job{}, errors.New("all jobs not fetched")
-
-			},
+			wantQuery: models.JobListQuery{Page: 1, PageSize: models.DefaultJobPageSize, Sort: "created_at", Order: "desc", Statuses: published},
+			repoErr:   errors.New("all jobs not fetched"),
+			wantErr:   true,
+		},
+		{name: "failure if facets are not counted",
+			wantQuery: models.JobListQuery{Page: 1, PageSize: models.DefaultJobPageSize, Sort: "created_at", Order: "desc", Statuses: published},
+			facetErr:  errors.New("facets cannot be counted"),
+			wantErr:   true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mc := gomock.NewController(t)
 			MockUserRepo := repository.NewMockUserRepo(mc)
-			if tt.mockRepoResponse != nil {
-				MockUserRepo.EXPECT().GetAllJobs().Return(tt.mockRepoResponse()).AnyTimes()
+			if tt.repoErr != nil {
+				MockUserRepo.EXPECT().ListJobs(gomock.Any(), tt.wantQuery).Return(nil, int64(0), tt.repoErr)
+			} else {
+				MockUserRepo.EXPECT().ListJobs(gomock.Any(), tt.wantQuery).Return(tt.want.Jobs, tt.want.Total, nil)
+				// Facets count over the same filters
+				MockUserRepo.EXPECT().CountJobFacets(gomock.Any(), models.JobSearchQuery{JobListQuery: tt.wantQuery}).Return(tt.want.Facets, tt.facetErr)
 			}
-			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{})
-			got, err := s.ViewAllJobs(tt.args.ctx)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+			got, err := s.ViewAllJobs(context.Background(), tt.query, auth.Claims{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.ViewAllJobs() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -115,6 +128,36 @@ func TestService_ViewAllJobs(t *testing.T) {
 	}
 }
 
+func TestService_SearchJobs(t *testing.T) {
+	mc := gomock.NewController(t)
+	MockUserRepo := repository.NewMockUserRepo(mc)
+	// Without a sort the repository orders by relevance
+	MockUserRepo.EXPECT().SearchJobs(gomock.Any(), models.JobSearchQuery{
+		Q:            "golang",
+		JobListQuery: models.JobListQuery{Page: 1, PageSize: models.DefaultJobPageSize, Order: "desc", Statuses: []string{models.JobPublished}, SkillIDs: []uint{3}},
+	}).Return([]models.JobSearchHit{{Job: models.Job{JobTitle: "golang developer"}, Rank: 0.8}}, int64(1), nil)
+	MockUserRepo.EXPECT().CountJobFacets(gomock.Any(), models.JobSearchQuery{
+		Q:            "golang",
+		JobListQuery: models.JobListQuery{Page: 1, PageSize: models.DefaultJobPageSize, Order: "desc", Statuses: []string{models.JobPublished}, SkillIDs: []uint{3}},
+	}).Return(models.JobFacets{Companies: []models.FacetCount{{ID: 2, Name: "tek", Count: 1}}}, nil)
+
+	s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+	got, err := s.SearchJobs(context.Background(), models.JobSearchQuery{Q: "golang", JobListQuery: models.JobListQuery{SkillIDs: []uint{3}}}, auth.Claims{})
+	if err != nil {
+		t.Fatalf("Service.SearchJobs() error = %v", err)
+	}
+	want := models.JobSearchPage{
+		Results:  []models.JobSearchHit{{Job: models.Job{JobTitle: "golang developer"}, Rank: 0.8}},
+		Total:    1,
+		Page:     1,
+		PageSize: models.DefaultJobPageSize,
+		Facets:   models.JobFacets{Companies: []models.FacetCount{{ID: 2, Name: "tek", Count: 1}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Service.SearchJobs() = %v, want %v", got, want)
+	}
+}
+
 func TestService_ViewJobById(t *testing.T) {
 	type args struct {
 		ctx context.Context
@@ -133,10 +176,10 @@ func TestService_ViewJobById(t *testing.T) {
 				ctx: context.Background(),
 				jid: 10,
 			},
-			want:    []models.Job{{JobTitle: "sde", Salary: "10,000"}, {JobTitle: "qa tester", Salary: "5,000"}},
+			want:    []models.Job{{JobTitle: "sde", SalaryMin: 10000}, {JobTitle: "qa tester", SalaryMin: 5000}},
 			wantErr: false,
 			mockRepoResponse: func() ([]models.Job, error) {
-				return []models.Job{{JobTitle: "sde", Salary: "10,000"}, {JobTitle: "qa tester", Salary: "5,000"}}, nil
+				return []models.Job{{JobTitle: "sde", SalaryMin: 10000}, {JobTitle: "qa tester", SalaryMin: 5000}}, nil
 
 			},
 		},
@@ -159,7 +202,7 @@ func TestService_ViewJobById(t *testing.T) {
 			if tt.mockRepoResponse != nil {
 				MockUserRepo.EXPECT().GetOneJob(gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
 			}
-			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{})
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
 			got, err := s.ViewJobById(tt.args.ctx, tt.args.jid)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.ViewJobById() error = %v, wantErr %v", err, tt.wantErr)
@@ -194,7 +237,10 @@ func TestService_AddJobDetails(t *testing.T) {
 				ctx: context.Background(),
 				cj: models.NewJobRequest{
 					JobTitle:            "job",
-					Salary:              "10000",
+					SalaryMin:           10000,
+					SalaryMax:           20000,
+					SalaryCurrency:      "INR",
+					SalaryPeriod:        models.PayMonthly,
 					MinimumNoticePeriod: int(10),
 					MaximumNoticePeriod: uint64(20),
 					Budget:              float64(10),
@@ -237,7 +283,10 @@ func TestService_AddJobDetails(t *testing.T) {
 				ctx: context.Background(),
 				cj: models.NewJobRequest{
 					JobTitle:            "job ",
-					Salary:              "10000",
+					SalaryMin:           10000,
+					SalaryMax:           20000,
+					SalaryCurrency:      "INR",
+					SalaryPeriod:        models.PayMonthly,
 					MinimumNoticePeriod: int(10),
 					MaximumNoticePeriod: uint64(20),
 					Budget:              float64(10),
@@ -267,9 +316,12 @@ func TestService_AddJobDetails(t *testing.T) {
 			if tt.mockRepoResponse != nil {
 				MockUserRepo.EXPECT().PostJob(gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
 			}
-			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{})
+			MockUserRepo.EXPECT().GetCompanyMember(gomock.Any(), gomock.Any()).Return(models.CompanyMember{Role: models.MemberRecruiter}, nil).AnyTimes()
+			MockUserRepo.EXPECT().GetCompany(gomock.Any()).Return(models.Company{}, nil).AnyTimes()
+			MockUserRepo.EXPECT().MissingTaxonomyIDs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
 
-			got, err := s.AddJobDetails(tt.args.ctx, tt.args.cj, tt.args.cid)
+			got, err := s.AddJobDetails(tt.args.ctx, tt.args.cj, tt.args.cid, auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.AddJobDetails() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -281,29 +333,638 @@ func TestService_AddJobDetails(t *testing.T) {
 	}
 }
 
+func TestService_AddJobDetailsInvalidReferences(t *testing.T) {
+	claims := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}}
+	tests := []struct {
+		name       string
+		setupMocks func(mr *repository.MockUserRepo)
+		want       map[string][]uint
+	}{
+		{name: "unknown skill and shift",
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetCompany(uint64(1)).Return(models.Company{}, nil)
+				mr.EXPECT().MissingTaxonomyIDs(gomock.Any(), models.LocationKind, []uint{1}).Return(nil, nil)
+				mr.EXPECT().MissingTaxonomyIDs(gomock.Any(), models.SkillKind, []uint{2, 9, 9}).Return([]uint{9}, nil)
+				mr.EXPECT().MissingTaxonomyIDs(gomock.Any(), models.ShiftKind, []uint{40}).Return([]uint{40}, nil)
+			},
+			want: map[string][]uint{"SkillIDs": {9}, "ShiftIDs": {40}},
+		},
+		{name: "deleted company is reported with the other ids",
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetCompany(uint64(1)).Return(models.Company{}, gorm.ErrRecordNotFound)
+				mr.EXPECT().MissingTaxonomyIDs(gomock.Any(), models.LocationKind, []uint{1}).Return([]uint{1}, nil)
+				mr.EXPECT().MissingTaxonomyIDs(gomock.Any(), models.SkillKind, []uint{2, 9, 9}).Return(nil, nil)
+				mr.EXPECT().MissingTaxonomyIDs(gomock.Any(), models.ShiftKind, []uint{40}).Return(nil, nil)
+			},
+			want: map[string][]uint{"companyId": {1}, "LocationIDs": {1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockUserRepo.EXPECT().GetCompanyMember(uint64(1), uint(1)).Return(models.CompanyMember{Role: models.MemberRecruiter}, nil)
+			tt.setupMocks(MockUserRepo)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+
+			// PostJob is not expected, nothing may be written
+			_, err := s.AddJobDetails(context.Background(), models.NewJobRequest{
+				LocationIDs: []uint{1},
+				SkillIDs:    []uint{2, 9, 9},
+				ShiftIDs:    []uint{40},
+			}, 1, claims)
+			var invalid *InvalidReferencesError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("Service.AddJobDetails() error = %v, want InvalidReferencesError", err)
+			}
+			if !reflect.DeepEqual(invalid.Fields, tt.want) {
+				t.Errorf("Service.AddJobDetails() invalid = %v, want %v", invalid.Fields, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_AddJobDetailsStartsAsDraft(t *testing.T) {
+	claims := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}}
+	mc := gomock.NewController(t)
+	MockUserRepo := repository.NewMockUserRepo(mc)
+	MockUserRepo.EXPECT().GetCompanyMember(uint64(1), uint(1)).Return(models.CompanyMember{Role: models.MemberRecruiter}, nil).Times(2)
+	MockUserRepo.EXPECT().GetCompany(uint64(1)).Return(models.Company{}, nil).Times(2)
+	var saved models.Job
+	MockUserRepo.EXPECT().PostJob(gomock.Any()).DoAndReturn(func(j models.Job) (models.Response, error) {
+		saved = j
+		return models.Response{ID: 3}, nil
+	})
+	s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+
+	expiry := time.Now().Add(time.Hour)
+	_, err := s.AddJobDetails(context.Background(), models.NewJobRequest{JobTitle: "sde", ExpiresAt: &expiry}, 1, claims)
+	if err != nil {
+		t.Fatalf("Service.AddJobDetails() error = %v", err)
+	}
+	if saved.Status != models.JobDraft || saved.ExpiresAt != &expiry {
+		t.Errorf("Service.AddJobDetails() saved status %q expiry %v", saved.Status, saved.ExpiresAt)
+	}
+
+	// PostJob is not expected again
+	past := time.Now().Add(-time.Hour)
+	_, err = s.AddJobDetails(context.Background(), models.NewJobRequest{JobTitle: "sde", ExpiresAt: &past}, 1, claims)
+	if !errors.Is(err, ErrJobExpiryPassed) {
+		t.Errorf("Service.AddJobDetails() error = %v, want %v", err, ErrJobExpiryPassed)
+	}
+}
+
+func TestService_PatchJobInvalidReferences(t *testing.T) {
+	admin := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "9"}, Roles: []string{models.RoleAdmin}}
+	mc := gomock.NewController(t)
+	MockUserRepo := repository.NewMockUserRepo(mc)
+	MockUserRepo.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{Model: gorm.Model{ID: 5}, CompanyId: 2}, nil)
+	// Only the lists present in the patch are checked
+	MockUserRepo.EXPECT().MissingTaxonomyIDs(gomock.Any(), models.JobTypeKind, []uint{3}).Return([]uint{3}, nil)
+
+	s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+	_, err := s.PatchJob(context.Background(), 5, models.JobPatchRequest{JobTypeIDs: &[]uint{3}}, admin)
+	var invalid *InvalidReferencesError
+	if !errors.As(err, &invalid) || !reflect.DeepEqual(invalid.Fields, map[string][]uint{"JobTypeIDs": {3}}) {
+		t.Errorf("Service.PatchJob() error = %v", err)
+	}
+}
+
 func TestService_ProcessJobApplications(t *testing.T) {
-	type args struct {
-		applications []models.NewUserApplication
+	open := models.Job{
+		Model:               gorm.Model{ID: 5},
+		Status:              models.JobPublished,
+		MaximumNoticePeriod: 60,
+		MinExperience:       1,
+		MaxExperience:       5,
+		Locations:           []models.Location{{Model: gorm.Model{ID: 1}}},
+		Skills:              []models.Skill{{Model: gorm.Model{ID: 3}}},
+	}
+	cached, _ := json.Marshal(open)
+	good := models.NewUserApplication{Name: "ravi", ID: 5, Jobs: models.RequestFromUser{NoticePeriod: 30, Experience: 2, Location: []uint{1}, Skills: []uint{3}}}
+	weak := models.NewUserApplication{Name: "asha", ID: 5, Jobs: models.RequestFromUser{NoticePeriod: 90}}
+	tests := []struct {
+		name        string
+		application models.NewUserApplication
+		setupMocks  func(mr *repository.MockUserRepo, mc *caching.MockCache)
+		wantVerdict string
+		wantErr     string
+	}{
+		{name: "job does not exist",
+			application: good,
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().GetCache(gomock.Any(), uint(5)).Return("", redis.Nil)
+				mr.EXPECT().FetchJobData(gomock.Any(), uint64(5)).Return(models.Job{}, gorm.ErrRecordNotFound)
+			},
+			wantVerdict: models.VerdictError,
+			wantErr:     "job not found",
+		},
+		{name: "cache cannot be read",
+			application: good,
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().GetCache(gomock.Any(), uint(5)).Return("", errors.New("redis down"))
+			},
+			wantVerdict: models.VerdictError,
+			wantErr:     "cache error",
+		},
+		{name: "job is not open",
+			application: good,
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().GetCache(gomock.Any(), uint(5)).Return("", redis.Nil)
+				mr.EXPECT().FetchJobData(gomock.Any(), uint64(5)).Return(models.Job{Model: gorm.Model{ID: 5}, Status: models.JobPaused}, nil)
+				mc.EXPECT().AddCache(gomock.Any(), uint(5), gomock.Any()).Return(nil)
+			},
+			wantVerdict: models.VerdictError,
+			wantErr:     "job is not open for applications",
+		},
+		{name: "job loaded from the database is cached",
+			application: good,
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().GetCache(gomock.Any(), uint(5)).Return("", redis.Nil)
+				mr.EXPECT().FetchJobData(gomock.Any(), uint64(5)).Return(open, nil)
+				mc.EXPECT().AddCache(gomock.Any(), uint(5), open).Return(errors.New("redis down"))
+			},
+			wantVerdict: models.VerdictMatched,
+		},
+		{name: "cached job and a weak application",
+			application: weak,
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().GetCache(gomock.Any(), uint(5)).Return(string(cached), nil)
+			},
+			wantVerdict: models.VerdictNotMatched,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockCache := caching.NewMockCache(mc)
+			tt.setupMocks(MockUserRepo, MockCache)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+			got, err := s.ProcessJobApplications(context.Background(), []models.NewUserApplication{tt.application})
+			if err != nil || len(got) != 1 {
+				t.Fatalf("Service.ProcessJobApplications() = %v, %v", got, err)
+			}
+			if got[0].Verdict != tt.wantVerdict || got[0].Error != tt.wantErr {
+				t.Errorf("Service.ProcessJobApplications() = %+v, want verdict %q error %q", got[0], tt.wantVerdict, tt.wantErr)
+			}
+			if (got[0].Match == nil) != (tt.wantVerdict == models.VerdictError) {
+				t.Errorf("Service.ProcessJobApplications() match = %v", got[0].Match)
+			}
+		})
 	}
+}
+
+func TestService_ProcessJobApplications_order(t *testing.T) {
+	mc := gomock.NewController(t)
+	MockUserRepo := repository.NewMockUserRepo(mc)
+	MockCache := caching.NewMockCache(mc)
+	MockCache.EXPECT().GetCache(gomock.Any(), gomock.Any()).Return("", redis.Nil).AnyTimes()
+	MockUserRepo.EXPECT().FetchJobData(gomock.Any(), gomock.Any()).Return(models.Job{}, gorm.ErrRecordNotFound).AnyTimes()
+	s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+
+	applications := []models.NewUserApplication{{Name: "a", ID: 1}, {Name: "b", ID: 2}, {Name: "c", ID: 3}}
+	got, _ := s.ProcessJobApplications(context.Background(), applications)
+	if len(got) != len(applications) {
+		t.Fatalf("Service.ProcessJobApplications() returned %d results, want %d", len(got), len(applications))
+	}
+	for i, r := range got {
+		if r.Application.Name != applications[i].Name {
+			t.Errorf("result %d is for %q, want %q", i, r.Application.Name, applications[i].Name)
+		}
+	}
+}
+
+func TestService_PatchJob(t *testing.T) {
+	recruiter := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Roles: []string{models.RoleRecruiter}}
+	stored := models.Job{
+		Model:     gorm.Model{ID: 5},
+		CompanyId: 2,
+		JobTitle:  "sde",
+		SalaryMin: 10000,
+		SalaryMax: 20000,
+		Locations: []models.Location{{Model: gorm.Model{ID: 1}}},
+		Skills:    []models.Skill{{Model: gorm.Model{ID: 3}}},
+	}
+	title := "senior sde"
+	noSkills := []uint{}
+	tests := []struct {
+		name       string
+		claims     auth.Claims
+		setupMocks func(mr *repository.MockUserRepo, mc *caching.MockCache)
+		want       models.Job
+		wantErr    error
+	}{
+		{name: "job does not exist",
+			claims: recruiter,
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrJobNotFound,
+		},
+		{name: "recruiter of another company",
+			claims: recruiter,
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(stored, nil)
+				mr.EXPECT().GetCompanyMember(uint64(2), uint(1)).Return(models.CompanyMember{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrNotCompanyMember,
+		},
+		{name: "only the given fields change and the cache is cleared",
+			claims: recruiter,
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(stored, nil)
+				mr.EXPECT().GetCompanyMember(uint64(2), uint(1)).Return(models.CompanyMember{Role: models.MemberRecruiter}, nil)
+				mr.EXPECT().UpdateJob(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, j models.Job) (models.Job, error) {
+					return j, nil
+				})
+				mc.EXPECT().DeleteCache(gomock.Any(), uint(5)).Return(nil)
+				mc.EXPECT().InvalidateRecommendations(gomock.Any()).Return(nil)
+			},
+			want: models.Job{
+				Model:     gorm.Model{ID: 5},
+				CompanyId: 2,
+				JobTitle:  "senior sde",
+				SalaryMin: 10000,
+				SalaryMax: 20000,
+				Locations: []models.Location{{Model: gorm.Model{ID: 1}}},
+				Skills:    []models.Skill{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockCache := caching.NewMockCache(mc)
+			tt.setupMocks(MockUserRepo, MockCache)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+			got, err := s.PatchJob(context.Background(), 5, models.JobPatchRequest{JobTitle: &title, SkillIDs: &noSkills}, tt.claims)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Service.PatchJob() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Service.PatchJob() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_PatchJobSalary(t *testing.T) {
+	admin := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "9"}, Roles: []string{models.RoleAdmin}}
+	stored := models.Job{Model: gorm.Model{ID: 5}, CompanyId: 2, SalaryMin: 10000, SalaryMax: 20000, SalaryCurrency: "INR", SalaryPeriod: models.PayMonthly}
+	low, high := 5000.0, 30000.0
 	tests := []struct {
 		name    string
-		s       *Service
-		args    args
-		want    []models.NewUserApplication
-		wantErr bool
+		patch   models.JobPatchRequest
+		want    models.Job
+		wantErr error
 	}{
-		// TODO: Add test cases.
+		{name: "max below the stored min",
+			patch:   models.JobPatchRequest{SalaryMax: &low},
+			wantErr: ErrInvalidSalaryRange,
+		},
+		{name: "one end of the range changed",
+			patch: models.JobPatchRequest{SalaryMax: &high},
+			want:  models.Job{Model: gorm.Model{ID: 5}, CompanyId: 2, SalaryMin: 10000, SalaryMax: 30000, SalaryCurrency: "INR", SalaryPeriod: models.PayMonthly},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := tt.s.ProcessJobApplications(tt.args.applications)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("Service.ProcessJobApplications() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockCache := caching.NewMockCache(mc)
+			MockUserRepo.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(stored, nil)
+			if tt.wantErr == nil {
+				MockUserRepo.EXPECT().UpdateJob(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, j models.Job) (models.Job, error) {
+					return j, nil
+				})
+				MockCache.EXPECT().DeleteCache(gomock.Any(), uint(5)).Return(nil)
+				MockCache.EXPECT().InvalidateRecommendations(gomock.Any()).Return(nil)
+			}
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+			got, err := s.PatchJob(context.Background(), 5, tt.patch, admin)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Service.PatchJob() error = %v, wantErr %v", err, tt.wantErr)
 			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("Service.ProcessJobApplications() = %v, want %v", got, tt.want)
+				t.Errorf("Service.PatchJob() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_ViewAllJobsHidesSalariesAndMatchRules(t *testing.T) {
+	rules := &models.MatchRules{RequiredSkillIDs: []uint{3}, Mandatory: []string{models.CriterionExperience}}
+	mc := gomock.NewController(t)
+	MockUserRepo := repository.NewMockUserRepo(mc)
+	MockUserRepo.EXPECT().ListJobs(gomock.Any(), gomock.Any()).Return([]models.Job{
+		{JobTitle: "sde", SalaryMin: 10, SalaryMax: 20, SalaryCurrency: "USD", SalaryPeriod: models.PayHourly, SalaryHidden: true},
+		{JobTitle: "qa", SalaryMin: 10, SalaryMax: 20, SalaryCurrency: "USD", SalaryPeriod: models.PayHourly, MatchRules: rules, Comp: models.Company{MatchRules: rules}},
+	}, int64(2), nil)
+	MockUserRepo.EXPECT().CountJobFacets(gomock.Any(), gomock.Any()).Return(models.JobFacets{}, nil)
+
+	s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+	got, err := s.ViewAllJobs(context.Background(), models.JobListQuery{}, auth.Claims{})
+	if err != nil {
+		t.Fatalf("Service.ViewAllJobs() error = %v", err)
+	}
+	want := []models.Job{
+		{JobTitle: "sde", SalaryCurrency: "USD", SalaryPeriod: models.PayHourly, SalaryHidden: true},
+		{JobTitle: "qa", SalaryMin: 10, SalaryMax: 20, SalaryCurrency: "USD", SalaryPeriod: models.PayHourly},
+	}
+	if !reflect.DeepEqual(got.Jobs, want) {
+		t.Errorf("Service.ViewAllJobs() jobs = %v, want %v", got.Jobs, want)
+	}
+}
+
+func TestService_UpdateJob(t *testing.T) {
+	admin := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "9"}, Roles: []string{models.RoleAdmin}}
+	mc := gomock.NewController(t)
+	MockUserRepo := repository.NewMockUserRepo(mc)
+	MockCache := caching.NewMockCache(mc)
+	MockUserRepo.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{
+		Model:     gorm.Model{ID: 5},
+		CompanyId: 2,
+		Locations: []models.Location{{Model: gorm.Model{ID: 1}}},
+		Shifts:    []models.Shift{{Model: gorm.Model{ID: 4}}},
+	}, nil)
+	MockUserRepo.EXPECT().MissingTaxonomyIDs(gomock.Any(), models.LocationKind, []uint{7}).Return(nil, nil)
+	var saved models.Job
+	MockUserRepo.EXPECT().UpdateJob(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, j models.Job) (models.Job, error) {
+		saved = j
+		return j, nil
+	})
+	MockCache.EXPECT().DeleteCache(gomock.Any(), uint(5)).Return(nil)
+	MockCache.EXPECT().InvalidateRecommendations(gomock.Any()).Return(nil)
+
+	s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+	_, err := s.UpdateJob(context.Background(), 5, models.NewJobRequest{JobTitle: "qa", MaxExperience: 4, LocationIDs: []uint{7}}, admin)
+	if err != nil {
+		t.Fatalf("Service.UpdateJob() error = %v", err)
+	}
+	if saved.JobTitle != "qa" || saved.MaxExperience != 4 || saved.CompanyId != 2 {
+		t.Errorf("Service.UpdateJob() saved %+v", saved)
+	}
+	// Associations missing from the request are cleared
+	if len(saved.Locations) != 1 || saved.Locations[0].ID != 7 || len(saved.Shifts) != 0 {
+		t.Errorf("Service.UpdateJob() associations = %v %v", saved.Locations, saved.Shifts)
+	}
+}
+
+func TestService_DeleteJob(t *testing.T) {
+	admin := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "9"}, Roles: []string{models.RoleAdmin}}
+	tests := []struct {
+		name       string
+		setupMocks func(mr *repository.MockUserRepo, mc *caching.MockCache)
+		wantErr    error
+	}{
+		{name: "job does not exist",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrJobNotFound,
+		},
+		{name: "job deleted and cache cleared",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{Model: gorm.Model{ID: 5}, CompanyId: 2}, nil)
+				mr.EXPECT().DeleteJob(gomock.Any(), uint64(5)).Return(nil)
+				mc.EXPECT().DeleteCache(gomock.Any(), uint(5)).Return(nil)
+				mc.EXPECT().InvalidateRecommendations(gomock.Any()).Return(nil)
+			},
+		},
+		{name: "cache failure does not fail the delete",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{Model: gorm.Model{ID: 5}, CompanyId: 2}, nil)
+				mr.EXPECT().DeleteJob(gomock.Any(), uint64(5)).Return(nil)
+				mc.EXPECT().DeleteCache(gomock.Any(), uint(5)).Return(errors.New("redis down"))
+				mc.EXPECT().InvalidateRecommendations(gomock.Any()).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockCache := caching.NewMockCache(mc)
+			tt.setupMocks(MockUserRepo, MockCache)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+			err := s.DeleteJob(context.Background(), 5, admin)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Service.DeleteJob() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestService_ViewAllJobsStatuses(t *testing.T) {
+	recruiter := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Roles: []string{models.RoleRecruiter}}
+	cid := uint64(2)
+	tests := []struct {
+		name       string
+		query      models.JobListQuery
+		claims     auth.Claims
+		setupMocks func(mr *repository.MockUserRepo)
+		wantErr    error
+	}{
+		{name: "drafts need a company",
+			query:      models.JobListQuery{Statuses: []string{models.JobDraft}},
+			claims:     recruiter,
+			setupMocks: func(mr *repository.MockUserRepo) {},
+			wantErr:    ErrNotCompanyMember,
+		},
+		{name: "drafts of another company",
+			query:  models.JobListQuery{Statuses: []string{models.JobDraft}, CompanyId: &cid},
+			claims: recruiter,
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetCompanyMember(uint64(2), uint(1)).Return(models.CompanyMember{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrNotCompanyMember,
+		},
+		{name: "drafts of own company",
+			query:  models.JobListQuery{Statuses: []string{models.JobDraft, models.JobPaused}, CompanyId: &cid},
+			claims: recruiter,
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetCompanyMember(uint64(2), uint(1)).Return(models.CompanyMember{Role: models.MemberRecruiter}, nil)
+				mr.EXPECT().ListJobs(gomock.Any(), gomock.Any()).Return(nil, int64(0), nil)
+				mr.EXPECT().CountJobFacets(gomock.Any(), gomock.Any()).Return(models.JobFacets{}, nil)
+			},
+		},
+		{name: "admins see every status",
+			query:  models.JobListQuery{Statuses: []string{models.JobClosed}},
+			claims: auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "9"}, Roles: []string{models.RoleAdmin}},
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().ListJobs(gomock.Any(), gomock.Any()).Return(nil, int64(0), nil)
+				mr.EXPECT().CountJobFacets(gomock.Any(), gomock.Any()).Return(models.JobFacets{}, nil)
+			},
+		},
+		{name: "anyone may ask for published jobs",
+			query:  models.JobListQuery{Statuses: []string{models.JobPublished}},
+			claims: auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "3"}, Roles: []string{models.RoleCandidate}},
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().ListJobs(gomock.Any(), gomock.Any()).Return(nil, int64(0), nil)
+				mr.EXPECT().CountJobFacets(gomock.Any(), gomock.Any()).Return(models.JobFacets{}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			tt.setupMocks(MockUserRepo)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+			_, err := s.ViewAllJobs(context.Background(), tt.query, tt.claims)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Service.ViewAllJobs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_JobStatusChanges(t *testing.T) {
+	admin := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "9"}, Roles: []string{models.RoleAdmin}}
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(24 * time.Hour)
+	tests := []struct {
+		name       string
+		change     func(s UserService) (models.Job, error)
+		setupMocks func(mr *repository.MockUserRepo, mc *caching.MockCache)
+		wantStatus string
+		wantErr    error
+	}{
+		{name: "draft published",
+			change: func(s UserService) (models.Job, error) { return s.PublishJob(context.Background(), 5, &future, admin) },
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{Model: gorm.Model{ID: 5}, CompanyId: 2, Status: models.JobDraft}, nil)
+				mr.EXPECT().SetJobStatus(gomock.Any(), uint64(5), models.JobDraft, models.JobPublished, &future).Return(nil)
+				mc.EXPECT().DeleteCache(gomock.Any(), uint(5)).Return(nil)
+				mc.EXPECT().InvalidateRecommendations(gomock.Any()).Return(nil)
+			},
+			wantStatus: models.JobPublished,
+		},
+		{name: "publishing with an expiry date in the past",
+			change: func(s UserService) (models.Job, error) { return s.PublishJob(context.Background(), 5, &past, admin) },
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{Model: gorm.Model{ID: 5}, CompanyId: 2, Status: models.JobDraft}, nil)
+			},
+			wantErr: ErrJobExpiryPassed,
+		},
+		{name: "expired job needs a new expiry date",
+			change: func(s UserService) (models.Job, error) { return s.PublishJob(context.Background(), 5, nil, admin) },
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{Model: gorm.Model{ID: 5}, CompanyId: 2, Status: models.JobExpired, ExpiresAt: &past}, nil)
+			},
+			wantErr: ErrJobExpiryPassed,
+		},
+		{name: "closed job cannot be published",
+			change: func(s UserService) (models.Job, error) { return s.PublishJob(context.Background(), 5, nil, admin) },
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{Model: gorm.Model{ID: 5}, CompanyId: 2, Status: models.JobClosed}, nil)
+			},
+			wantErr: ErrJobTransition,
+		},
+		{name: "draft cannot be paused",
+			change: func(s UserService) (models.Job, error) { return s.PauseJob(context.Background(), 5, admin) },
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{Model: gorm.Model{ID: 5}, CompanyId: 2, Status: models.JobDraft}, nil)
+			},
+			wantErr: ErrJobTransition,
+		},
+		{name: "status changed by another request",
+			change: func(s UserService) (models.Job, error) { return s.PauseJob(context.Background(), 5, admin) },
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{Model: gorm.Model{ID: 5}, CompanyId: 2, Status: models.JobPublished}, nil)
+				mr.EXPECT().SetJobStatus(gomock.Any(), uint64(5), models.JobPublished, models.JobPaused, nil).Return(gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrJobTransition,
+		},
+		{name: "paused job closed",
+			change: func(s UserService) (models.Job, error) { return s.CloseJob(context.Background(), 5, admin) },
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetJob(gomock.Any(), uint64(5)).Return(models.Job{Model: gorm.Model{ID: 5}, CompanyId: 2, Status: models.JobPaused}, nil)
+				mr.EXPECT().SetJobStatus(gomock.Any(), uint64(5), models.JobPaused, models.JobClosed, nil).Return(nil)
+				mc.EXPECT().DeleteCache(gomock.Any(), uint(5)).Return(nil)
+				mc.EXPECT().InvalidateRecommendations(gomock.Any()).Return(nil)
+			},
+			wantStatus: models.JobClosed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockCache := caching.NewMockCache(mc)
+			tt.setupMocks(MockUserRepo, MockCache)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+			got, err := tt.change(s)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("status change error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("status change status = %q, want %q", got.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestService_ExpireJobs(t *testing.T) {
+	mc := gomock.NewController(t)
+	MockUserRepo := repository.NewMockUserRepo(mc)
+	MockCache := caching.NewMockCache(mc)
+	MockUserRepo.EXPECT().ExpireJobs(gomock.Any(), gomock.Any()).Return([]uint{4, 7}, nil)
+	MockCache.EXPECT().DeleteCache(gomock.Any(), uint(4)).Return(nil)
+	MockCache.EXPECT().DeleteCache(gomock.Any(), uint(7)).Return(nil)
+	MockCache.EXPECT().InvalidateRecommendations(gomock.Any()).Return(nil)
+
+	s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+	err := s.ExpireJobs(context.Background())
+	if err != nil {
+		t.Errorf("Service.ExpireJobs() error = %v", err)
+	}
+
+	// Nothing expired, the recommendations stay cached
+	MockUserRepo.EXPECT().ExpireJobs(gomock.Any(), gomock.Any()).Return([]uint{}, nil)
+	err = s.ExpireJobs(context.Background())
+	if err != nil {
+		t.Errorf("Service.ExpireJobs() error = %v", err)
+	}
+}
+
+func TestService_StreamJobApplications(t *testing.T) {
+	mc := gomock.NewController(t)
+	MockUserRepo := repository.NewMockUserRepo(mc)
+	MockCache := caching.NewMockCache(mc)
+	MockCache.EXPECT().GetCache(gomock.Any(), gomock.Any()).Return("", redis.Nil).AnyTimes()
+	MockUserRepo.EXPECT().FetchJobData(gomock.Any(), gomock.Any()).Return(models.Job{}, gorm.ErrRecordNotFound).AnyTimes()
+	s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+
+	in := make(chan models.StreamedApplication)
+	out := s.StreamJobApplications(context.Background(), in)
+	go func() {
+		defer close(in)
+		for line := 1; line <= 50; line++ {
+			in <- models.StreamedApplication{Line: line, Application: models.NewUserApplication{ID: uint64(line)}}
+		}
+	}()
+	seen := map[int]bool{}
+	for r := range out {
+		if r.Verdict != models.VerdictError || r.Error != ErrJobNotFound.Error() || r.Application.ID != uint64(r.Line) {
+			t.Errorf("Service.StreamJobApplications() = %+v", r)
+		}
+		seen[r.Line] = true
+	}
+	if len(seen) != 50 {
+		t.Errorf("Service.StreamJobApplications() returned %d results, want 50", len(seen))
+	}
+}
+
+func TestService_StreamJobApplications_cancelled(t *testing.T) {
+	mc := gomock.NewController(t)
+	s, _ := NewService(repository.NewMockUserRepo(mc), &auth.Auth{}, caching.NewMockCache(mc), nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// in is never closed, the results still end once ctx is done
+	for r := range s.StreamJobApplications(ctx, make(chan models.StreamedApplication)) {
+		t.Errorf("Service.StreamJobApplications() = %+v after cancel", r)
+	}
+}
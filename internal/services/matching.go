@@ -0,0 +1,172 @@
+package services
+
+import (
+	"job-portal-api/internal/models"
+	"math"
+)
+
+// compareData scores an application against a job with the match rules of
+// the job. Each criterion scores from 0 to 1, skills by the share of the
+// job's skills the candidate has and the others by whether they are met.
+func (s *Service) compareData(application models.NewUserApplication, jobData models.Job) models.MatchResult {
+	rules := matchRules(jobData)
+	mandatory := make(map[string]bool, len(rules.Mandatory))
+	for _, c := range rules.Mandatory {
+		mandatory[c] = true
+	}
+
+	result := models.MatchResult{
+		Threshold:               rules.MinScore(),
+		Criteria:                make([]models.CriterionResult, 0, len(models.MatchCriteria)),
+		MissingSkillIDs:         missing(application.Jobs.Skills, rules.RequiredSkillIDs),
+		MissingQualificationIDs: missing(application.Jobs.Qualifications, rules.RequiredQualificationIDs),
+	}
+	matched := len(result.MissingSkillIDs) == 0 && len(result.MissingQualificationIDs) == 0
+	var total, weighted float64
+	for _, c := range models.MatchCriteria {
+		score := criterionScore(c, application.Jobs, jobData)
+		cr := models.CriterionResult{
+			Criterion: c,
+			Weight:    rules.Weight(c),
+			Score:     math.Round(score*100) / 100,
+			Matched:   score > 0,
+			Mandatory: mandatory[c],
+		}
+		if cr.Mandatory && !cr.Matched {
+			matched = false
+		}
+		total += cr.Weight
+		weighted += cr.Weight * score
+		result.Criteria = append(result.Criteria, cr)
+	}
+
+	if total > 0 {
+		result.Score = math.Round(weighted/total*10000) / 100
+	}
+	result.Matched = matched && result.Score >= result.Threshold
+	return result
+}
+
+// matchRules returns the rules of the job, else those of its company, else
+// the defaults
+func matchRules(j models.Job) models.MatchRules {
+	if j.MatchRules != nil {
+		return *j.MatchRules
+	}
+	if j.Comp.MatchRules != nil {
+		return *j.Comp.MatchRules
+	}
+	return models.MatchRules{}
+}
+
+// criterionScore scores one criterion from 0 to 1
+func criterionScore(criterion string, a models.RequestFromUser, j models.Job) float64 {
+	switch criterion {
+	case models.CriterionNoticePeriod:
+		return met(a.NoticePeriod >= j.MinimumNoticePeriod && a.NoticePeriod <= int(j.MaximumNoticePeriod))
+	case models.CriterionExperience:
+		return met(a.Experience >= j.MinExperience && a.Experience <= j.MaxExperience)
+	case models.CriterionLocation:
+		return met(overlap(a.Location, locationIDs(j.Locations)) > 0)
+	case models.CriterionSkills:
+		jobSkills := skillIDs(j.Skills)
+		if len(jobSkills) == 0 {
+			return 0
+		}
+		return float64(overlap(a.Skills, jobSkills)) / float64(len(jobSkills))
+	case models.CriterionQualifications:
+		return met(overlap(a.Qualifications, qualificationIDs(j.Qualifications)) > 0)
+	case models.CriterionShift:
+		return met(overlap(a.Shift, shiftIDs(j.Shifts)) > 0)
+	case models.CriterionJobType:
+		return met(overlap(a.JobType, jobTypeIDs(j.JobTypes)) > 0)
+	}
+	return 0
+}
+
+func met(ok bool) float64 {
+	if ok {
+		return 1
+	}
+	return 0
+}
+
+// overlap counts the distinct ids of want that are also in have
+func overlap(have, want []uint) int {
+	set := make(map[uint]bool, len(have))
+	for _, id := range have {
+		set[id] = true
+	}
+	n := 0
+	seen := make(map[uint]bool, len(want))
+	for _, id := range want {
+		if set[id] && !seen[id] {
+			seen[id] = true
+			n++
+		}
+	}
+	return n
+}
+
+// missing returns the ids of want that are not in have
+func missing(have, want []uint) []uint {
+	set := make(map[uint]bool, len(have))
+	for _, id := range have {
+		set[id] = true
+	}
+	var ids []uint
+	for _, id := range want {
+		if !set[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func locationIDs(l []models.Location) []uint {
+	ids := make([]uint, 0, len(l))
+	for _, v := range l {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func skillIDs(l []models.Skill) []uint {
+	ids := make([]uint, 0, len(l))
+	for _, v := range l {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func qualificationIDs(l []models.Qualification) []uint {
+	ids := make([]uint, 0, len(l))
+	for _, v := range l {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func shiftIDs(l []models.Shift) []uint {
+	ids := make([]uint, 0, len(l))
+	for _, v := range l {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func jobTypeIDs(l []models.JobType) []uint {
+	ids := make([]uint, 0, len(l))
+	for _, v := range l {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func workModeIDs(l []models.WorkMode) []uint {
+	ids := make([]uint, 0, len(l))
+	for _, v := range l {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"job-portal-api/internal/models"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestService_compareData(t *testing.T) {
+	job := models.Job{
+		MinimumNoticePeriod: 0,
+		MaximumNoticePeriod: 60,
+		MinExperience:       1,
+		MaxExperience:       5,
+		Locations:           []models.Location{{Model: gorm.Model{ID: 1}}},
+		Skills:              []models.Skill{{Model: gorm.Model{ID: 3}}, {Model: gorm.Model{ID: 4}}, {Model: gorm.Model{ID: 5}}, {Model: gorm.Model{ID: 6}}},
+		Qualifications:      []models.Qualification{{Model: gorm.Model{ID: 7}}},
+		Shifts:              []models.Shift{{Model: gorm.Model{ID: 8}}},
+		JobTypes:            []models.JobType{{Model: gorm.Model{ID: 9}}},
+	}
+	// Meets notice period, experience and location and has half the skills
+	candidate := models.RequestFromUser{NoticePeriod: 30, Experience: 2, Location: []uint{1}, Skills: []uint{3, 4, 4}}
+	threshold := func(v float64) *float64 { return &v }
+	withRules := func(r *models.MatchRules) models.Job {
+		j := job
+		j.MatchRules = r
+		return j
+	}
+	tests := []struct {
+		name string
+		app  models.RequestFromUser
+		job  models.Job
+		want models.MatchResult
+	}{
+		{name: "default rules weigh every criterion the same",
+			app:  candidate,
+			job:  job,
+			want: models.MatchResult{Score: 50, Matched: true},
+		},
+		{name: "skills score by the share of the job's skills",
+			app:  models.RequestFromUser{Skills: []uint{3}},
+			job:  withRules(&models.MatchRules{Weights: map[string]float64{models.CriterionSkills: 1, models.CriterionNoticePeriod: 0, models.CriterionExperience: 0, models.CriterionLocation: 0, models.CriterionQualifications: 0, models.CriterionShift: 0, models.CriterionJobType: 0}}),
+			want: models.MatchResult{Score: 25, Matched: false},
+		},
+		{name: "weights shift the score",
+			app:  candidate,
+			job:  withRules(&models.MatchRules{Weights: map[string]float64{models.CriterionSkills: 4}}),
+			want: models.MatchResult{Score: 50, Matched: true},
+		},
+		{name: "threshold of the job",
+			app:  candidate,
+			job:  withRules(&models.MatchRules{Threshold: threshold(60)}),
+			want: models.MatchResult{Score: 50, Matched: false},
+		},
+		{name: "mandatory criterion not met",
+			app:  candidate,
+			job:  withRules(&models.MatchRules{Mandatory: []string{models.CriterionQualifications}}),
+			want: models.MatchResult{Score: 50, Matched: false},
+		},
+		{name: "required qualification missing",
+			app:  models.RequestFromUser{NoticePeriod: 30, Experience: 2, Location: []uint{1}, Skills: []uint{3, 4}, Qualifications: []uint{7}},
+			job:  withRules(&models.MatchRules{RequiredQualificationIDs: []uint{7, 10}}),
+			want: models.MatchResult{Score: 64.29, Matched: false},
+		},
+		{name: "rules of the company when the job has none",
+			app: candidate,
+			job: func() models.Job {
+				j := job
+				j.Comp.MatchRules = &models.MatchRules{Threshold: threshold(80)}
+				return j
+			}(),
+			want: models.MatchResult{Score: 50, Matched: false},
+		},
+		{name: "rules of the job win over the company",
+			app: candidate,
+			job: func() models.Job {
+				j := withRules(&models.MatchRules{Threshold: threshold(40)})
+				j.Comp.MatchRules = &models.MatchRules{Threshold: threshold(80)}
+				return j
+			}(),
+			want: models.MatchResult{Score: 50, Matched: true},
+		},
+	}
+	s := &Service{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := s.compareData(models.NewUserApplication{Jobs: tt.app}, tt.job)
+			if got.Score != tt.want.Score || got.Matched != tt.want.Matched {
+				t.Errorf("Service.compareData() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_compareData_explained(t *testing.T) {
+	job := models.Job{
+		MaximumNoticePeriod: 60,
+		MaxExperience:       5,
+		Skills:              []models.Skill{{Model: gorm.Model{ID: 3}}, {Model: gorm.Model{ID: 4}}, {Model: gorm.Model{ID: 5}}},
+		Qualifications:      []models.Qualification{{Model: gorm.Model{ID: 7}}},
+		MatchRules: &models.MatchRules{
+			Weights:                  map[string]float64{models.CriterionSkills: 2},
+			Mandatory:                []string{models.CriterionQualifications},
+			RequiredQualificationIDs: []uint{7},
+		},
+	}
+	got := (&Service{}).compareData(models.NewUserApplication{Jobs: models.RequestFromUser{NoticePeriod: 30, Experience: 2, Skills: []uint{3}}}, job)
+	want := models.MatchResult{
+		Score:     33.33,
+		Threshold: models.DefaultMatchThreshold,
+		Criteria: []models.CriterionResult{
+			{Criterion: models.CriterionNoticePeriod, Weight: 1, Score: 1, Matched: true},
+			{Criterion: models.CriterionExperience, Weight: 1, Score: 1, Matched: true},
+			{Criterion: models.CriterionLocation, Weight: 1},
+			{Criterion: models.CriterionSkills, Weight: 2, Score: 0.33, Matched: true},
+			{Criterion: models.CriterionQualifications, Weight: 1, Mandatory: true},
+			{Criterion: models.CriterionShift, Weight: 1},
+			{Criterion: models.CriterionJobType, Weight: 1},
+		},
+		MissingQualificationIDs: []uint{7},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Service.compareData() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Errors returned for candidate profiles
+var (
+	ErrProfileNotFound = errors.New("profile not found")
+	ErrProfileExists   = errors.New("you already have a profile, change it instead")
+)
+
+// profileReferences returns the id lists of a profile request, keyed by their
+// names in the request
+func profileReferences(pr models.ProfileRequest) []jobReference {
+	return []jobReference{
+		{field: "location", kind: models.LocationKind, ids: pr.LocationIDs},
+		{field: "technologyStack", kind: models.SkillKind, ids: pr.SkillIDs},
+		{field: "work_modes", kind: models.WorkModeKind, ids: pr.WorkModeIDs},
+		{field: "qualifications", kind: models.QualificationKind, ids: pr.QualificationIDs},
+		{field: "shifts", kind: models.ShiftKind, ids: pr.ShiftIDs},
+		{field: "job_type", kind: models.JobTypeKind, ids: pr.JobTypeIDs},
+	}
+}
+
+// checkProfileReferences returns an InvalidReferencesError for the ids of a
+// profile request that do not exist
+func (s *Service) checkProfileReferences(ctx context.Context, pr models.ProfileRequest) error {
+	err := s.checkJobReferences(ctx, map[string][]uint{}, profileReferences(pr))
+	var invalid *InvalidReferencesError
+	if errors.As(err, &invalid) {
+		invalid.Subject = "profile"
+	}
+	return err
+}
+
+// applyProfileRequest copies every field of a profile request onto the profile
+func applyProfileRequest(p *models.CandidateProfile, pr models.ProfileRequest) {
+	p.Headline = pr.Headline
+	p.NoticePeriod = pr.NoticePeriod
+	p.Experience = pr.Experience
+	p.Locations = locationsFromIDs(pr.LocationIDs)
+	p.Skills = skillsFromIDs(pr.SkillIDs)
+	p.WorkModes = workModesFromIDs(pr.WorkModeIDs)
+	p.Qualifications = qualificationsFromIDs(pr.QualificationIDs)
+	p.Shifts = shiftsFromIDs(pr.ShiftIDs)
+	p.JobTypes = jobTypesFromIDs(pr.JobTypeIDs)
+}
+
+// CreateProfile stores the profile of the logged in candidate, a candidate has
+// only one
+func (s *Service) CreateProfile(ctx context.Context, pr models.ProfileRequest, claims auth.Claims) (models.CandidateProfile, error) {
+	uid, err := claims.UserId()
+	if err != nil {
+		return models.CandidateProfile{}, err
+	}
+	err = s.checkProfileReferences(ctx, pr)
+	if err != nil {
+		return models.CandidateProfile{}, err
+	}
+	p := models.CandidateProfile{UserId: uid}
+	applyProfileRequest(&p, pr)
+	p, err = s.UserRepo.CreateProfile(ctx, p)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return models.CandidateProfile{}, ErrProfileExists
+	}
+	if err != nil {
+		return models.CandidateProfile{}, err
+	}
+	s.forgetRecommendations(ctx, uid)
+	return p, nil
+}
+
+// ViewProfile returns the profile of the logged in candidate
+func (s *Service) ViewProfile(ctx context.Context, claims auth.Claims) (models.CandidateProfile, error) {
+	uid, err := claims.UserId()
+	if err != nil {
+		return models.CandidateProfile{}, err
+	}
+	return s.getProfile(ctx, uid)
+}
+
+// UpdateProfile replaces the profile of the logged in candidate
+func (s *Service) UpdateProfile(ctx context.Context, pr models.ProfileRequest, claims auth.Claims) (models.CandidateProfile, error) {
+	uid, err := claims.UserId()
+	if err != nil {
+		return models.CandidateProfile{}, err
+	}
+	p, err := s.getProfile(ctx, uid)
+	if err != nil {
+		return models.CandidateProfile{}, err
+	}
+	err = s.checkProfileReferences(ctx, pr)
+	if err != nil {
+		return models.CandidateProfile{}, err
+	}
+	applyProfileRequest(&p, pr)
+	p, err = s.UserRepo.UpdateProfile(ctx, p)
+	if err != nil {
+		return models.CandidateProfile{}, err
+	}
+	s.forgetRecommendations(ctx, uid)
+	return p, nil
+}
+
+// DeleteProfile deletes the profile of the logged in candidate, applications
+// made from it keep their details
+func (s *Service) DeleteProfile(ctx context.Context, claims auth.Claims) error {
+	uid, err := claims.UserId()
+	if err != nil {
+		return err
+	}
+	err = s.UserRepo.DeleteProfile(ctx, uid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrProfileNotFound
+	}
+	if err != nil {
+		return err
+	}
+	s.forgetRecommendations(ctx, uid)
+	return nil
+}
+
+func (s *Service) getProfile(ctx context.Context, uid uint) (models.CandidateProfile, error) {
+	p, err := s.UserRepo.GetProfile(ctx, uid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.CandidateProfile{}, ErrProfileNotFound
+	}
+	if err != nil {
+		return models.CandidateProfile{}, err
+	}
+	return p, nil
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/caching"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"reflect"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestService_CreateProfile(t *testing.T) {
+	candidate := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "4"}, Roles: []string{models.RoleCandidate}}
+	pr := models.ProfileRequest{NoticePeriod: 30, Experience: 2, LocationIDs: []uint{1}, SkillIDs: []uint{3, 9}}
+	tests := []struct {
+		name        string
+		setupMocks  func(mr *repository.MockUserRepo, mc *caching.MockCache)
+		wantInvalid map[string][]uint
+		wantErr     error
+	}{
+		{name: "unknown skill",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().MissingTaxonomyIDs(gomock.Any(), models.LocationKind, []uint{1}).Return(nil, nil)
+				mr.EXPECT().MissingTaxonomyIDs(gomock.Any(), models.SkillKind, []uint{3, 9}).Return([]uint{9}, nil)
+			},
+			wantInvalid: map[string][]uint{"technologyStack": {9}},
+		},
+		{name: "second profile",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().MissingTaxonomyIDs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+				mr.EXPECT().CreateProfile(gomock.Any(), gomock.Any()).Return(models.CandidateProfile{}, gorm.ErrDuplicatedKey)
+			},
+			wantErr: ErrProfileExists,
+		},
+		{name: "profile stored",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().MissingTaxonomyIDs(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil).Times(2)
+				mr.EXPECT().CreateProfile(gomock.Any(), models.CandidateProfile{
+					UserId:         4,
+					NoticePeriod:   30,
+					Experience:     2,
+					Locations:      []models.Location{{Model: gorm.Model{ID: 1}}},
+					Skills:         []models.Skill{{Model: gorm.Model{ID: 3}}, {Model: gorm.Model{ID: 9}}},
+					WorkModes:      []models.WorkMode{},
+					Qualifications: []models.Qualification{},
+					Shifts:         []models.Shift{},
+					JobTypes:       []models.JobType{},
+				}).DoAndReturn(func(ctx context.Context, p models.CandidateProfile) (models.CandidateProfile, error) {
+					p.ID = 2
+					return p, nil
+				})
+				mc.EXPECT().DeleteRecommendations(gomock.Any(), uint(4)).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockCache := caching.NewMockCache(mc)
+			tt.setupMocks(MockUserRepo, MockCache)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+			_, err := s.CreateProfile(context.Background(), pr, candidate)
+			if tt.wantInvalid != nil {
+				var invalid *InvalidReferencesError
+				if !errors.As(err, &invalid) || !reflect.DeepEqual(invalid.Fields, tt.wantInvalid) {
+					t.Fatalf("Service.CreateProfile() error = %v, want invalid %v", err, tt.wantInvalid)
+				}
+				if invalid.Error() != "profile refers to items that do not exist" {
+					t.Errorf("Service.CreateProfile() error = %q", invalid.Error())
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Service.CreateProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_UpdateProfile(t *testing.T) {
+	candidate := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "4"}, Roles: []string{models.RoleCandidate}}
+	stored := models.CandidateProfile{Model: gorm.Model{ID: 2}, UserId: 4, NoticePeriod: 30, Skills: []models.Skill{{Model: gorm.Model{ID: 3}}}}
+	tests := []struct {
+		name       string
+		setupMocks func(mr *repository.MockUserRepo, mc *caching.MockCache)
+		wantErr    error
+	}{
+		{name: "no profile yet",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetProfile(gomock.Any(), uint(4)).Return(models.CandidateProfile{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrProfileNotFound,
+		},
+		{name: "profile replaced even when the cache fails",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().GetProfile(gomock.Any(), uint(4)).Return(stored, nil)
+				mr.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, p models.CandidateProfile) (models.CandidateProfile, error) {
+					if p.ID != 2 || p.NoticePeriod != 0 || len(p.Skills) != 0 {
+						t.Errorf("profile not replaced: %+v", p)
+					}
+					return p, nil
+				})
+				mc.EXPECT().DeleteRecommendations(gomock.Any(), uint(4)).Return(errors.New("redis down"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockCache := caching.NewMockCache(mc)
+			tt.setupMocks(MockUserRepo, MockCache)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+			_, err := s.UpdateProfile(context.Background(), models.ProfileRequest{Experience: 1}, candidate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Service.UpdateProfile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_DeleteProfile(t *testing.T) {
+	mc := gomock.NewController(t)
+	MockUserRepo := repository.NewMockUserRepo(mc)
+	MockUserRepo.EXPECT().DeleteProfile(gomock.Any(), uint(4)).Return(gorm.ErrRecordNotFound)
+	s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+	err := s.DeleteProfile(context.Background(), auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "4"}})
+	if !errors.Is(err, ErrProfileNotFound) {
+		t.Errorf("Service.DeleteProfile() error = %v, want %v", err, ErrProfileNotFound)
+	}
+}
+
+func TestCandidateProfile_Details(t *testing.T) {
+	p := models.CandidateProfile{
+		NoticePeriod: 15,
+		Experience:   3.5,
+		WorkModes:    []models.WorkMode{{Model: gorm.Model{ID: 2}}},
+		JobTypes:     []models.JobType{{Model: gorm.Model{ID: 1}}},
+	}
+	want := models.RequestFromUser{NoticePeriod: 15, Experience: 3.5, WorkModeIDs: []uint{2}, JobType: []uint{1}}
+	if got := p.Details(); !reflect.DeepEqual(got, want) {
+		t.Errorf("CandidateProfile.Details() = %+v, want %+v", got, want)
+	}
+}
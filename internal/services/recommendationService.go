@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+const (
+	// recommendationPool is how many of the newest open jobs are ranked
+	recommendationPool = 1000
+	// recommendationLimit is how many of the best jobs are kept
+	recommendationLimit = 200
+	// recommendationTTL bounds how long a ranking lives without any change
+	recommendationTTL = time.Hour
+)
+
+// ErrNoPreferences is returned when recommending jobs without preferences in
+// the request and without a profile to take them from
+var ErrNoPreferences = errors.New("send your preferences or create a profile first")
+
+// RecommendJobs ranks the open jobs for the logged in candidate, best score
+// first. The preferences in the query are used when there are any, else the
+// candidate profile; only the ranking from the profile is cached.
+func (s *Service) RecommendJobs(ctx context.Context, q models.RecommendationQuery, claims auth.Claims) (models.RecommendationPage, error) {
+	uid, err := claims.UserId()
+	if err != nil {
+		return models.RecommendationPage{}, err
+	}
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.PageSize == 0 {
+		q.PageSize = models.DefaultJobPageSize
+	}
+
+	var ranked []models.RecommendedJob
+	if q.HasPreferences() {
+		ranked, err = s.rankJobs(ctx, q.Preferences())
+	} else {
+		ranked, err = s.profileRecommendations(ctx, uid)
+	}
+	if err != nil {
+		return models.RecommendationPage{}, err
+	}
+
+	// A cached job may have expired since it was ranked
+	now := time.Now()
+	open := make([]models.RecommendedJob, 0, len(ranked))
+	for _, r := range ranked {
+		if r.Job.IsOpen(now) {
+			open = append(open, r)
+		}
+	}
+	page := models.RecommendationPage{Jobs: []models.RecommendedJob{}, Total: len(open), Page: q.Page, PageSize: q.PageSize}
+	start := (q.Page - 1) * q.PageSize
+	if start < len(open) {
+		page.Jobs = open[start:min(start+q.PageSize, len(open))]
+	}
+	return page, nil
+}
+
+// profileRecommendations returns the ranking for the profile of a user from
+// the cache, ranking again when the cached one is from an older generation.
+// The cache only saves work, when redis fails the jobs are ranked anyway.
+func (s *Service) profileRecommendations(ctx context.Context, uid uint) ([]models.RecommendedJob, error) {
+	gen, genErr := s.rdb.RecommendationsGeneration(ctx)
+	if genErr != nil {
+		log.Error().Err(genErr).Msg("reading recommendations generation")
+	} else {
+		rec, err := s.rdb.GetRecommendations(ctx, uid)
+		if err == nil && rec.Generation == gen {
+			return rec.Jobs, nil
+		}
+		if err != nil && !errors.Is(err, redis.Nil) {
+			log.Error().Err(err).Uint("User Id", uid).Msg("reading cached recommendations")
+		}
+	}
+
+	p, err := s.UserRepo.GetProfile(ctx, uid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrNoPreferences
+	}
+	if err != nil {
+		return nil, err
+	}
+	ranked, err := s.rankJobs(ctx, p.Details())
+	if err != nil {
+		return nil, err
+	}
+	// Jobs changed after gen was read start a newer generation, so a ranking
+	// that missed them is never taken for a current one
+	if genErr == nil {
+		err = s.rdb.SaveRecommendations(ctx, uid, models.Recommendations{Generation: gen, Jobs: ranked}, recommendationTTL)
+		if err != nil {
+			log.Error().Err(err).Uint("User Id", uid).Msg("caching recommendations")
+		}
+	}
+	return ranked, nil
+}
+
+// rankJobs scores the newest open jobs with the criteria of compareData and
+// keeps the best ones, jobs that score nothing are left out. Work modes are
+// no match criterion, among jobs of the same score those in a preferred work
+// mode come first.
+func (s *Service) rankJobs(ctx context.Context, prefs models.RequestFromUser) ([]models.RecommendedJob, error) {
+	jobs, err := s.UserRepo.OpenJobs(ctx, recommendationPool)
+	if err != nil {
+		return nil, err
+	}
+	ranked := []models.RecommendedJob{}
+	for _, j := range jobs {
+		m := s.compareData(models.NewUserApplication{ID: uint64(j.ID), Jobs: prefs}, j)
+		if m.Score <= 0 {
+			continue
+		}
+		// Rankings are cached and shown to candidates, so the job is hidden
+		// like in the listings before it is kept
+		hideSalary(&j)
+		hideMatchRules(&j)
+		r := models.RecommendedJob{Job: j, Score: m.Score, Criteria: make([]models.CriterionScore, 0, len(m.Criteria))}
+		for _, c := range m.Criteria {
+			r.Criteria = append(r.Criteria, models.CriterionScore{Criterion: c.Criterion, Score: c.Score})
+		}
+		ranked = append(ranked, r)
+	}
+	// Jobs come newest first, which breaks the remaining ties
+	sort.SliceStable(ranked, func(a, b int) bool {
+		if ranked[a].Score != ranked[b].Score {
+			return ranked[a].Score > ranked[b].Score
+		}
+		return overlap(prefs.WorkModeIDs, workModeIDs(ranked[a].Job.WorkModes)) > overlap(prefs.WorkModeIDs, workModeIDs(ranked[b].Job.WorkModes))
+	})
+	if len(ranked) > recommendationLimit {
+		ranked = ranked[:recommendationLimit]
+	}
+	return ranked, nil
+}
+
+// invalidateRecommendations outdates the cached recommendations of every
+// user, a failure is only logged and the rankings expire with their TTL
+func (s *Service) invalidateRecommendations(ctx context.Context) {
+	err := s.rdb.InvalidateRecommendations(ctx)
+	if err != nil {
+		log.Error().Err(err).Msg("invalidating recommendations")
+	}
+}
+
+// forgetRecommendations drops the cached recommendations of a user whose
+// preferences changed
+func (s *Service) forgetRecommendations(ctx context.Context, uid uint) {
+	err := s.rdb.DeleteRecommendations(ctx, uid)
+	if err != nil {
+		log.Error().Err(err).Uint("User Id", uid).Msg("dropping cached recommendations")
+	}
+}
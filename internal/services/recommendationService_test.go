@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/caching"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestService_RecommendJobs(t *testing.T) {
+	candidate := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "4"}, Roles: []string{models.RoleCandidate}}
+	open := func(id uint, minNP int, maxNP uint64, minExp, maxExp float64) models.Job {
+		return models.Job{Model: gorm.Model{ID: id}, Status: models.JobPublished,
+			MinimumNoticePeriod: minNP, MaximumNoticePeriod: maxNP, MinExperience: minExp, MaxExperience: maxExp}
+	}
+	// scores 57.14: notice period, experience, location and skills
+	best := open(1, 0, 60, 1, 5)
+	best.Locations = []models.Location{{Model: gorm.Model{ID: 1}}}
+	best.Skills = []models.Skill{{Model: gorm.Model{ID: 3}}}
+	// neither is shown to candidates
+	best.SalaryMin, best.SalaryMax, best.SalaryHidden = 10, 20, true
+	best.MatchRules = &models.MatchRules{RequiredSkillIDs: []uint{3}}
+	// both score 28.57, remote is in the preferred work mode
+	onsite := open(2, 0, 60, 0, 5)
+	remote := open(4, 0, 60, 0, 5)
+	remote.WorkModes = []models.WorkMode{{Model: gorm.Model{ID: 2}}}
+	// scores nothing
+	senior := open(3, 90, 120, 10, 20)
+	jobs := []models.Job{best, onsite, senior, remote}
+
+	np, exp := 30, 2.0
+	asked := models.RecommendationQuery{NoticePeriod: &np, Experience: &exp, LocationIDs: []uint{1}, SkillIDs: []uint{3}, WorkModeIDs: []uint{2}}
+	profile := models.CandidateProfile{UserId: 4, NoticePeriod: 30, Experience: 2,
+		Locations: []models.Location{{Model: gorm.Model{ID: 1}}}, Skills: []models.Skill{{Model: gorm.Model{ID: 3}}}, WorkModes: []models.WorkMode{{Model: gorm.Model{ID: 2}}}}
+	past := time.Now().Add(-time.Minute)
+	gone := open(9, 0, 60, 0, 5)
+	gone.ExpiresAt = &past
+	cached := models.Recommendations{Generation: 6, Jobs: []models.RecommendedJob{
+		{Job: gone, Score: 80},
+		{Job: onsite, Score: 28.57},
+	}}
+
+	tests := []struct {
+		name       string
+		q          models.RecommendationQuery
+		setupMocks func(mr *repository.MockUserRepo, mc *caching.MockCache)
+		wantIDs    []uint
+		wantTotal  int
+		wantErr    error
+	}{
+		{name: "preferences in the request are ranked without the cache", q: asked,
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().OpenJobs(gomock.Any(), recommendationPool).Return(jobs, nil)
+			},
+			wantIDs: []uint{1, 4, 2}, wantTotal: 3,
+		},
+		{name: "second page", q: func() models.RecommendationQuery { q := asked; q.Page, q.PageSize = 2, 2; return q }(),
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().OpenJobs(gomock.Any(), recommendationPool).Return(jobs, nil)
+			},
+			wantIDs: []uint{2}, wantTotal: 3,
+		},
+		{name: "current ranking from the cache without expired jobs",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().RecommendationsGeneration(gomock.Any()).Return(int64(6), nil)
+				mc.EXPECT().GetRecommendations(gomock.Any(), uint(4)).Return(cached, nil)
+			},
+			wantIDs: []uint{2}, wantTotal: 1,
+		},
+		{name: "ranking of an older generation ranked again from the profile",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().RecommendationsGeneration(gomock.Any()).Return(int64(7), nil)
+				mc.EXPECT().GetRecommendations(gomock.Any(), uint(4)).Return(cached, nil)
+				mr.EXPECT().GetProfile(gomock.Any(), uint(4)).Return(profile, nil)
+				mr.EXPECT().OpenJobs(gomock.Any(), recommendationPool).Return(jobs, nil)
+				mc.EXPECT().SaveRecommendations(gomock.Any(), uint(4), gomock.Any(), recommendationTTL).
+					DoAndReturn(func(ctx context.Context, uid uint, rec models.Recommendations, ttl time.Duration) error {
+						if rec.Generation != 7 || len(rec.Jobs) != 3 {
+							t.Errorf("cached %d jobs of generation %d", len(rec.Jobs), rec.Generation)
+						}
+						return nil
+					})
+			},
+			wantIDs: []uint{1, 4, 2}, wantTotal: 3,
+		},
+		{name: "nothing cached and no profile",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().RecommendationsGeneration(gomock.Any()).Return(int64(0), nil)
+				mc.EXPECT().GetRecommendations(gomock.Any(), uint(4)).Return(models.Recommendations{}, redis.Nil)
+				mr.EXPECT().GetProfile(gomock.Any(), uint(4)).Return(models.CandidateProfile{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrNoPreferences,
+		},
+		{name: "ranked without caching when redis is down",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().RecommendationsGeneration(gomock.Any()).Return(int64(0), errors.New("redis down"))
+				mr.EXPECT().GetProfile(gomock.Any(), uint(4)).Return(profile, nil)
+				mr.EXPECT().OpenJobs(gomock.Any(), recommendationPool).Return(jobs, nil)
+			},
+			wantIDs: []uint{1, 4, 2}, wantTotal: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockCache := caching.NewMockCache(mc)
+			tt.setupMocks(MockUserRepo, MockCache)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+			got, err := s.RecommendJobs(context.Background(), tt.q, candidate)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Service.RecommendJobs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			ids := []uint{}
+			for _, r := range got.Jobs {
+				ids = append(ids, r.Job.ID)
+				if r.Job.SalaryMin != 0 || r.Job.SalaryMax != 0 || r.Job.MatchRules != nil {
+					t.Errorf("Service.RecommendJobs() job %d shows its hidden salary or match rules", r.Job.ID)
+				}
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) || got.Total != tt.wantTotal {
+				t.Errorf("Service.RecommendJobs() = %v of %d, want %v of %d", ids, got.Total, tt.wantIDs, tt.wantTotal)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/storage"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+)
+
+// Errors returned for resumes
+var (
+	ErrResumeNotFound = errors.New("resume not found")
+	ErrResumeTooLarge = fmt.Errorf("resume must not be larger than %d MiB", models.MaxResumeSize>>20)
+	ErrResumeType     = errors.New("resume must be a PDF or DOCX file")
+	ErrResumeLink     = errors.New("download link is invalid or has expired")
+	ErrNoBlobStore    = errors.New("resume storage is not configured")
+)
+
+// UploadResume stores a resume of the logged in user, attached to one of the
+// user's applications when ApplicationId is set. The type is decided from the
+// content, whatever the file is named.
+func (s *Service) UploadResume(ctx context.Context, up models.ResumeUpload, claims auth.Claims) (models.Resume, error) {
+	if s.blobs == nil {
+		return models.Resume{}, ErrNoBlobStore
+	}
+	uid, err := claims.UserId()
+	if err != nil {
+		return models.Resume{}, err
+	}
+	if up.Size > models.MaxResumeSize {
+		return models.Resume{}, ErrResumeTooLarge
+	}
+	if up.ApplicationId != nil {
+		_, err = s.ownApplication(ctx, *up.ApplicationId, claims)
+		if err != nil {
+			return models.Resume{}, err
+		}
+	}
+	contentType, err := sniffResume(up.File, up.Size)
+	if err != nil {
+		return models.Resume{}, err
+	}
+	_, err = up.File.Seek(0, io.SeekStart)
+	if err != nil {
+		return models.Resume{}, err
+	}
+
+	key := fmt.Sprintf("resumes/%d/%s", uid, uuid.NewString())
+	err = s.blobs.Put(ctx, key, up.File, up.Size, contentType)
+	if err != nil {
+		return models.Resume{}, err
+	}
+	rs, err := s.UserRepo.CreateResume(ctx, models.Resume{
+		UserId:        uid,
+		ApplicationId: up.ApplicationId,
+		FileName:      resumeFileName(up.FileName, contentType),
+		ContentType:   contentType,
+		Size:          up.Size,
+		BlobKey:       key,
+	})
+	if err != nil {
+		s.deleteBlob(key)
+		return models.Resume{}, err
+	}
+	return rs, nil
+}
+
+// sniffResume returns the type of a PDF or DOCX file. A DOCX file is a zip
+// archive holding word/document.xml.
+func sniffResume(f models.ResumeFile, size int64) (string, error) {
+	head := make([]byte, 512)
+	n, err := f.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	switch http.DetectContentType(head[:n]) {
+	case "application/pdf":
+		return models.ResumePDF, nil
+	case "application/zip":
+		zr, err := zip.NewReader(f, size)
+		if err != nil {
+			return "", ErrResumeType
+		}
+		for _, zf := range zr.File {
+			if zf.Name == "word/document.xml" {
+				return models.ResumeDOCX, nil
+			}
+		}
+	}
+	return "", ErrResumeType
+}
+
+// resumeFileName keeps the base of the name the file was uploaded with, with
+// the extension of its actual type
+func resumeFileName(name, contentType string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.TrimSuffix(name, filepath.Ext(name))
+	if name == "" || name == "." || name == "/" {
+		name = "resume"
+	}
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	if contentType == models.ResumeDOCX {
+		return name + ".docx"
+	}
+	return name + ".pdf"
+}
+
+// ViewMyResumes lists the resumes of the logged in user
+func (s *Service) ViewMyResumes(ctx context.Context, claims auth.Claims) ([]models.Resume, error) {
+	uid, err := claims.UserId()
+	if err != nil {
+		return nil, err
+	}
+	return s.UserRepo.ListUserResumes(ctx, uid)
+}
+
+// ViewApplicationResumes lists the resumes attached to an application to the
+// candidate who made it and to the members of the company of the job
+func (s *Service) ViewApplicationResumes(ctx context.Context, aid uint, claims auth.Claims) ([]models.Resume, error) {
+	_, err := s.visibleApplication(ctx, aid, claims)
+	if err != nil {
+		return nil, err
+	}
+	return s.UserRepo.ListApplicationResumes(ctx, aid)
+}
+
+// DeleteResume deletes a resume of the logged in user with its file
+func (s *Service) DeleteResume(ctx context.Context, id uint, claims auth.Claims) error {
+	rs, err := s.getResume(ctx, id)
+	if err != nil {
+		return err
+	}
+	uid, err := claims.UserId()
+	if err != nil {
+		return err
+	}
+	if rs.UserId != uid {
+		return ErrResumeNotFound
+	}
+	err = s.UserRepo.DeleteResume(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrResumeNotFound
+	}
+	if err != nil {
+		return err
+	}
+	s.deleteBlob(rs.BlobKey)
+	return nil
+}
+
+// ResumeLink returns a download link for a resume to its owner, to admins and,
+// when it is attached to an application, to the members of the company of
+// the job
+func (s *Service) ResumeLink(ctx context.Context, id uint, claims auth.Claims) (models.ResumeLink, error) {
+	rs, err := s.getResume(ctx, id)
+	if err != nil {
+		return models.ResumeLink{}, err
+	}
+	uid, err := claims.UserId()
+	if err != nil {
+		return models.ResumeLink{}, err
+	}
+	if rs.UserId != uid && !claims.HasRole(models.RoleAdmin) {
+		if rs.ApplicationId == nil {
+			return models.ResumeLink{}, ErrResumeNotFound
+		}
+		_, err = s.visibleApplication(ctx, *rs.ApplicationId, claims)
+		if err != nil {
+			return models.ResumeLink{}, err
+		}
+	}
+	expires := time.Now().Add(models.ResumeLinkTTL).Truncate(time.Second)
+	return models.ResumeLink{
+		URL:       fmt.Sprintf("%s/resumes/%d/download?expires=%d&sig=%s", s.appURL, id, expires.Unix(), s.linkSignature(id, expires.Unix())),
+		ExpiresAt: expires.UTC(),
+	}, nil
+}
+
+// OpenResume checks a download link and opens the file it points to
+func (s *Service) OpenResume(ctx context.Context, id uint, expires int64, sig string) (models.Resume, io.ReadCloser, error) {
+	if s.blobs == nil {
+		return models.Resume{}, nil, ErrNoBlobStore
+	}
+	if time.Now().Unix() > expires || !hmac.Equal([]byte(sig), []byte(s.linkSignature(id, expires))) {
+		return models.Resume{}, nil, ErrResumeLink
+	}
+	rs, err := s.getResume(ctx, id)
+	if err != nil {
+		return models.Resume{}, nil, err
+	}
+	rc, err := s.blobs.Get(ctx, rs.BlobKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return models.Resume{}, nil, ErrResumeNotFound
+	}
+	if err != nil {
+		return models.Resume{}, nil, err
+	}
+	return rs, rc, nil
+}
+
+func (s *Service) linkSignature(id uint, expires int64) string {
+	h := hmac.New(sha256.New, s.linkSecret)
+	h.Write([]byte(strconv.FormatUint(uint64(id), 10) + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (s *Service) getResume(ctx context.Context, id uint) (models.Resume, error) {
+	rs, err := s.UserRepo.GetResume(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Resume{}, ErrResumeNotFound
+	}
+	if err != nil {
+		return models.Resume{}, err
+	}
+	return rs, nil
+}
+
+// deleteBlob removes a file nothing points to any more, a failure only
+// leaves an orphan behind so it is logged
+func (s *Service) deleteBlob(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := s.blobs.Delete(ctx, key)
+	if err != nil {
+		log.Error().Err(err).Str("Key", key).Msg("deleting blob")
+	}
+}
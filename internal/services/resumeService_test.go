@@ -0,0 +1,240 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/caching"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"job-portal-api/internal/storage"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+var testPDF = []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\n%%EOF\n")
+
+func testDOCX(t *testing.T) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	zw := zip.NewWriter(&b)
+	for _, name := range []string{"[Content_Types].xml", "word/document.xml"} {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte("<xml/>"))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return b.Bytes()
+}
+
+func TestService_UploadResume(t *testing.T) {
+	candidate := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "4"}, Roles: []string{models.RoleCandidate}}
+	aid := uint(8)
+	var plainZip bytes.Buffer
+	zw := zip.NewWriter(&plainZip)
+	zw.Create("notes.txt")
+	zw.Close()
+	docx := testDOCX(t)
+	tests := []struct {
+		name       string
+		content    []byte
+		size       int64
+		app        *uint
+		setupMocks func(mr *repository.MockUserRepo, mb *storage.MockBlobStore)
+		wantType   string
+		wantName   string
+		wantErr    error
+	}{
+		{name: "pdf stored", content: testPDF,
+			setupMocks: func(mr *repository.MockUserRepo, mb *storage.MockBlobStore) {
+				mb.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), int64(len(testPDF)), models.ResumePDF).
+					DoAndReturn(func(ctx context.Context, key string, r io.Reader, size int64, ct string) error {
+						b, _ := io.ReadAll(r)
+						if !bytes.Equal(b, testPDF) {
+							t.Errorf("stored %q, want the whole file", b)
+						}
+						return nil
+					})
+				mr.EXPECT().CreateResume(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, rs models.Resume) (models.Resume, error) {
+					return rs, nil
+				})
+			},
+			wantType: models.ResumePDF, wantName: "cv.pdf",
+		},
+		{name: "docx detected from the content", content: docx, app: &aid,
+			setupMocks: func(mr *repository.MockUserRepo, mb *storage.MockBlobStore) {
+				mr.EXPECT().GetApplication(gomock.Any(), uint(8)).Return(models.Application{Model: gorm.Model{ID: 8}, UserId: 4}, nil)
+				mb.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), int64(len(docx)), models.ResumeDOCX).Return(nil)
+				mr.EXPECT().CreateResume(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, rs models.Resume) (models.Resume, error) {
+					if rs.ApplicationId == nil || *rs.ApplicationId != 8 {
+						t.Errorf("resume not attached to the application")
+					}
+					return rs, nil
+				})
+			},
+			wantType: models.ResumeDOCX, wantName: "cv.docx",
+		},
+		{name: "text file rejected", content: []byte("just some text"),
+			setupMocks: func(mr *repository.MockUserRepo, mb *storage.MockBlobStore) {},
+			wantErr:    ErrResumeType,
+		},
+		{name: "zip without a document rejected", content: plainZip.Bytes(),
+			setupMocks: func(mr *repository.MockUserRepo, mb *storage.MockBlobStore) {},
+			wantErr:    ErrResumeType,
+		},
+		{name: "too large", content: testPDF, size: models.MaxResumeSize + 1,
+			setupMocks: func(mr *repository.MockUserRepo, mb *storage.MockBlobStore) {},
+			wantErr:    ErrResumeTooLarge,
+		},
+		{name: "application of another candidate", content: testPDF, app: &aid,
+			setupMocks: func(mr *repository.MockUserRepo, mb *storage.MockBlobStore) {
+				mr.EXPECT().GetApplication(gomock.Any(), uint(8)).Return(models.Application{Model: gorm.Model{ID: 8}, UserId: 9}, nil)
+			},
+			wantErr: ErrApplicationNotFound,
+		},
+		{name: "file removed when the record is not stored", content: testPDF,
+			setupMocks: func(mr *repository.MockUserRepo, mb *storage.MockBlobStore) {
+				mb.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				mr.EXPECT().CreateResume(gomock.Any(), gomock.Any()).Return(models.Resume{}, errors.New("resume not created"))
+				mb.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			wantErr: errors.New("resume not created"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockBlobStore := storage.NewMockBlobStore(mc)
+			tt.setupMocks(MockUserRepo, MockBlobStore)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil, WithBlobStore(MockBlobStore))
+			size := tt.size
+			if size == 0 {
+				size = int64(len(tt.content))
+			}
+			got, err := s.UploadResume(context.Background(), models.ResumeUpload{
+				FileName: "cv.txt", Size: size, ApplicationId: tt.app, File: bytes.NewReader(tt.content),
+			}, candidate)
+			if tt.wantErr != nil {
+				if err == nil || err.Error() != tt.wantErr.Error() {
+					t.Fatalf("Service.UploadResume() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Service.UploadResume() error = %v", err)
+			}
+			if got.ContentType != tt.wantType || got.FileName != tt.wantName || got.UserId != 4 {
+				t.Errorf("Service.UploadResume() = %+v, want %s %s", got, tt.wantType, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestService_ResumeLink(t *testing.T) {
+	owner := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "4"}, Roles: []string{models.RoleCandidate}}
+	other := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "6"}, Roles: []string{models.RoleCandidate}}
+	recruiter := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Roles: []string{models.RoleRecruiter}}
+	aid := uint(8)
+	rs := models.Resume{Model: gorm.Model{ID: 3}, UserId: 4, ApplicationId: &aid, FileName: "cv.pdf", ContentType: models.ResumePDF, Size: int64(len(testPDF)), BlobKey: "resumes/4/x"}
+	tests := []struct {
+		name       string
+		claims     auth.Claims
+		setupMocks func(mr *repository.MockUserRepo)
+		wantErr    error
+	}{
+		{name: "owner", claims: owner,
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetResume(gomock.Any(), uint(3)).Return(rs, nil)
+			},
+		},
+		{name: "member of the company of the application", claims: recruiter,
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetResume(gomock.Any(), uint(3)).Return(rs, nil)
+				mr.EXPECT().GetApplication(gomock.Any(), uint(8)).Return(models.Application{UserId: 4, Job: models.Job{CompanyId: 2}}, nil)
+				mr.EXPECT().GetCompanyMember(uint64(2), uint(1)).Return(models.CompanyMember{Role: models.MemberRecruiter}, nil)
+			},
+		},
+		{name: "another candidate", claims: other,
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetResume(gomock.Any(), uint(3)).Return(rs, nil)
+				mr.EXPECT().GetApplication(gomock.Any(), uint(8)).Return(models.Application{UserId: 4, Job: models.Job{CompanyId: 2}}, nil)
+				mr.EXPECT().GetCompanyMember(uint64(2), uint(6)).Return(models.CompanyMember{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrNotCompanyMember,
+		},
+		{name: "resume does not exist", claims: owner,
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetResume(gomock.Any(), uint(3)).Return(models.Resume{}, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrResumeNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockBlobStore := storage.NewMockBlobStore(mc)
+			tt.setupMocks(MockUserRepo)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil,
+				WithAppURL("http://api.test"), WithBlobStore(MockBlobStore), WithLinkSecret("secret"))
+			link, err := s.ResumeLink(context.Background(), 3, tt.claims)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Service.ResumeLink() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			// the link opens the file until it expires
+			u, err := url.Parse(link.URL)
+			if err != nil || u.Path != "/resumes/3/download" {
+				t.Fatalf("Service.ResumeLink() url = %q", link.URL)
+			}
+			expires, _ := strconv.ParseInt(u.Query().Get("expires"), 10, 64)
+			sig := u.Query().Get("sig")
+			MockUserRepo.EXPECT().GetResume(gomock.Any(), uint(3)).Return(rs, nil)
+			MockBlobStore.EXPECT().Get(gomock.Any(), "resumes/4/x").Return(io.NopCloser(bytes.NewReader(testPDF)), nil)
+			got, rc, err := s.OpenResume(context.Background(), 3, expires, sig)
+			if err != nil {
+				t.Fatalf("Service.OpenResume() error = %v", err)
+			}
+			rc.Close()
+			if got.FileName != "cv.pdf" {
+				t.Errorf("Service.OpenResume() = %+v", got)
+			}
+			for _, bad := range []struct {
+				id      uint
+				expires int64
+			}{{4, expires}, {3, expires + 60}} {
+				_, _, err = s.OpenResume(context.Background(), bad.id, bad.expires, sig)
+				if !errors.Is(err, ErrResumeLink) {
+					t.Errorf("Service.OpenResume(%d, %d) error = %v, want %v", bad.id, bad.expires, err, ErrResumeLink)
+				}
+			}
+		})
+	}
+}
+
+func TestService_OpenResumeExpired(t *testing.T) {
+	mc := gomock.NewController(t)
+	s := &Service{UserRepo: repository.NewMockUserRepo(mc), blobs: storage.NewMockBlobStore(mc), linkSecret: []byte("secret")}
+	expires := time.Now().Add(-time.Minute).Unix()
+	_, _, err := s.OpenResume(context.Background(), 3, expires, s.linkSignature(3, expires))
+	if !errors.Is(err, ErrResumeLink) {
+		t.Errorf("Service.OpenResume() error = %v, want %v", err, ErrResumeLink)
+	}
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// screeningWorkers is the most applications screened at once
+	screeningWorkers = 8
+	// maxRunningScreenings is the most screenings run at once by the process,
+	// so at most maxRunningScreenings*screeningWorkers background workers
+	maxRunningScreenings = 4
+	// screeningChunk applications are screened before the progress of a
+	// screening is saved
+	screeningChunk = 500
+	// Screenings and their results are kept for a day
+	screeningTTL = 24 * time.Hour
+	// screeningTimeout stops a screening that takes too long
+	screeningTimeout = time.Hour
+)
+
+// Errors returned for batch screenings
+var (
+	ErrScreeningNotFound = errors.New("screening not found")
+	ErrScreeningSize     = fmt.Errorf("a screening takes from 1 to %d applications", models.MaxScreeningSize)
+	ErrScreeningsBusy    = errors.New("too many screenings are running, try again later")
+)
+
+// SubmitScreening stores a new screening and screens its applications in the
+// background, the screening is returned before any application is screened.
+// ErrScreeningsBusy when maxRunningScreenings are running already.
+func (s *Service) SubmitScreening(ctx context.Context, applications []models.NewUserApplication, claims auth.Claims) (models.Screening, error) {
+	if len(applications) == 0 || len(applications) > models.MaxScreeningSize {
+		return models.Screening{}, ErrScreeningSize
+	}
+	uid, err := claims.UserId()
+	if err != nil {
+		return models.Screening{}, err
+	}
+	select {
+	case s.screenings <- struct{}{}:
+	default:
+		return models.Screening{}, ErrScreeningsBusy
+	}
+	sc := models.Screening{
+		ID:        uuid.NewString(),
+		Status:    models.ScreeningRunning,
+		CreatedBy: uid,
+		Total:     len(applications),
+		CreatedAt: time.Now().UTC(),
+	}
+	err = s.rdb.SaveScreening(ctx, sc, screeningTTL)
+	if err != nil {
+		<-s.screenings
+		return models.Screening{}, err
+	}
+	go s.runScreening(sc, applications)
+	return sc, nil
+}
+
+// runScreening screens the applications chunk by chunk and stores the results
+// of each chunk with the progress of the screening
+func (s *Service) runScreening(sc models.Screening, applications []models.NewUserApplication) {
+	defer func() { <-s.screenings }()
+	// The request that submitted the screening is over by now
+	ctx, cancel := context.WithTimeout(context.Background(), screeningTimeout)
+	defer cancel()
+
+	for start := 0; start < len(applications); start += screeningChunk {
+		end := start + screeningChunk
+		if end > len(applications) {
+			end = len(applications)
+		}
+		results, err := s.ProcessJobApplications(ctx, applications[start:end])
+		if err == nil {
+			err = s.saveScreeningChunk(ctx, &sc, results)
+		}
+		if err != nil {
+			log.Error().Err(err).Str("Screening Id", sc.ID).Int("Done", sc.Done).Msg("screening stopped")
+			s.failScreening(sc)
+			return
+		}
+	}
+}
+
+// saveScreeningChunk stores the results of a chunk and moves the progress of
+// sc on once they are stored
+func (s *Service) saveScreeningChunk(ctx context.Context, sc *models.Screening, results []models.ScreeningResult) error {
+	next := *sc
+	for _, r := range results {
+		switch r.Verdict {
+		case models.VerdictMatched:
+			next.Matched++
+		case models.VerdictError:
+			next.Errors++
+		}
+	}
+	next.Done += len(results)
+	if next.Done == next.Total {
+		finished := time.Now().UTC()
+		next.Status = models.ScreeningDone
+		next.FinishedAt = &finished
+	}
+	err := s.rdb.AddScreeningResults(ctx, next, results, screeningTTL)
+	if err != nil {
+		return err
+	}
+	*sc = next
+	return nil
+}
+
+// failScreening marks a screening as failed, the results stored so far are
+// kept
+func (s *Service) failScreening(sc models.Screening) {
+	finished := time.Now().UTC()
+	sc.Status = models.ScreeningFailed
+	sc.Error = fmt.Sprintf("screening stopped after %d of %d applications", sc.Done, sc.Total)
+	sc.FinishedAt = &finished
+	// The screening context may be what ran out
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := s.rdb.SaveScreening(ctx, sc, screeningTTL)
+	if err != nil {
+		log.Error().Err(err).Str("Screening Id", sc.ID).Msg("saving failed screening")
+	}
+}
+
+// ViewScreening returns the progress of a screening and a page of its
+// results. Only the user who submitted it and admins can see a screening.
+func (s *Service) ViewScreening(ctx context.Context, id string, q models.ScreeningQuery, claims auth.Claims) (models.ScreeningPage, error) {
+	sc, err := s.rdb.GetScreening(ctx, id)
+	if errors.Is(err, redis.Nil) {
+		return models.ScreeningPage{}, ErrScreeningNotFound
+	}
+	if err != nil {
+		return models.ScreeningPage{}, err
+	}
+	if !claims.HasRole(models.RoleAdmin) {
+		uid, err := claims.UserId()
+		if err != nil {
+			return models.ScreeningPage{}, err
+		}
+		if sc.CreatedBy != uid {
+			return models.ScreeningPage{}, ErrScreeningNotFound
+		}
+	}
+
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.PageSize == 0 {
+		q.PageSize = models.DefaultScreeningPageSize
+	}
+	start := int64((q.Page - 1) * q.PageSize)
+	page := models.ScreeningPage{Screening: sc, Page: q.Page, PageSize: q.PageSize, Results: []models.ScreeningResult{}}
+	if start >= int64(sc.Done) {
+		return page, nil
+	}
+	page.Results, err = s.rdb.GetScreeningResults(ctx, id, start, start+int64(q.PageSize)-1)
+	if err != nil {
+		return models.ScreeningPage{}, err
+	}
+	return page, nil
+}
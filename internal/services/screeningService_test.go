@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/caching"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestService_SubmitScreening(t *testing.T) {
+	recruiter := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Roles: []string{models.RoleRecruiter}}
+	mc := gomock.NewController(t)
+	MockUserRepo := repository.NewMockUserRepo(mc)
+	MockCache := caching.NewMockCache(mc)
+	s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+
+	_, err := s.SubmitScreening(context.Background(), nil, recruiter)
+	if !errors.Is(err, ErrScreeningSize) {
+		t.Fatalf("Service.SubmitScreening() error = %v, want %v", err, ErrScreeningSize)
+	}
+
+	// Every application goes to a job that does not exist, so the screening
+	// finishes with an error result for each of them
+	applications := make([]models.NewUserApplication, screeningChunk+1)
+	for i := range applications {
+		applications[i] = models.NewUserApplication{ID: 5}
+	}
+	MockCache.EXPECT().SaveScreening(gomock.Any(), gomock.Any(), screeningTTL).Return(nil)
+	MockCache.EXPECT().GetCache(gomock.Any(), uint(5)).Return("", redis.Nil).AnyTimes()
+	MockUserRepo.EXPECT().FetchJobData(gomock.Any(), uint64(5)).Return(models.Job{}, gorm.ErrRecordNotFound).AnyTimes()
+	saved := make(chan models.Screening, 2)
+	MockCache.EXPECT().AddScreeningResults(gomock.Any(), gomock.Any(), gomock.Any(), screeningTTL).Times(2).
+		DoAndReturn(func(ctx context.Context, sc models.Screening, results []models.ScreeningResult, ttl time.Duration) error {
+			saved <- sc
+			return nil
+		})
+
+	sc, err := s.SubmitScreening(context.Background(), applications, recruiter)
+	if err != nil || sc.Status != models.ScreeningRunning || sc.Total != len(applications) || sc.CreatedBy != 1 {
+		t.Fatalf("Service.SubmitScreening() = %+v, %v", sc, err)
+	}
+	first, last := <-saved, <-saved
+	if first.Done != screeningChunk || first.Status != models.ScreeningRunning {
+		t.Errorf("first chunk saved as %+v", first)
+	}
+	if last.Done != len(applications) || last.Errors != len(applications) || last.Status != models.ScreeningDone || last.FinishedAt == nil {
+		t.Errorf("last chunk saved as %+v", last)
+	}
+}
+
+func TestService_SubmitScreening_busy(t *testing.T) {
+	recruiter := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Roles: []string{models.RoleRecruiter}}
+	mc := gomock.NewController(t)
+	MockCache := caching.NewMockCache(mc)
+	s := &Service{rdb: MockCache, screenings: make(chan struct{}, maxRunningScreenings)}
+	for i := 0; i < maxRunningScreenings; i++ {
+		s.screenings <- struct{}{}
+	}
+	_, err := s.SubmitScreening(context.Background(), []models.NewUserApplication{{ID: 5}}, recruiter)
+	if !errors.Is(err, ErrScreeningsBusy) {
+		t.Fatalf("Service.SubmitScreening() error = %v, want %v", err, ErrScreeningsBusy)
+	}
+
+	// A screening that cannot be stored gives its slot back
+	<-s.screenings
+	MockCache.EXPECT().SaveScreening(gomock.Any(), gomock.Any(), screeningTTL).Return(errors.New("redis down"))
+	_, err = s.SubmitScreening(context.Background(), []models.NewUserApplication{{ID: 5}}, recruiter)
+	if err == nil {
+		t.Fatalf("Service.SubmitScreening() stored a screening redis refused")
+	}
+	if len(s.screenings) != maxRunningScreenings-1 {
+		t.Errorf("running screenings = %d, want %d", len(s.screenings), maxRunningScreenings-1)
+	}
+}
+
+func TestService_ViewScreening(t *testing.T) {
+	owner := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "1"}, Roles: []string{models.RoleRecruiter}}
+	other := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "2"}, Roles: []string{models.RoleRecruiter}}
+	admin := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{Subject: "3"}, Roles: []string{models.RoleAdmin}}
+	stored := models.Screening{ID: "abc", CreatedBy: 1, Total: 250, Done: 250, Status: models.ScreeningDone}
+	tests := []struct {
+		name        string
+		claims      auth.Claims
+		q           models.ScreeningQuery
+		setupMocks  func(mc *caching.MockCache)
+		wantResults int
+		wantErr     error
+	}{
+		{name: "screening does not exist", claims: owner,
+			setupMocks: func(mc *caching.MockCache) {
+				mc.EXPECT().GetScreening(gomock.Any(), "abc").Return(models.Screening{}, redis.Nil)
+			},
+			wantErr: ErrScreeningNotFound,
+		},
+		{name: "screening of another recruiter", claims: other,
+			setupMocks: func(mc *caching.MockCache) {
+				mc.EXPECT().GetScreening(gomock.Any(), "abc").Return(stored, nil)
+			},
+			wantErr: ErrScreeningNotFound,
+		},
+		{name: "first page by default", claims: owner,
+			setupMocks: func(mc *caching.MockCache) {
+				mc.EXPECT().GetScreening(gomock.Any(), "abc").Return(stored, nil)
+				mc.EXPECT().GetScreeningResults(gomock.Any(), "abc", int64(0), int64(99)).Return(make([]models.ScreeningResult, 100), nil)
+			},
+			wantResults: 100,
+		},
+		{name: "admin pages through the results", claims: admin, q: models.ScreeningQuery{Page: 3, PageSize: 100},
+			setupMocks: func(mc *caching.MockCache) {
+				mc.EXPECT().GetScreening(gomock.Any(), "abc").Return(stored, nil)
+				mc.EXPECT().GetScreeningResults(gomock.Any(), "abc", int64(200), int64(299)).Return(make([]models.ScreeningResult, 50), nil)
+			},
+			wantResults: 50,
+		},
+		{name: "page past the results", claims: owner, q: models.ScreeningQuery{Page: 4, PageSize: 100},
+			setupMocks: func(mc *caching.MockCache) {
+				mc.EXPECT().GetScreening(gomock.Any(), "abc").Return(stored, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockCache := caching.NewMockCache(mc)
+			tt.setupMocks(MockCache)
+			s, _ := NewService(repository.NewMockUserRepo(mc), &auth.Auth{}, MockCache, nil)
+			got, err := s.ViewScreening(context.Background(), "abc", tt.q, tt.claims)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Service.ViewScreening() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got.Results) != tt.wantResults {
+				t.Errorf("Service.ViewScreening() returned %d results, want %d", len(got.Results), tt.wantResults)
+			}
+		})
+	}
+}
+
+func TestService_ProcessJobApplications_cancelled(t *testing.T) {
+	mc := gomock.NewController(t)
+	MockCache := caching.NewMockCache(mc)
+	s, _ := NewService(repository.NewMockUserRepo(mc), &auth.Auth{}, MockCache, nil)
+	MockCache.EXPECT().GetCache(gomock.Any(), gomock.Any()).Return("", context.Canceled).AnyTimes()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := s.ProcessJobApplications(ctx, make([]models.NewUserApplication, 100))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Service.ProcessJobApplications() error = %v, want %v", err, context.Canceled)
+	}
+}
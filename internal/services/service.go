@@ -2,50 +2,159 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
+	"fmt"
+	"io"
 	"job-portal-api/internal/auth"
 	"job-portal-api/internal/caching"
+	"job-portal-api/internal/mailer"
 
 	"job-portal-api/internal/models"
 	"job-portal-api/internal/repository"
-
-	"github.com/golang-jwt/jwt/v5"
+	"job-portal-api/internal/storage"
+	"strings"
+	"time"
 )
 
 //go:generate mockgen -source=service.go -destination=service_mock.go -package=services
 
 type UserService interface {
 	Signup(ctx context.Context, userData models.NewUser) (models.User, error)
-	Login(ctx context.Context, email, password string) (jwt.RegisteredClaims, error)
+	Login(ctx context.Context, email, password string) (auth.Claims, error)
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) (string, error)
+	CreateRefreshToken(ctx context.Context, claims auth.Claims) (string, error)
+	RefreshToken(ctx context.Context, refreshToken string) (auth.Claims, string, error)
+	Logout(ctx context.Context, claims auth.Claims, refreshToken string) error
+	SetUserRole(ctx context.Context, uid uint, role string) (models.User, error)
 
-	AddCompanyDetails(ctx context.Context, companyData models.Company) (models.Company, error)
+	AddCompanyDetails(ctx context.Context, companyData models.Company, claims auth.Claims) (models.Company, error)
 	ViewAllCompanies(ctx context.Context) ([]models.Company, error)
 	ViewCompanyDetails(ctx context.Context, id uint64) (models.Company, error)
+	UpdateCompany(ctx context.Context, cid uint64, companyData models.Company, claims auth.Claims) (models.Company, error)
+	DeleteCompany(ctx context.Context, cid uint64, cascade bool, claims auth.Claims) error
+	RestoreCompany(ctx context.Context, cid uint64, claims auth.Claims) (models.Company, error)
+	AddCompanyMember(ctx context.Context, cid uint64, nm models.NewCompanyMember, claims auth.Claims) (models.CompanyMember, error)
+	ViewCompanyMembers(ctx context.Context, cid uint64, claims auth.Claims) ([]models.CompanyMember, error)
+	RemoveCompanyMember(ctx context.Context, cid uint64, uid uint, claims auth.Claims) error
 
 	ViewJobFromCompany(cid uint64) ([]models.Job, error)
-	AddJobDetails(ctx context.Context, jobData models.NewJobRequest, cid uint64) (models.Response, error)
-	ViewAllJobs(ctx context.Context) ([]models.Job, error)
+	AddJobDetails(ctx context.Context, jobData models.NewJobRequest, cid uint64, claims auth.Claims) (models.Response, error)
+	ViewAllJobs(ctx context.Context, q models.JobListQuery, claims auth.Claims) (models.JobPage, error)
+	SearchJobs(ctx context.Context, q models.JobSearchQuery, claims auth.Claims) (models.JobSearchPage, error)
 	ViewJobById(ctx context.Context, jid uint64) ([]models.Job, error)
+	UpdateJob(ctx context.Context, jid uint64, jobData models.NewJobRequest, claims auth.Claims) (models.Job, error)
+	PatchJob(ctx context.Context, jid uint64, patch models.JobPatchRequest, claims auth.Claims) (models.Job, error)
+	DeleteJob(ctx context.Context, jid uint64, claims auth.Claims) error
+	PublishJob(ctx context.Context, jid uint64, expiresAt *time.Time, claims auth.Claims) (models.Job, error)
+	PauseJob(ctx context.Context, jid uint64, claims auth.Claims) (models.Job, error)
+	CloseJob(ctx context.Context, jid uint64, claims auth.Claims) (models.Job, error)
+	ExpireJobs(ctx context.Context) error
+
+	ListTaxonomy(ctx context.Context, kind models.TaxonomyKind) ([]models.TaxonomyItem, error)
+	AddTaxonomy(ctx context.Context, kind models.TaxonomyKind, name string) (models.TaxonomyItem, error)
+	RenameTaxonomy(ctx context.Context, kind models.TaxonomyKind, id uint, name string) (models.TaxonomyItem, error)
+	DeleteTaxonomy(ctx context.Context, kind models.TaxonomyKind, id uint) error
+	MergeTaxonomy(ctx context.Context, kind models.TaxonomyKind, targetId uint, sourceIds []uint) (models.TaxonomyItem, error)
 
-	ProcessJobApplications(appData []models.NewUserApplication) ([]models.NewUserApplication, error)
+	ApplyForJob(ctx context.Context, jid uint64, na models.NewApplication, claims auth.Claims) (models.Application, error)
+	MoveApplication(ctx context.Context, aid uint, m models.MoveApplication, claims auth.Claims) (models.Application, error)
+	WithdrawApplication(ctx context.Context, aid uint, claims auth.Claims) (models.Application, error)
+	ViewJobApplications(ctx context.Context, jid uint64, stages []string, claims auth.Claims) ([]models.Application, error)
+	ViewMyApplications(ctx context.Context, claims auth.Claims) ([]models.Application, error)
+	ViewApplicationHistory(ctx context.Context, aid uint, claims auth.Claims) ([]models.ApplicationStageChange, error)
+	ProcessJobApplications(ctx context.Context, appData []models.NewUserApplication) ([]models.ScreeningResult, error)
+	StreamJobApplications(ctx context.Context, in <-chan models.StreamedApplication) <-chan models.ScreeningResult
+	SubmitScreening(ctx context.Context, appData []models.NewUserApplication, claims auth.Claims) (models.Screening, error)
+	ViewScreening(ctx context.Context, id string, q models.ScreeningQuery, claims auth.Claims) (models.ScreeningPage, error)
+	UploadResume(ctx context.Context, up models.ResumeUpload, claims auth.Claims) (models.Resume, error)
+	ViewMyResumes(ctx context.Context, claims auth.Claims) ([]models.Resume, error)
+	ViewApplicationResumes(ctx context.Context, aid uint, claims auth.Claims) ([]models.Resume, error)
+	DeleteResume(ctx context.Context, id uint, claims auth.Claims) error
+	ResumeLink(ctx context.Context, id uint, claims auth.Claims) (models.ResumeLink, error)
+	OpenResume(ctx context.Context, id uint, expires int64, sig string) (models.Resume, io.ReadCloser, error)
+	CreateProfile(ctx context.Context, pr models.ProfileRequest, claims auth.Claims) (models.CandidateProfile, error)
+	ViewProfile(ctx context.Context, claims auth.Claims) (models.CandidateProfile, error)
+	UpdateProfile(ctx context.Context, pr models.ProfileRequest, claims auth.Claims) (models.CandidateProfile, error)
+	DeleteProfile(ctx context.Context, claims auth.Claims) error
+	RecommendJobs(ctx context.Context, q models.RecommendationQuery, claims auth.Claims) (models.RecommendationPage, error)
 	OTPGeneration(ctx context.Context, data models.ForgotPassword) (string, error)
 	ChangePassword(ctx context.Context, otp models.OtpPassword) (string, error)
 }
 
+// Errors returned by the service layer that handlers map to status codes
+var (
+	ErrNotCompanyMember = errors.New("user is not allowed to act for this company")
+	ErrLastOwner        = errors.New("company must keep at least one owner")
+	ErrCompanyNotFound  = errors.New("company not found")
+	ErrCompanyHasJobs   = errors.New("company still has open jobs, close them first or pass cascade=true")
+)
+
 type Service struct {
 	UserRepo repository.UserRepo
 	auth     auth.Authentication
 	UserService
-	rdb caching.Cache
+	rdb    caching.Cache
+	mailer mailer.Mailer
+	// appURL is the public address of the api, used in links sent by email
+	appURL string
+	blobs  storage.BlobStore
+	// linkSecret signs resume download links
+	linkSecret []byte
+	// screenings holds a slot for each screening running in the background
+	screenings chan struct{}
 }
 
-func NewService(userRepo repository.UserRepo, a auth.Authentication, rdb caching.Cache) (UserService, error) {
+// Option changes an optional Service setting
+type Option func(*Service)
+
+// WithAppURL sets the public address used to build links in emails
+func WithAppURL(url string) Option {
+	return func(s *Service) {
+		s.appURL = strings.TrimRight(url, "/")
+	}
+}
+
+// WithBlobStore sets where uploaded resumes are kept
+func WithBlobStore(bs storage.BlobStore) Option {
+	return func(s *Service) {
+		s.blobs = bs
+	}
+}
+
+// WithLinkSecret sets the key download links are signed with, links signed
+// with a random key stop working when the app restarts
+func WithLinkSecret(secret string) Option {
+	return func(s *Service) {
+		if secret != "" {
+			s.linkSecret = []byte(secret)
+		}
+	}
+}
+
+func NewService(userRepo repository.UserRepo, a auth.Authentication, rdb caching.Cache, m mailer.Mailer, opts ...Option) (UserService, error) {
 	if userRepo == nil {
 		return nil, errors.New("interface cannot be nil")
 	}
-	return &Service{
+	s := &Service{
 		UserRepo: userRepo,
 		auth:     a,
 		rdb:      rdb,
-	}, nil
+		mailer:   m,
+		appURL:   "http://localhost:8080",
+		// Shared by every request, the limit is per process
+		screenings: make(chan struct{}, maxRunningScreenings),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.linkSecret == nil {
+		s.linkSecret = make([]byte, 32)
+		_, err := rand.Read(s.linkSecret)
+		if err != nil {
+			return nil, fmt.Errorf("generating link secret %w", err)
+		}
+	}
+	return s, nil
 }
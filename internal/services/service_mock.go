@@ -5,16 +5,17 @@
 //
 //	mockgen -source=service.go -destination=service_mock.go -package=services
 //
-
 // Package services is a generated GoMock package.
 package services
 
 import (
 	context "context"
+	io "io"
+	auth "job-portal-api/internal/auth"
 	models "job-portal-api/internal/models"
 	reflect "reflect"
+	time "time"
 
-	jwt "github.com/golang-jwt/jwt/v5"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,33 +43,78 @@ func (m *MockUserService) EXPECT() *MockUserServiceMockRecorder {
 }
 
 // AddCompanyDetails mocks base method.
-func (m *MockUserService) AddCompanyDetails(ctx context.Context, companyData models.Company) (models.Company, error) {
+func (m *MockUserService) AddCompanyDetails(ctx context.Context, companyData models.Company, claims auth.Claims) (models.Company, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddCompanyDetails", ctx, companyData)
+	ret := m.ctrl.Call(m, "AddCompanyDetails", ctx, companyData, claims)
 	ret0, _ := ret[0].(models.Company)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddCompanyDetails indicates an expected call of AddCompanyDetails.
-func (mr *MockUserServiceMockRecorder) AddCompanyDetails(ctx, companyData any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) AddCompanyDetails(ctx, companyData, claims any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCompanyDetails", reflect.TypeOf((*MockUserService)(nil).AddCompanyDetails), ctx, companyData)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCompanyDetails", reflect.TypeOf((*MockUserService)(nil).AddCompanyDetails), ctx, companyData, claims)
+}
+
+// AddCompanyMember mocks base method.
+func (m *MockUserService) AddCompanyMember(ctx context.Context, cid uint64, nm models.NewCompanyMember, claims auth.Claims) (models.CompanyMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCompanyMember", ctx, cid, nm, claims)
+	ret0, _ := ret[0].(models.CompanyMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCompanyMember indicates an expected call of AddCompanyMember.
+func (mr *MockUserServiceMockRecorder) AddCompanyMember(ctx, cid, nm, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCompanyMember", reflect.TypeOf((*MockUserService)(nil).AddCompanyMember), ctx, cid, nm, claims)
 }
 
 // AddJobDetails mocks base method.
-func (m *MockUserService) AddJobDetails(ctx context.Context, jobData models.NewJobRequest, cid uint64) (models.Response, error) {
+func (m *MockUserService) AddJobDetails(ctx context.Context, jobData models.NewJobRequest, cid uint64, claims auth.Claims) (models.Response, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddJobDetails", ctx, jobData, cid)
+	ret := m.ctrl.Call(m, "AddJobDetails", ctx, jobData, cid, claims)
 	ret0, _ := ret[0].(models.Response)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // AddJobDetails indicates an expected call of AddJobDetails.
-func (mr *MockUserServiceMockRecorder) AddJobDetails(ctx, jobData, cid any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) AddJobDetails(ctx, jobData, cid, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddJobDetails", reflect.TypeOf((*MockUserService)(nil).AddJobDetails), ctx, jobData, cid, claims)
+}
+
+// AddTaxonomy mocks base method.
+func (m *MockUserService) AddTaxonomy(ctx context.Context, kind models.TaxonomyKind, name string) (models.TaxonomyItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTaxonomy", ctx, kind, name)
+	ret0, _ := ret[0].(models.TaxonomyItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTaxonomy indicates an expected call of AddTaxonomy.
+func (mr *MockUserServiceMockRecorder) AddTaxonomy(ctx, kind, name any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddJobDetails", reflect.TypeOf((*MockUserService)(nil).AddJobDetails), ctx, jobData, cid)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTaxonomy", reflect.TypeOf((*MockUserService)(nil).AddTaxonomy), ctx, kind, name)
+}
+
+// ApplyForJob mocks base method.
+func (m *MockUserService) ApplyForJob(ctx context.Context, jid uint64, na models.NewApplication, claims auth.Claims) (models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyForJob", ctx, jid, na, claims)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyForJob indicates an expected call of ApplyForJob.
+func (mr *MockUserServiceMockRecorder) ApplyForJob(ctx, jid, na, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyForJob", reflect.TypeOf((*MockUserService)(nil).ApplyForJob), ctx, jid, na, claims)
 }
 
 // ChangePassword mocks base method.
@@ -86,11 +132,155 @@ func (mr *MockUserServiceMockRecorder) ChangePassword(ctx, otp any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserService)(nil).ChangePassword), ctx, otp)
 }
 
+// CloseJob mocks base method.
+func (m *MockUserService) CloseJob(ctx context.Context, jid uint64, claims auth.Claims) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseJob", ctx, jid, claims)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseJob indicates an expected call of CloseJob.
+func (mr *MockUserServiceMockRecorder) CloseJob(ctx, jid, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseJob", reflect.TypeOf((*MockUserService)(nil).CloseJob), ctx, jid, claims)
+}
+
+// CreateProfile mocks base method.
+func (m *MockUserService) CreateProfile(ctx context.Context, pr models.ProfileRequest, claims auth.Claims) (models.CandidateProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProfile", ctx, pr, claims)
+	ret0, _ := ret[0].(models.CandidateProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProfile indicates an expected call of CreateProfile.
+func (mr *MockUserServiceMockRecorder) CreateProfile(ctx, pr, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProfile", reflect.TypeOf((*MockUserService)(nil).CreateProfile), ctx, pr, claims)
+}
+
+// CreateRefreshToken mocks base method.
+func (m *MockUserService) CreateRefreshToken(ctx context.Context, claims auth.Claims) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefreshToken", ctx, claims)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRefreshToken indicates an expected call of CreateRefreshToken.
+func (mr *MockUserServiceMockRecorder) CreateRefreshToken(ctx, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefreshToken", reflect.TypeOf((*MockUserService)(nil).CreateRefreshToken), ctx, claims)
+}
+
+// DeleteCompany mocks base method.
+func (m *MockUserService) DeleteCompany(ctx context.Context, cid uint64, cascade bool, claims auth.Claims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCompany", ctx, cid, cascade, claims)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCompany indicates an expected call of DeleteCompany.
+func (mr *MockUserServiceMockRecorder) DeleteCompany(ctx, cid, cascade, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCompany", reflect.TypeOf((*MockUserService)(nil).DeleteCompany), ctx, cid, cascade, claims)
+}
+
+// DeleteJob mocks base method.
+func (m *MockUserService) DeleteJob(ctx context.Context, jid uint64, claims auth.Claims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteJob", ctx, jid, claims)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteJob indicates an expected call of DeleteJob.
+func (mr *MockUserServiceMockRecorder) DeleteJob(ctx, jid, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteJob", reflect.TypeOf((*MockUserService)(nil).DeleteJob), ctx, jid, claims)
+}
+
+// DeleteProfile mocks base method.
+func (m *MockUserService) DeleteProfile(ctx context.Context, claims auth.Claims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteProfile", ctx, claims)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteProfile indicates an expected call of DeleteProfile.
+func (mr *MockUserServiceMockRecorder) DeleteProfile(ctx, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteProfile", reflect.TypeOf((*MockUserService)(nil).DeleteProfile), ctx, claims)
+}
+
+// DeleteResume mocks base method.
+func (m *MockUserService) DeleteResume(ctx context.Context, id uint, claims auth.Claims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteResume", ctx, id, claims)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteResume indicates an expected call of DeleteResume.
+func (mr *MockUserServiceMockRecorder) DeleteResume(ctx, id, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteResume", reflect.TypeOf((*MockUserService)(nil).DeleteResume), ctx, id, claims)
+}
+
+// DeleteTaxonomy mocks base method.
+func (m *MockUserService) DeleteTaxonomy(ctx context.Context, kind models.TaxonomyKind, id uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTaxonomy", ctx, kind, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTaxonomy indicates an expected call of DeleteTaxonomy.
+func (mr *MockUserServiceMockRecorder) DeleteTaxonomy(ctx, kind, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTaxonomy", reflect.TypeOf((*MockUserService)(nil).DeleteTaxonomy), ctx, kind, id)
+}
+
+// ExpireJobs mocks base method.
+func (m *MockUserService) ExpireJobs(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireJobs", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExpireJobs indicates an expected call of ExpireJobs.
+func (mr *MockUserServiceMockRecorder) ExpireJobs(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireJobs", reflect.TypeOf((*MockUserService)(nil).ExpireJobs), ctx)
+}
+
+// ListTaxonomy mocks base method.
+func (m *MockUserService) ListTaxonomy(ctx context.Context, kind models.TaxonomyKind) ([]models.TaxonomyItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTaxonomy", ctx, kind)
+	ret0, _ := ret[0].([]models.TaxonomyItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTaxonomy indicates an expected call of ListTaxonomy.
+func (mr *MockUserServiceMockRecorder) ListTaxonomy(ctx, kind any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTaxonomy", reflect.TypeOf((*MockUserService)(nil).ListTaxonomy), ctx, kind)
+}
+
 // Login mocks base method.
-func (m *MockUserService) Login(ctx context.Context, email, password string) (jwt.RegisteredClaims, error) {
+func (m *MockUserService) Login(ctx context.Context, email, password string) (auth.Claims, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Login", ctx, email, password)
-	ret0, _ := ret[0].(jwt.RegisteredClaims)
+	ret0, _ := ret[0].(auth.Claims)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -101,6 +291,50 @@ func (mr *MockUserServiceMockRecorder) Login(ctx, email, password any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserService)(nil).Login), ctx, email, password)
 }
 
+// Logout mocks base method.
+func (m *MockUserService) Logout(ctx context.Context, claims auth.Claims, refreshToken string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, claims, refreshToken)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUserServiceMockRecorder) Logout(ctx, claims, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUserService)(nil).Logout), ctx, claims, refreshToken)
+}
+
+// MergeTaxonomy mocks base method.
+func (m *MockUserService) MergeTaxonomy(ctx context.Context, kind models.TaxonomyKind, targetId uint, sourceIds []uint) (models.TaxonomyItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeTaxonomy", ctx, kind, targetId, sourceIds)
+	ret0, _ := ret[0].(models.TaxonomyItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeTaxonomy indicates an expected call of MergeTaxonomy.
+func (mr *MockUserServiceMockRecorder) MergeTaxonomy(ctx, kind, targetId, sourceIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeTaxonomy", reflect.TypeOf((*MockUserService)(nil).MergeTaxonomy), ctx, kind, targetId, sourceIds)
+}
+
+// MoveApplication mocks base method.
+func (m_2 *MockUserService) MoveApplication(ctx context.Context, aid uint, m models.MoveApplication, claims auth.Claims) (models.Application, error) {
+	m_2.ctrl.T.Helper()
+	ret := m_2.ctrl.Call(m_2, "MoveApplication", ctx, aid, m, claims)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveApplication indicates an expected call of MoveApplication.
+func (mr *MockUserServiceMockRecorder) MoveApplication(ctx, aid, m, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveApplication", reflect.TypeOf((*MockUserService)(nil).MoveApplication), ctx, aid, m, claims)
+}
+
 // OTPGeneration mocks base method.
 func (m *MockUserService) OTPGeneration(ctx context.Context, data models.ForgotPassword) (string, error) {
 	m.ctrl.T.Helper()
@@ -116,19 +350,215 @@ func (mr *MockUserServiceMockRecorder) OTPGeneration(ctx, data any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OTPGeneration", reflect.TypeOf((*MockUserService)(nil).OTPGeneration), ctx, data)
 }
 
+// OpenResume mocks base method.
+func (m *MockUserService) OpenResume(ctx context.Context, id uint, expires int64, sig string) (models.Resume, io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenResume", ctx, id, expires, sig)
+	ret0, _ := ret[0].(models.Resume)
+	ret1, _ := ret[1].(io.ReadCloser)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// OpenResume indicates an expected call of OpenResume.
+func (mr *MockUserServiceMockRecorder) OpenResume(ctx, id, expires, sig any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenResume", reflect.TypeOf((*MockUserService)(nil).OpenResume), ctx, id, expires, sig)
+}
+
+// PatchJob mocks base method.
+func (m *MockUserService) PatchJob(ctx context.Context, jid uint64, patch models.JobPatchRequest, claims auth.Claims) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PatchJob", ctx, jid, patch, claims)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PatchJob indicates an expected call of PatchJob.
+func (mr *MockUserServiceMockRecorder) PatchJob(ctx, jid, patch, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PatchJob", reflect.TypeOf((*MockUserService)(nil).PatchJob), ctx, jid, patch, claims)
+}
+
+// PauseJob mocks base method.
+func (m *MockUserService) PauseJob(ctx context.Context, jid uint64, claims auth.Claims) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseJob", ctx, jid, claims)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PauseJob indicates an expected call of PauseJob.
+func (mr *MockUserServiceMockRecorder) PauseJob(ctx, jid, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseJob", reflect.TypeOf((*MockUserService)(nil).PauseJob), ctx, jid, claims)
+}
+
 // ProcessJobApplications mocks base method.
-func (m *MockUserService) ProcessJobApplications(appData []models.NewUserApplication) ([]models.NewUserApplication, error) {
+func (m *MockUserService) ProcessJobApplications(ctx context.Context, appData []models.NewUserApplication) ([]models.ScreeningResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ProcessJobApplications", appData)
-	ret0, _ := ret[0].([]models.NewUserApplication)
+	ret := m.ctrl.Call(m, "ProcessJobApplications", ctx, appData)
+	ret0, _ := ret[0].([]models.ScreeningResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ProcessJobApplications indicates an expected call of ProcessJobApplications.
-func (mr *MockUserServiceMockRecorder) ProcessJobApplications(appData any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) ProcessJobApplications(ctx, appData any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessJobApplications", reflect.TypeOf((*MockUserService)(nil).ProcessJobApplications), ctx, appData)
+}
+
+// PublishJob mocks base method.
+func (m *MockUserService) PublishJob(ctx context.Context, jid uint64, expiresAt *time.Time, claims auth.Claims) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishJob", ctx, jid, expiresAt, claims)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PublishJob indicates an expected call of PublishJob.
+func (mr *MockUserServiceMockRecorder) PublishJob(ctx, jid, expiresAt, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishJob", reflect.TypeOf((*MockUserService)(nil).PublishJob), ctx, jid, expiresAt, claims)
+}
+
+// RecommendJobs mocks base method.
+func (m *MockUserService) RecommendJobs(ctx context.Context, q models.RecommendationQuery, claims auth.Claims) (models.RecommendationPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecommendJobs", ctx, q, claims)
+	ret0, _ := ret[0].(models.RecommendationPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecommendJobs indicates an expected call of RecommendJobs.
+func (mr *MockUserServiceMockRecorder) RecommendJobs(ctx, q, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecommendJobs", reflect.TypeOf((*MockUserService)(nil).RecommendJobs), ctx, q, claims)
+}
+
+// RefreshToken mocks base method.
+func (m *MockUserService) RefreshToken(ctx context.Context, refreshToken string) (auth.Claims, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshToken", ctx, refreshToken)
+	ret0, _ := ret[0].(auth.Claims)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// RefreshToken indicates an expected call of RefreshToken.
+func (mr *MockUserServiceMockRecorder) RefreshToken(ctx, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshToken", reflect.TypeOf((*MockUserService)(nil).RefreshToken), ctx, refreshToken)
+}
+
+// RemoveCompanyMember mocks base method.
+func (m *MockUserService) RemoveCompanyMember(ctx context.Context, cid uint64, uid uint, claims auth.Claims) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveCompanyMember", ctx, cid, uid, claims)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveCompanyMember indicates an expected call of RemoveCompanyMember.
+func (mr *MockUserServiceMockRecorder) RemoveCompanyMember(ctx, cid, uid, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCompanyMember", reflect.TypeOf((*MockUserService)(nil).RemoveCompanyMember), ctx, cid, uid, claims)
+}
+
+// RenameTaxonomy mocks base method.
+func (m *MockUserService) RenameTaxonomy(ctx context.Context, kind models.TaxonomyKind, id uint, name string) (models.TaxonomyItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameTaxonomy", ctx, kind, id, name)
+	ret0, _ := ret[0].(models.TaxonomyItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameTaxonomy indicates an expected call of RenameTaxonomy.
+func (mr *MockUserServiceMockRecorder) RenameTaxonomy(ctx, kind, id, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameTaxonomy", reflect.TypeOf((*MockUserService)(nil).RenameTaxonomy), ctx, kind, id, name)
+}
+
+// ResendVerification mocks base method.
+func (m *MockUserService) ResendVerification(ctx context.Context, email string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerification", ctx, email)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResendVerification indicates an expected call of ResendVerification.
+func (mr *MockUserServiceMockRecorder) ResendVerification(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerification", reflect.TypeOf((*MockUserService)(nil).ResendVerification), ctx, email)
+}
+
+// RestoreCompany mocks base method.
+func (m *MockUserService) RestoreCompany(ctx context.Context, cid uint64, claims auth.Claims) (models.Company, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreCompany", ctx, cid, claims)
+	ret0, _ := ret[0].(models.Company)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreCompany indicates an expected call of RestoreCompany.
+func (mr *MockUserServiceMockRecorder) RestoreCompany(ctx, cid, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreCompany", reflect.TypeOf((*MockUserService)(nil).RestoreCompany), ctx, cid, claims)
+}
+
+// ResumeLink mocks base method.
+func (m *MockUserService) ResumeLink(ctx context.Context, id uint, claims auth.Claims) (models.ResumeLink, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeLink", ctx, id, claims)
+	ret0, _ := ret[0].(models.ResumeLink)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeLink indicates an expected call of ResumeLink.
+func (mr *MockUserServiceMockRecorder) ResumeLink(ctx, id, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeLink", reflect.TypeOf((*MockUserService)(nil).ResumeLink), ctx, id, claims)
+}
+
+// SearchJobs mocks base method.
+func (m *MockUserService) SearchJobs(ctx context.Context, q models.JobSearchQuery, claims auth.Claims) (models.JobSearchPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchJobs", ctx, q, claims)
+	ret0, _ := ret[0].(models.JobSearchPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchJobs indicates an expected call of SearchJobs.
+func (mr *MockUserServiceMockRecorder) SearchJobs(ctx, q, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchJobs", reflect.TypeOf((*MockUserService)(nil).SearchJobs), ctx, q, claims)
+}
+
+// SetUserRole mocks base method.
+func (m *MockUserService) SetUserRole(ctx context.Context, uid uint, role string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserRole", ctx, uid, role)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetUserRole indicates an expected call of SetUserRole.
+func (mr *MockUserServiceMockRecorder) SetUserRole(ctx, uid, role any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessJobApplications", reflect.TypeOf((*MockUserService)(nil).ProcessJobApplications), appData)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserRole", reflect.TypeOf((*MockUserService)(nil).SetUserRole), ctx, uid, role)
 }
 
 // Signup mocks base method.
@@ -146,6 +576,109 @@ func (mr *MockUserServiceMockRecorder) Signup(ctx, userData any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Signup", reflect.TypeOf((*MockUserService)(nil).Signup), ctx, userData)
 }
 
+// StreamJobApplications mocks base method.
+func (m *MockUserService) StreamJobApplications(ctx context.Context, in <-chan models.StreamedApplication) <-chan models.ScreeningResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamJobApplications", ctx, in)
+	ret0, _ := ret[0].(<-chan models.ScreeningResult)
+	return ret0
+}
+
+// StreamJobApplications indicates an expected call of StreamJobApplications.
+func (mr *MockUserServiceMockRecorder) StreamJobApplications(ctx, in any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamJobApplications", reflect.TypeOf((*MockUserService)(nil).StreamJobApplications), ctx, in)
+}
+
+// SubmitScreening mocks base method.
+func (m *MockUserService) SubmitScreening(ctx context.Context, appData []models.NewUserApplication, claims auth.Claims) (models.Screening, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitScreening", ctx, appData, claims)
+	ret0, _ := ret[0].(models.Screening)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitScreening indicates an expected call of SubmitScreening.
+func (mr *MockUserServiceMockRecorder) SubmitScreening(ctx, appData, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitScreening", reflect.TypeOf((*MockUserService)(nil).SubmitScreening), ctx, appData, claims)
+}
+
+// UpdateCompany mocks base method.
+func (m *MockUserService) UpdateCompany(ctx context.Context, cid uint64, companyData models.Company, claims auth.Claims) (models.Company, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCompany", ctx, cid, companyData, claims)
+	ret0, _ := ret[0].(models.Company)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCompany indicates an expected call of UpdateCompany.
+func (mr *MockUserServiceMockRecorder) UpdateCompany(ctx, cid, companyData, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCompany", reflect.TypeOf((*MockUserService)(nil).UpdateCompany), ctx, cid, companyData, claims)
+}
+
+// UpdateJob mocks base method.
+func (m *MockUserService) UpdateJob(ctx context.Context, jid uint64, jobData models.NewJobRequest, claims auth.Claims) (models.Job, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", ctx, jid, jobData, claims)
+	ret0, _ := ret[0].(models.Job)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockUserServiceMockRecorder) UpdateJob(ctx, jid, jobData, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockUserService)(nil).UpdateJob), ctx, jid, jobData, claims)
+}
+
+// UpdateProfile mocks base method.
+func (m *MockUserService) UpdateProfile(ctx context.Context, pr models.ProfileRequest, claims auth.Claims) (models.CandidateProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, pr, claims)
+	ret0, _ := ret[0].(models.CandidateProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockUserServiceMockRecorder) UpdateProfile(ctx, pr, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockUserService)(nil).UpdateProfile), ctx, pr, claims)
+}
+
+// UploadResume mocks base method.
+func (m *MockUserService) UploadResume(ctx context.Context, up models.ResumeUpload, claims auth.Claims) (models.Resume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadResume", ctx, up, claims)
+	ret0, _ := ret[0].(models.Resume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadResume indicates an expected call of UploadResume.
+func (mr *MockUserServiceMockRecorder) UploadResume(ctx, up, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadResume", reflect.TypeOf((*MockUserService)(nil).UploadResume), ctx, up, claims)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockUserService) VerifyEmail(ctx context.Context, token string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockUserServiceMockRecorder) VerifyEmail(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockUserService)(nil).VerifyEmail), ctx, token)
+}
+
 // ViewAllCompanies mocks base method.
 func (m *MockUserService) ViewAllCompanies(ctx context.Context) ([]models.Company, error) {
 	m.ctrl.T.Helper()
@@ -162,18 +695,48 @@ func (mr *MockUserServiceMockRecorder) ViewAllCompanies(ctx any) *gomock.Call {
 }
 
 // ViewAllJobs mocks base method.
-func (m *MockUserService) ViewAllJobs(ctx context.Context) ([]models.Job, error) {
+func (m *MockUserService) ViewAllJobs(ctx context.Context, q models.JobListQuery, claims auth.Claims) (models.JobPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ViewAllJobs", ctx)
-	ret0, _ := ret[0].([]models.Job)
+	ret := m.ctrl.Call(m, "ViewAllJobs", ctx, q, claims)
+	ret0, _ := ret[0].(models.JobPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ViewAllJobs indicates an expected call of ViewAllJobs.
-func (mr *MockUserServiceMockRecorder) ViewAllJobs(ctx any) *gomock.Call {
+func (mr *MockUserServiceMockRecorder) ViewAllJobs(ctx, q, claims any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewAllJobs", reflect.TypeOf((*MockUserService)(nil).ViewAllJobs), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewAllJobs", reflect.TypeOf((*MockUserService)(nil).ViewAllJobs), ctx, q, claims)
+}
+
+// ViewApplicationHistory mocks base method.
+func (m *MockUserService) ViewApplicationHistory(ctx context.Context, aid uint, claims auth.Claims) ([]models.ApplicationStageChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewApplicationHistory", ctx, aid, claims)
+	ret0, _ := ret[0].([]models.ApplicationStageChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewApplicationHistory indicates an expected call of ViewApplicationHistory.
+func (mr *MockUserServiceMockRecorder) ViewApplicationHistory(ctx, aid, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewApplicationHistory", reflect.TypeOf((*MockUserService)(nil).ViewApplicationHistory), ctx, aid, claims)
+}
+
+// ViewApplicationResumes mocks base method.
+func (m *MockUserService) ViewApplicationResumes(ctx context.Context, aid uint, claims auth.Claims) ([]models.Resume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewApplicationResumes", ctx, aid, claims)
+	ret0, _ := ret[0].([]models.Resume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewApplicationResumes indicates an expected call of ViewApplicationResumes.
+func (mr *MockUserServiceMockRecorder) ViewApplicationResumes(ctx, aid, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewApplicationResumes", reflect.TypeOf((*MockUserService)(nil).ViewApplicationResumes), ctx, aid, claims)
 }
 
 // ViewCompanyDetails mocks base method.
@@ -191,6 +754,36 @@ func (mr *MockUserServiceMockRecorder) ViewCompanyDetails(ctx, id any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompanyDetails", reflect.TypeOf((*MockUserService)(nil).ViewCompanyDetails), ctx, id)
 }
 
+// ViewCompanyMembers mocks base method.
+func (m *MockUserService) ViewCompanyMembers(ctx context.Context, cid uint64, claims auth.Claims) ([]models.CompanyMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewCompanyMembers", ctx, cid, claims)
+	ret0, _ := ret[0].([]models.CompanyMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewCompanyMembers indicates an expected call of ViewCompanyMembers.
+func (mr *MockUserServiceMockRecorder) ViewCompanyMembers(ctx, cid, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewCompanyMembers", reflect.TypeOf((*MockUserService)(nil).ViewCompanyMembers), ctx, cid, claims)
+}
+
+// ViewJobApplications mocks base method.
+func (m *MockUserService) ViewJobApplications(ctx context.Context, jid uint64, stages []string, claims auth.Claims) ([]models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewJobApplications", ctx, jid, stages, claims)
+	ret0, _ := ret[0].([]models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewJobApplications indicates an expected call of ViewJobApplications.
+func (mr *MockUserServiceMockRecorder) ViewJobApplications(ctx, jid, stages, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewJobApplications", reflect.TypeOf((*MockUserService)(nil).ViewJobApplications), ctx, jid, stages, claims)
+}
+
 // ViewJobById mocks base method.
 func (m *MockUserService) ViewJobById(ctx context.Context, jid uint64) ([]models.Job, error) {
 	m.ctrl.T.Helper()
@@ -220,3 +813,78 @@ func (mr *MockUserServiceMockRecorder) ViewJobFromCompany(cid any) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewJobFromCompany", reflect.TypeOf((*MockUserService)(nil).ViewJobFromCompany), cid)
 }
+
+// ViewMyApplications mocks base method.
+func (m *MockUserService) ViewMyApplications(ctx context.Context, claims auth.Claims) ([]models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewMyApplications", ctx, claims)
+	ret0, _ := ret[0].([]models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewMyApplications indicates an expected call of ViewMyApplications.
+func (mr *MockUserServiceMockRecorder) ViewMyApplications(ctx, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewMyApplications", reflect.TypeOf((*MockUserService)(nil).ViewMyApplications), ctx, claims)
+}
+
+// ViewMyResumes mocks base method.
+func (m *MockUserService) ViewMyResumes(ctx context.Context, claims auth.Claims) ([]models.Resume, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewMyResumes", ctx, claims)
+	ret0, _ := ret[0].([]models.Resume)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewMyResumes indicates an expected call of ViewMyResumes.
+func (mr *MockUserServiceMockRecorder) ViewMyResumes(ctx, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewMyResumes", reflect.TypeOf((*MockUserService)(nil).ViewMyResumes), ctx, claims)
+}
+
+// ViewProfile mocks base method.
+func (m *MockUserService) ViewProfile(ctx context.Context, claims auth.Claims) (models.CandidateProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewProfile", ctx, claims)
+	ret0, _ := ret[0].(models.CandidateProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewProfile indicates an expected call of ViewProfile.
+func (mr *MockUserServiceMockRecorder) ViewProfile(ctx, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewProfile", reflect.TypeOf((*MockUserService)(nil).ViewProfile), ctx, claims)
+}
+
+// ViewScreening mocks base method.
+func (m *MockUserService) ViewScreening(ctx context.Context, id string, q models.ScreeningQuery, claims auth.Claims) (models.ScreeningPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ViewScreening", ctx, id, q, claims)
+	ret0, _ := ret[0].(models.ScreeningPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ViewScreening indicates an expected call of ViewScreening.
+func (mr *MockUserServiceMockRecorder) ViewScreening(ctx, id, q, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ViewScreening", reflect.TypeOf((*MockUserService)(nil).ViewScreening), ctx, id, q, claims)
+}
+
+// WithdrawApplication mocks base method.
+func (m *MockUserService) WithdrawApplication(ctx context.Context, aid uint, claims auth.Claims) (models.Application, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawApplication", ctx, aid, claims)
+	ret0, _ := ret[0].(models.Application)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithdrawApplication indicates an expected call of WithdrawApplication.
+func (mr *MockUserServiceMockRecorder) WithdrawApplication(ctx, aid, claims any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawApplication", reflect.TypeOf((*MockUserService)(nil).WithdrawApplication), ctx, aid, claims)
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Errors returned for master-data changes
+var (
+	ErrTaxonomyNotFound = errors.New("item not found")
+	ErrTaxonomyExists   = errors.New("an item with this name already exists")
+	ErrTaxonomyInUse    = errors.New("item is used by jobs or profiles, merge it into another item instead")
+	ErrInvalidMerge     = errors.New("an item cannot be merged into itself")
+)
+
+func (s *Service) ListTaxonomy(ctx context.Context, kind models.TaxonomyKind) ([]models.TaxonomyItem, error) {
+	return s.UserRepo.ListTaxonomy(ctx, kind)
+}
+
+func (s *Service) AddTaxonomy(ctx context.Context, kind models.TaxonomyKind, name string) (models.TaxonomyItem, error) {
+	item, err := s.UserRepo.CreateTaxonomy(ctx, kind, strings.TrimSpace(name))
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return models.TaxonomyItem{}, ErrTaxonomyExists
+	}
+	if err != nil {
+		return models.TaxonomyItem{}, err
+	}
+	return item, nil
+}
+
+func (s *Service) RenameTaxonomy(ctx context.Context, kind models.TaxonomyKind, id uint, name string) (models.TaxonomyItem, error) {
+	item, err := s.UserRepo.RenameTaxonomy(ctx, kind, id, strings.TrimSpace(name))
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return models.TaxonomyItem{}, ErrTaxonomyExists
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.TaxonomyItem{}, ErrTaxonomyNotFound
+	}
+	if err != nil {
+		return models.TaxonomyItem{}, err
+	}
+	return item, nil
+}
+
+// DeleteTaxonomy deletes an item no job or candidate profile uses any more
+func (s *Service) DeleteTaxonomy(ctx context.Context, kind models.TaxonomyKind, id uint) error {
+	used, err := s.UserRepo.CountTaxonomyUsage(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+	if used > 0 {
+		return ErrTaxonomyInUse
+	}
+	err = s.UserRepo.DeleteTaxonomy(ctx, kind, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrTaxonomyNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// MergeTaxonomy folds duplicate items into the target item, jobs tagged with a
+// duplicate are tagged with the target instead
+func (s *Service) MergeTaxonomy(ctx context.Context, kind models.TaxonomyKind, targetId uint, sourceIds []uint) (models.TaxonomyItem, error) {
+	seen := map[uint]bool{}
+	var sources []uint
+	for _, id := range sourceIds {
+		if id == targetId {
+			return models.TaxonomyItem{}, ErrInvalidMerge
+		}
+		if !seen[id] {
+			seen[id] = true
+			sources = append(sources, id)
+		}
+	}
+	jobIds, err := s.UserRepo.MergeTaxonomy(ctx, kind, targetId, sources)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.TaxonomyItem{}, ErrTaxonomyNotFound
+	}
+	if err != nil {
+		return models.TaxonomyItem{}, err
+	}
+	for _, jid := range jobIds {
+		s.invalidateJob(ctx, jid)
+	}
+	// Profiles tagged with a duplicate are tagged with the target now
+	s.invalidateRecommendations(ctx)
+	return s.UserRepo.GetTaxonomy(ctx, kind, targetId)
+}
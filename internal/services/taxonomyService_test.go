@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/caching"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"reflect"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestService_AddTaxonomy(t *testing.T) {
+	skills := models.TaxonomyKinds[1]
+	tests := []struct {
+		name       string
+		setupMocks func(mr *repository.MockUserRepo)
+		want       models.TaxonomyItem
+		wantErr    error
+	}{
+		{name: "name already taken",
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().CreateTaxonomy(gomock.Any(), skills, "Go").Return(models.TaxonomyItem{}, gorm.ErrDuplicatedKey)
+			},
+			wantErr: ErrTaxonomyExists,
+		},
+		{name: "name is trimmed before saving",
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().CreateTaxonomy(gomock.Any(), skills, "Go").Return(models.TaxonomyItem{ID: 4, Name: "Go"}, nil)
+			},
+			want: models.TaxonomyItem{ID: 4, Name: "Go"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			tt.setupMocks(MockUserRepo)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
+			got, err := s.AddTaxonomy(context.Background(), skills, "  Go ")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Service.AddTaxonomy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Service.AddTaxonomy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestService_DeleteTaxonomy(t *testing.T) {
+	locations := models.TaxonomyKinds[0]
+	tests := []struct {
+		name       string
+		setupMocks func(mr *repository.MockUserRepo, mc *caching.MockCache)
+		wantErr    error
+	}{
+		{name: "item used by jobs or profiles",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().CountTaxonomyUsage(gomock.Any(), locations, uint(2)).Return(int64(3), nil)
+			},
+			wantErr: ErrTaxonomyInUse,
+		},
+		{name: "item does not exist",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().CountTaxonomyUsage(gomock.Any(), locations, uint(2)).Return(int64(0), nil)
+				mr.EXPECT().DeleteTaxonomy(gomock.Any(), locations, uint(2)).Return(gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrTaxonomyNotFound,
+		},
+		{name: "unused item deleted",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().CountTaxonomyUsage(gomock.Any(), locations, uint(2)).Return(int64(0), nil)
+				mr.EXPECT().DeleteTaxonomy(gomock.Any(), locations, uint(2)).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockCache := caching.NewMockCache(mc)
+			tt.setupMocks(MockUserRepo, MockCache)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+			err := s.DeleteTaxonomy(context.Background(), locations, 2)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Service.DeleteTaxonomy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_MergeTaxonomy(t *testing.T) {
+	skills := models.TaxonomyKinds[1]
+	tests := []struct {
+		name       string
+		sources    []uint
+		setupMocks func(mr *repository.MockUserRepo, mc *caching.MockCache)
+		wantErr    error
+	}{
+		{name: "item merged into itself",
+			sources:    []uint{3, 1},
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {},
+			wantErr:    ErrInvalidMerge,
+		},
+		{name: "unknown duplicate",
+			sources: []uint{3},
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().MergeTaxonomy(gomock.Any(), skills, uint(1), []uint{3}).Return(nil, gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrTaxonomyNotFound,
+		},
+		{name: "duplicates merged and affected jobs dropped from the cache",
+			sources: []uint{3, 4, 3},
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mr.EXPECT().MergeTaxonomy(gomock.Any(), skills, uint(1), []uint{3, 4}).Return([]uint{10}, nil)
+				mc.EXPECT().DeleteCache(gomock.Any(), uint(10)).Return(nil)
+				mc.EXPECT().InvalidateRecommendations(gomock.Any()).Return(nil)
+				mr.EXPECT().GetTaxonomy(gomock.Any(), skills, uint(1)).Return(models.TaxonomyItem{ID: 1, Name: "Go"}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockCache := caching.NewMockCache(mc)
+			tt.setupMocks(MockUserRepo, MockCache)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+			_, err := s.MergeTaxonomy(context.Background(), skills, 1, tt.sources)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Service.MergeTaxonomy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/models"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Lifetimes of the tokens handed out on login
+const (
+	accessTokenTTL  = time.Hour
+	refreshTokenTTL = 7 * 24 * time.Hour
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrForeignRefreshToken = errors.New("refresh token belongs to another user")
+)
+
+// newClaims builds the access token claims for a user, every token gets its
+// own id (jti) so it can be revoked on its own
+func newClaims(u models.User) auth.Claims {
+	// Accounts created before roles existed are treated as candidates.
+	role := u.Role
+	if role == "" {
+		role = models.RoleCandidate
+	}
+	return auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    "service project",
+			Subject:   strconv.FormatUint(uint64(u.ID), 10),
+			Audience:  jwt.ClaimStrings{auth.AccessAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(accessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+		Roles: []string{role},
+	}
+}
+
+// CreateRefreshToken stores a new opaque refresh token for the user in the claims
+func (s *Service) CreateRefreshToken(ctx context.Context, claims auth.Claims) (string, error) {
+	uid, err := claims.UserId()
+	if err != nil {
+		return "", err
+	}
+	b := make([]byte, 32)
+	_, err = rand.Read(b)
+	if err != nil {
+		return "", fmt.Errorf("generating refresh token %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	err = s.rdb.AddRefreshToken(ctx, token, uid, refreshTokenTTL)
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// RefreshToken exchanges a refresh token for new access claims and a new
+// refresh token, the old refresh token stops working
+func (s *Service) RefreshToken(ctx context.Context, refreshToken string) (auth.Claims, string, error) {
+	uid, err := s.rdb.TakeRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return auth.Claims{}, "", ErrInvalidRefreshToken
+	}
+	u, err := s.UserRepo.GetUserById(ctx, uid)
+	if err != nil {
+		return auth.Claims{}, "", ErrInvalidRefreshToken
+	}
+	claims := newClaims(u)
+	newToken, err := s.CreateRefreshToken(ctx, claims)
+	if err != nil {
+		return auth.Claims{}, "", err
+	}
+	return claims, newToken, nil
+}
+
+// Logout revokes the access token in the claims and drops the refresh token
+// if one is given. A refresh token handed to someone else is refused.
+func (s *Service) Logout(ctx context.Context, claims auth.Claims, refreshToken string) error {
+	dropRefresh := false
+	if refreshToken != "" {
+		uid, err := claims.UserId()
+		if err != nil {
+			return err
+		}
+		owner, err := s.rdb.RefreshTokenOwner(ctx, refreshToken)
+		switch {
+		case errors.Is(err, redis.Nil):
+			// Already used or expired, nothing to drop
+		case err != nil:
+			return err
+		case owner != uid:
+			return ErrForeignRefreshToken
+		default:
+			dropRefresh = true
+		}
+	}
+	if claims.ID != "" && claims.ExpiresAt != nil {
+		err := s.rdb.RevokeToken(ctx, claims.ID, time.Until(claims.ExpiresAt.Time))
+		if err != nil {
+			return err
+		}
+	}
+	if dropRefresh {
+		err := s.rdb.DeleteRefreshToken(ctx, refreshToken)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/caching"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
+)
+
+func TestService_RefreshToken(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupMocks func(mr *repository.MockUserRepo, mc *caching.MockCache)
+		wantErr    bool
+	}{
+		{name: "refresh token already used or expired",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().TakeRefreshToken(gomock.Any(), "old").Return(uint(0), errors.New("redis: nil"))
+			},
+			wantErr: true,
+		},
+		{name: "user no longer exists",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().TakeRefreshToken(gomock.Any(), "old").Return(uint(7), nil)
+				mr.EXPECT().GetUserById(gomock.Any(), uint(7)).Return(models.User{}, errors.New("user not found"))
+			},
+			wantErr: true,
+		},
+		{name: "refresh token rotated",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().TakeRefreshToken(gomock.Any(), "old").Return(uint(7), nil)
+				mr.EXPECT().GetUserById(gomock.Any(), uint(7)).Return(models.User{Model: gorm.Model{ID: 7}, Role: models.RoleRecruiter}, nil)
+				mc.EXPECT().AddRefreshToken(gomock.Any(), gomock.Any(), uint(7), refreshTokenTTL).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mr := repository.NewMockUserRepo(ctrl)
+			mc := caching.NewMockCache(ctrl)
+			tt.setupMocks(mr, mc)
+			s, _ := NewService(mr, &auth.Auth{}, mc, nil)
+			claims, rt, err := s.RefreshToken(context.Background(), "old")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Service.RefreshToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if rt == "" || rt == "old" {
+				t.Errorf("Service.RefreshToken() refresh token not rotated: %q", rt)
+			}
+			if claims.Subject != "7" || !claims.HasRole(models.RoleRecruiter) || claims.ID == "" {
+				t.Errorf("Service.RefreshToken() claims = %v", claims)
+			}
+		})
+	}
+}
+
+func TestService_Logout(t *testing.T) {
+	claims := auth.Claims{RegisteredClaims: jwt.RegisteredClaims{ID: "jti", Subject: "7", ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}}
+	tests := []struct {
+		name    string
+		setup   func(mc *caching.MockCache)
+		wantErr error
+	}{
+		{name: "own refresh token dropped",
+			setup: func(mc *caching.MockCache) {
+				mc.EXPECT().RefreshTokenOwner(gomock.Any(), "rt").Return(uint(7), nil)
+				mc.EXPECT().RevokeToken(gomock.Any(), "jti", gomock.Any()).Return(nil)
+				mc.EXPECT().DeleteRefreshToken(gomock.Any(), "rt").Return(nil)
+			},
+		},
+		{name: "refresh token of another user refused",
+			setup: func(mc *caching.MockCache) {
+				mc.EXPECT().RefreshTokenOwner(gomock.Any(), "rt").Return(uint(8), nil)
+			},
+			wantErr: ErrForeignRefreshToken,
+		},
+		{name: "expired refresh token only revokes the access token",
+			setup: func(mc *caching.MockCache) {
+				mc.EXPECT().RefreshTokenOwner(gomock.Any(), "rt").Return(uint(0), redis.Nil)
+				mc.EXPECT().RevokeToken(gomock.Any(), "jti", gomock.Any()).Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mr := repository.NewMockUserRepo(ctrl)
+			mc := caching.NewMockCache(ctrl)
+			tt.setup(mc)
+			s, _ := NewService(mr, &auth.Auth{}, mc, nil)
+			err := s.Logout(context.Background(), claims, "rt")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Service.Logout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
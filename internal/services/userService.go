@@ -4,17 +4,21 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/mailer"
 	"job-portal-api/internal/models"
 	"job-portal-api/internal/pkg"
 
-	"math/rand"
-	"net/smtp"
+	"crypto/rand"
+	"crypto/subtle"
+	"math/big"
 
-	"strconv"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog/log"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 // var otp string
@@ -23,132 +27,204 @@ func (s *Service) Signup(ctx context.Context, nu models.NewUser) (models.User, e
 	if err != nil {
 		return models.User{}, err
 	}
+	exists, err := s.UserRepo.EmailExists(ctx, nu.Email)
+	if err != nil {
+		return models.User{}, err
+	}
+	if exists {
+		return models.User{}, ErrEmailTaken
+	}
 	userDetails := models.User{
 		Name:         nu.Name,
 		Email:        nu.Email,
 		PasswordHash: hashedPass,
 		Dob:          nu.Dob,
+		// Everyone signs up as a candidate, recruiters are made by an admin
+		Role: models.RoleCandidate,
 	}
 	fmt.Printf("chck:: %#v", s)
 	userDetails, err = s.UserRepo.CreateUser(ctx, userDetails)
 	if err != nil {
 		return models.User{}, err
 	}
+	// The account exists either way, a failed mail can be sent again
+	// through the resend endpoint
+	err = s.sendVerificationEmail(ctx, userDetails)
+	if err != nil {
+		log.Error().Err(err).Str("Email", userDetails.Email).Msg("sending verification mail")
+	}
 	return userDetails, nil
 }
 
-func (s *Service) Login(ctx context.Context, email, password string) (jwt.RegisteredClaims, error) {
+func (s *Service) Login(ctx context.Context, email, password string) (auth.Claims, error) {
 
 	// We attempt to find the User record where the email
 	// matches the provided email.
 	var u models.User
 	u, err := s.UserRepo.CheckEmail(ctx, email)
 	if err != nil {
-		return jwt.RegisteredClaims{}, err
+		return auth.Claims{}, err
 	}
 	// We check if the provided password matches the hashed password in the database.
 	err = bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password))
 	if err != nil {
-		return jwt.RegisteredClaims{}, err
+		return auth.Claims{}, err
+	}
+	if !u.Verified {
+		return auth.Claims{}, ErrEmailNotVerified
 	}
 
 	// Successful authentication! Generate JWT claims.
-	c := jwt.RegisteredClaims{
-		Issuer:    "service project",
-		Subject:   strconv.FormatUint(uint64(u.ID), 10),
-		Audience:  jwt.ClaimStrings{"users"},
-		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
-		IssuedAt:  jwt.NewNumericDate(time.Now()),
-	}
+	return newClaims(u), nil
+}
+
+var ErrUserNotFound = errors.New("user not found")
 
-	// And return those claims.
-	return c, nil
+// SetUserRole makes a user a candidate or a recruiter. Tokens already handed
+// out keep the old role until they are refreshed.
+func (s *Service) SetUserRole(ctx context.Context, uid uint, role string) (models.User, error) {
+	err := s.UserRepo.SetUserRole(ctx, uid, role)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.User{}, ErrUserNotFound
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+	return s.UserRepo.GetUserById(ctx, uid)
 }
 
+// Password reset limits
+const (
+	otpLength      = 6
+	otpTTL         = 5 * time.Minute
+	otpCooldown    = time.Minute
+	maxOTPAttempts = 5
+	// Wrong codes are counted per email over this window, whatever the
+	// number of codes sent in it
+	otpAttemptWindow = time.Hour
+)
+
+// OTPSentMessage is returned whether or not the email is known so the reset
+// endpoint cannot be used to find out who has an account
+const OTPSentMessage = "if the account exists, a reset code has been sent to the email"
+
+var (
+	ErrOTPCooldown        = errors.New("a reset code was requested recently, try again later")
+	ErrInvalidOTP         = errors.New("invalid otp")
+	ErrTooManyOTPAttempts = errors.New("too many wrong codes, request a new one")
+	ErrPasswordMismatch   = errors.New("password and confirm password mismatched")
+)
+
 func (s *Service) OTPGeneration(ctx context.Context, data models.ForgotPassword) (string, error) {
-	check, err := s.UserRepo.CheckEmail(ctx, data.Email)
+	ok, err := s.rdb.StartOTPCooldown(ctx, data.Email, otpCooldown)
 	if err != nil {
-		return "", errors.New("email not found in db")
+		return "", err
+	}
+	if !ok {
+		return "", ErrOTPCooldown
 	}
 
-	// Sender's email address and password
-	from := "niki16809@gmail.com"
-	password := "xagg yzsu kkvd jbrr"
-
-	// Recipient's email address
-	to := check
+	check, err := s.UserRepo.CheckEmail(ctx, data.Email)
+	if err != nil || check.Dob != data.Dob {
+		log.Info().Str("Email", data.Email).Msg("password reset requested for unknown email or wrong dob")
+		return OTPSentMessage, nil
+	}
 
-	// SMTP server details
-	smtpServer := "smtp.gmail.com"
-	smtpPort := 587
-	otp := generateOTP(4)
-	// Message content
-	message := fmt.Sprintf("subject: reset password\n\notp to reset password is : %v", otp)
+	otp, err := generateOTP(otpLength)
+	if err != nil {
+		return "", err
+	}
 	//adding otp to cache
-	err = s.rdb.AddEmailToCache(ctx, check.Email, otp)
+	err = s.rdb.AddEmailToCache(ctx, check.Email, otp, otpTTL)
 	if err != nil {
 		return "", err
 	}
-	// Authentication information
-	auth := smtp.PlainAuth("", from, password, smtpServer)
 
-	// SMTP connection
-	smtpAddr := fmt.Sprintf("%s:%d", smtpServer, smtpPort)
-	err = smtp.SendMail(smtpAddr, auth, from, []string{to.Email}, []byte(message))
+	msg, err := mailer.Render("password_reset", check.Email, map[string]any{
+		"Name":     check.Name,
+		"OTP":      otp,
+		"ValidFor": "5 minutes",
+	})
+	if err != nil {
+		return "", err
+	}
+	err = s.mailer.Send(ctx, msg)
 	if err != nil {
-		//fmt.Println("Error sending email:", err)
+		log.Error().Err(err).Str("Email", check.Email).Msg("sending password reset mail")
 		return "", errors.New("error sending email")
 	}
-
-	fmt.Println("Email sent successfully!")
-	return otp, nil
+	return OTPSentMessage, nil
 
 }
 
-func generateOTP(length int) string {
-	// Seed the random number generator with the current timestamp
-	rand.Seed(time.Now().UnixNano())
-
-	// Define the characters allowed in the OTP
-	otpChars := "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
-
-	// Generate the OTP
+// generateOTP returns a numeric code read from crypto/rand
+func generateOTP(length int) (string, error) {
 	otp := make([]byte, length)
 	for i := range otp {
-		otp[i] = otpChars[rand.Intn(len(otpChars))]
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", fmt.Errorf("generating otp %w", err)
+		}
+		otp[i] = byte('0' + n.Int64())
 	}
-
-	return string(otp)
+	return string(otp), nil
 }
 
 func (s *Service) ChangePassword(ctx context.Context, cj models.OtpPassword) (string, error) {
+	if cj.Password != cj.ConfirmPassword {
+		return "", ErrPasswordMismatch
+	}
+
+	attempts, err := s.rdb.GetOTPAttempts(ctx, cj.Email)
+	if err != nil {
+		return "", err
+	}
+	if attempts >= maxOTPAttempts {
+		return "", ErrTooManyOTPAttempts
+	}
+
 	v, err := s.rdb.GetEmailFromCache(ctx, cj.Email)
+	if errors.Is(err, redis.Nil) {
+		return "", ErrInvalidOTP
+	}
 	if err != nil {
-		fmt.Println("there is an error in GetEmailFromCache ")
-		//return "", err
+		return "", err
 	}
 
-	if v == cj.Otp {
-		if cj.Password == cj.ConfirmPassword {
-			newuserotp, err := s.UserRepo.CheckEmail(ctx, cj.Email)
-			if err != nil {
-				return "", errors.New("email not matching")
-			}
-			hashedPass, err := pkg.PasswordHash(cj.ConfirmPassword)
-			if err != nil {
-				return "", errors.New("error in pwd hash")
-			}
-			newuserotp.PasswordHash = hashedPass
-			err = s.UserRepo.UpdatePwdInDb(newuserotp)
+	if subtle.ConstantTimeCompare([]byte(v), []byte(cj.Otp)) != 1 {
+		attempts, err = s.rdb.IncrOTPAttempts(ctx, cj.Email, otpAttemptWindow)
+		if err != nil {
+			return "", err
+		}
+		if attempts >= maxOTPAttempts {
+			// Burn the code, the user has to request a new one
+			err = s.rdb.DeleteOTP(ctx, cj.Email)
 			if err != nil {
-				return "", errors.New("password not matching")
+				return "", err
 			}
-
-		} else {
-			return "", errors.New("old password and confirm password mismatched")
+			return "", ErrTooManyOTPAttempts
 		}
-	} else {
-		return "", errors.New("invalid otp")
+		return "", ErrInvalidOTP
+	}
+
+	newuserotp, err := s.UserRepo.CheckEmail(ctx, cj.Email)
+	if err != nil {
+		return "", errors.New("email not matching")
+	}
+	hashedPass, err := pkg.PasswordHash(cj.ConfirmPassword)
+	if err != nil {
+		return "", errors.New("error in pwd hash")
+	}
+	newuserotp.PasswordHash = hashedPass
+	err = s.UserRepo.UpdatePwdInDb(newuserotp)
+	if err != nil {
+		return "", errors.New("password not updated")
+	}
+
+	// The code is single use
+	err = s.rdb.ResetOTP(ctx, cj.Email)
+	if err != nil {
+		log.Error().Err(err).Str("Email", cj.Email).Msg("removing used otp")
 	}
 	return "PASSWORD CHANGED SUCCESSFULLY", nil
 }
@@ -5,14 +5,21 @@ import (
 	"errors"
 	"job-portal-api/internal/auth"
 	"job-portal-api/internal/caching"
+	"job-portal-api/internal/mailer"
 	"job-portal-api/internal/models"
 	"job-portal-api/internal/repository"
+	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/redis/go-redis/v9"
 	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 func TestService_UserSignup(t *testing.T) {
@@ -26,8 +33,19 @@ func TestService_UserSignup(t *testing.T) {
 		args             args
 		want             models.User
 		wantErr          bool
+		emailTaken       bool
 		mockRepoResponse func() (models.User, error)
 	}{
+		{name: "email already registered",
+			args: args{nu: models.NewUser{
+				Name:     "Nikitha",
+				Email:    "niki123@gmail.com",
+				Password: "1234"},
+				ctx: context.Background()},
+			want:       models.User{},
+			wantErr:    true,
+			emailTaken: true,
+		},
 		{name: "error in converting password to hashpassword during signup",
 			args: args{nu: models.NewUser{
 				Name:     "Nikitha",
@@ -70,10 +88,18 @@ func TestService_UserSignup(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mc := gomock.NewController(t)
 			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockUserRepo.EXPECT().EmailExists(gomock.Any(), tt.args.nu.Email).Return(tt.emailTaken, nil).AnyTimes()
 			if tt.mockRepoResponse != nil {
-				MockUserRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
+				MockUserRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Do(func(_ context.Context, u models.User) {
+					if u.Role != models.RoleCandidate {
+						t.Errorf("Service.UserSignup() role = %q, want %q", u.Role, models.RoleCandidate)
+					}
+				}).Return(tt.mockRepoResponse()).AnyTimes()
 			}
-			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{})
+			MockAuth := auth.NewMockAuthentication(mc)
+			MockAuth.EXPECT().GenerateToken(gomock.Any()).Return("verification-token", nil).AnyTimes()
+			outbox, _ := mailer.NewOutbox("", "no-reply@jobportal.local")
+			s, _ := NewService(MockUserRepo, MockAuth, &caching.Redis{}, outbox)
 			got, err := s.Signup(tt.args.ctx, tt.args.nu)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.UserSignup() error = %v, wantErr %v", err, tt.wantErr)
@@ -86,6 +112,45 @@ func TestService_UserSignup(t *testing.T) {
 	}
 }
 
+func TestService_SetUserRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		setup   func(m *repository.MockUserRepo)
+		want    models.User
+		wantErr error
+	}{
+		{name: "no such user",
+			setup: func(m *repository.MockUserRepo) {
+				m.EXPECT().SetUserRole(gomock.Any(), uint(4), models.RoleRecruiter).Return(gorm.ErrRecordNotFound)
+			},
+			wantErr: ErrUserNotFound,
+		},
+		{name: "user made a recruiter",
+			setup: func(m *repository.MockUserRepo) {
+				m.EXPECT().SetUserRole(gomock.Any(), uint(4), models.RoleRecruiter).Return(nil)
+				m.EXPECT().GetUserById(gomock.Any(), uint(4)).Return(models.User{Name: "Asha", Role: models.RoleRecruiter}, nil)
+			},
+			want: models.User{Name: "Asha", Role: models.RoleRecruiter},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			tt.setup(MockUserRepo)
+			s := &Service{UserRepo: MockUserRepo}
+			got, err := s.SetUserRole(context.Background(), 4, models.RoleRecruiter)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Service.SetUserRole() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Service.SetUserRole() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestService_Login(t *testing.T) {
 	type args struct {
 		ctx      context.Context
@@ -96,27 +161,38 @@ func TestService_Login(t *testing.T) {
 		name             string
 		s                *Service
 		args             args
-		want             jwt.RegisteredClaims
+		want             auth.Claims
 		wantErr          bool
 		mockRepoResponse func() (models.User, error)
 	}{
 		{name: "failure case for login",
 			args: args{email: "niki123@gmail.com",
 				password: ""},
-			want:    jwt.RegisteredClaims{},
+			want:    auth.Claims{},
 			wantErr: true,
 			mockRepoResponse: func() (models.User, error) {
 				return models.User{Email: "niki1232gmail.com", PasswordHash: "$2a$10$vtON7w6i6G.OZT3zKpR00elHrB7P8e3IknFgOfhvfXXHFIk6ytDQC"}, nil
 			},
 		},
-		{name: "success case for login",
+		{name: "login refused until the email is verified",
 			args:    args{email: "niki1232gmail.com", password: "abcdefg"},
-			want:    jwt.RegisteredClaims{Issuer: "service project", Subject: "0", Audience: jwt.ClaimStrings{"users"}, ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)), IssuedAt: jwt.NewNumericDate(time.Now())},
-			wantErr: false,
+			want:    auth.Claims{},
+			wantErr: true,
 			mockRepoResponse: func() (models.User, error) {
 				return models.User{Email: "niki1232gmail.com", PasswordHash: "$2a$10$vtON7w6i6G.OZT3zKpR00elHrB7P8e3IknFgOfhvfXXHFIk6ytDQC"}, nil
 			},
 		},
+		{name: "success case for login",
+			args: args{email: "niki1232gmail.com", password: "abcdefg"},
+			want: auth.Claims{
+				RegisteredClaims: jwt.RegisteredClaims{Issuer: "service project", Subject: "0", Audience: jwt.ClaimStrings{"users"}, ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)), IssuedAt: jwt.NewNumericDate(time.Now())},
+				Roles:            []string{models.RoleCandidate},
+			},
+			wantErr: false,
+			mockRepoResponse: func() (models.User, error) {
+				return models.User{Email: "niki1232gmail.com", PasswordHash: "$2a$10$vtON7w6i6G.OZT3zKpR00elHrB7P8e3IknFgOfhvfXXHFIk6ytDQC", Verified: true}, nil
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -126,15 +202,178 @@ func TestService_Login(t *testing.T) {
 			if tt.mockRepoResponse != nil {
 				MockUserRepo.EXPECT().CheckEmail(gomock.Any(), gomock.Any()).Return(tt.mockRepoResponse()).AnyTimes()
 			}
-			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{})
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, &caching.Redis{}, nil)
 			got, err := s.Login(tt.args.ctx, tt.args.email, tt.args.password)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Service.Login() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			// Every token gets a random id, only check that it is there
+			if !tt.wantErr && got.ID == "" {
+				t.Errorf("Service.Login() token id missing")
+			}
+			got.ID = ""
+			// Timestamps come from time.Now, allow the clock to tick between
+			// building the expectation and logging in
+			if got.IssuedAt != nil && tt.want.IssuedAt != nil && got.IssuedAt.Sub(tt.want.IssuedAt.Time) < 5*time.Second {
+				got.IssuedAt, got.ExpiresAt = tt.want.IssuedAt, tt.want.ExpiresAt
+			}
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Service.Login() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestService_OTPGeneration(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupMocks func(mr *repository.MockUserRepo, mc *caching.MockCache)
+		want       string
+		wantErr    error
+		wantMails  int
+	}{
+		{name: "code requested again within the cooldown",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().StartOTPCooldown(gomock.Any(), "niki@gmail.com", otpCooldown).Return(false, nil)
+			},
+			wantErr: ErrOTPCooldown,
+		},
+		{name: "unknown email gets the same answer and no mail",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().StartOTPCooldown(gomock.Any(), "niki@gmail.com", otpCooldown).Return(true, nil)
+				mr.EXPECT().CheckEmail(gomock.Any(), "niki@gmail.com").Return(models.User{}, errors.New("email not found"))
+			},
+			want: OTPSentMessage,
+		},
+		{name: "wrong date of birth gets the same answer and no mail",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().StartOTPCooldown(gomock.Any(), "niki@gmail.com", otpCooldown).Return(true, nil)
+				mr.EXPECT().CheckEmail(gomock.Any(), "niki@gmail.com").Return(models.User{Name: "Niki", Email: "niki@gmail.com", Dob: "2000-01-01"}, nil)
+			},
+			want: OTPSentMessage,
+		},
+		{name: "code mailed and not returned",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().StartOTPCooldown(gomock.Any(), "niki@gmail.com", otpCooldown).Return(true, nil)
+				mr.EXPECT().CheckEmail(gomock.Any(), "niki@gmail.com").Return(models.User{Name: "Niki", Email: "niki@gmail.com", Dob: "1999-01-01"}, nil)
+				mc.EXPECT().AddEmailToCache(gomock.Any(), "niki@gmail.com", gomock.Any(), otpTTL).Return(nil)
+			},
+			want:      OTPSentMessage,
+			wantMails: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			outbox, err := mailer.NewOutbox(dir, "no-reply@jobportal.local")
+			if err != nil {
+				t.Fatalf("NewOutbox() error = %v", err)
+			}
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockCache := caching.NewMockCache(mc)
+			tt.setupMocks(MockUserRepo, MockCache)
+
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, outbox)
+			got, err := s.OTPGeneration(context.Background(), models.ForgotPassword{Email: "niki@gmail.com", Dob: "1999-01-01"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Service.OTPGeneration() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Service.OTPGeneration() = %q, want %q", got, tt.want)
+			}
+			files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+			if len(files) != tt.wantMails {
+				t.Fatalf("outbox files = %v, want %d", files, tt.wantMails)
+			}
+			if tt.wantMails == 1 {
+				data, _ := os.ReadFile(files[0])
+				if !regexp.MustCompile(`\n    [0-9]{6}\r\n`).Match(data) || !strings.Contains(string(data), "To: niki@gmail.com") {
+					t.Errorf("reset mail = %q", data)
+				}
+			}
+		})
+	}
+}
+
+func TestService_ChangePassword(t *testing.T) {
+	request := models.OtpPassword{Email: "niki@gmail.com", Otp: "123456", Password: "secret", ConfirmPassword: "secret"}
+	tests := []struct {
+		name       string
+		req        models.OtpPassword
+		setupMocks func(mr *repository.MockUserRepo, mc *caching.MockCache)
+		wantErr    error
+	}{
+		{name: "passwords do not match",
+			req:        models.OtpPassword{Email: "niki@gmail.com", Otp: "123456", Password: "secret", ConfirmPassword: "other"},
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {},
+			wantErr:    ErrPasswordMismatch,
+		},
+		{name: "attempts already used up",
+			req: request,
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().GetOTPAttempts(gomock.Any(), "niki@gmail.com").Return(int64(maxOTPAttempts), nil)
+			},
+			wantErr: ErrTooManyOTPAttempts,
+		},
+		{name: "no code was requested",
+			req: request,
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().GetOTPAttempts(gomock.Any(), "niki@gmail.com").Return(int64(0), nil)
+				mc.EXPECT().GetEmailFromCache(gomock.Any(), "niki@gmail.com").Return("", redis.Nil)
+			},
+			wantErr: ErrInvalidOTP,
+		},
+		{name: "cache failure is not ignored",
+			req: request,
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().GetOTPAttempts(gomock.Any(), "niki@gmail.com").Return(int64(0), nil)
+				mc.EXPECT().GetEmailFromCache(gomock.Any(), "niki@gmail.com").Return("", errors.New("connection refused"))
+			},
+			wantErr: errors.New("connection refused"),
+		},
+		{name: "wrong code counts an attempt",
+			req: request,
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().GetOTPAttempts(gomock.Any(), "niki@gmail.com").Return(int64(0), nil)
+				mc.EXPECT().GetEmailFromCache(gomock.Any(), "niki@gmail.com").Return("654321", nil)
+				mc.EXPECT().IncrOTPAttempts(gomock.Any(), "niki@gmail.com", otpAttemptWindow).Return(int64(1), nil)
+			},
+			wantErr: ErrInvalidOTP,
+		},
+		{name: "last wrong code burns the otp",
+			req: request,
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().GetOTPAttempts(gomock.Any(), "niki@gmail.com").Return(int64(maxOTPAttempts-1), nil)
+				mc.EXPECT().GetEmailFromCache(gomock.Any(), "niki@gmail.com").Return("654321", nil)
+				mc.EXPECT().IncrOTPAttempts(gomock.Any(), "niki@gmail.com", otpAttemptWindow).Return(int64(maxOTPAttempts), nil)
+				mc.EXPECT().DeleteOTP(gomock.Any(), "niki@gmail.com").Return(nil)
+			},
+			wantErr: ErrTooManyOTPAttempts,
+		},
+		{name: "password changed and otp removed",
+			req: request,
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().GetOTPAttempts(gomock.Any(), "niki@gmail.com").Return(int64(0), nil)
+				mc.EXPECT().GetEmailFromCache(gomock.Any(), "niki@gmail.com").Return("123456", nil)
+				mr.EXPECT().CheckEmail(gomock.Any(), "niki@gmail.com").Return(models.User{Email: "niki@gmail.com"}, nil)
+				mr.EXPECT().UpdatePwdInDb(gomock.Any()).Return(nil)
+				mc.EXPECT().ResetOTP(gomock.Any(), "niki@gmail.com").Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockCache := caching.NewMockCache(mc)
+			tt.setupMocks(MockUserRepo, MockCache)
+			s, _ := NewService(MockUserRepo, &auth.Auth{}, MockCache, nil)
+			_, err := s.ChangePassword(context.Background(), tt.req)
+			if tt.wantErr == nil && err != nil || tt.wantErr != nil && (err == nil || err.Error() != tt.wantErr.Error()) {
+				t.Errorf("Service.ChangePassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
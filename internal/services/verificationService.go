@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/mailer"
+	"job-portal-api/internal/models"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// Email verification limits
+const (
+	verificationTokenTTL = 24 * time.Hour
+	verificationCooldown = time.Minute
+)
+
+// VerificationSentMessage is returned whether or not the email is known so
+// the resend endpoint cannot be used to find out who has an account
+const VerificationSentMessage = "if the account exists and is not verified, a verification link has been sent to the email"
+
+var (
+	ErrEmailTaken               = errors.New("an account with this email already exists")
+	ErrEmailNotVerified         = errors.New("email address is not verified")
+	ErrInvalidVerificationToken = errors.New("invalid or expired verification link")
+	ErrVerificationCooldown     = errors.New("a verification link was sent recently, try again later")
+)
+
+// sendVerificationEmail mails the user a link carrying a signed token that
+// is only accepted by the verify endpoint
+func (s *Service) sendVerificationEmail(ctx context.Context, u models.User) error {
+	claims := auth.Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    "service project",
+			Subject:   strconv.FormatUint(uint64(u.ID), 10),
+			Audience:  jwt.ClaimStrings{auth.EmailVerificationAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(verificationTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+	tkn, err := s.auth.GenerateToken(claims)
+	if err != nil {
+		return err
+	}
+	msg, err := mailer.Render("email_verification", u.Email, map[string]any{
+		"Name":     u.Name,
+		"Link":     s.appURL + "/verify-email?token=" + url.QueryEscape(tkn),
+		"ValidFor": "24 hours",
+	})
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, msg)
+}
+
+// VerifyEmail marks the account in a verification token as verified, using a
+// link again after that is not an error
+func (s *Service) VerifyEmail(ctx context.Context, token string) error {
+	claims, err := s.auth.ValidateTokenFor(ctx, token, auth.EmailVerificationAudience)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+	uid, err := claims.UserId()
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+	u, err := s.UserRepo.GetUserById(ctx, uid)
+	if err != nil {
+		return ErrInvalidVerificationToken
+	}
+	if u.Verified {
+		return nil
+	}
+	return s.UserRepo.MarkUserVerified(ctx, uid)
+}
+
+// ResendVerification sends a new verification link to an unverified account
+func (s *Service) ResendVerification(ctx context.Context, email string) (string, error) {
+	ok, err := s.rdb.StartVerificationCooldown(ctx, email, verificationCooldown)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", ErrVerificationCooldown
+	}
+
+	u, err := s.UserRepo.CheckEmail(ctx, email)
+	if err != nil || u.Verified {
+		log.Info().Str("Email", email).Msg("verification resend for unknown or verified email")
+		return VerificationSentMessage, nil
+	}
+	err = s.sendVerificationEmail(ctx, u)
+	if err != nil {
+		log.Error().Err(err).Str("Email", email).Msg("sending verification mail")
+		return "", errors.New("error sending email")
+	}
+	return VerificationSentMessage, nil
+}
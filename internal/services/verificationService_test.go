@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"job-portal-api/internal/auth"
+	"job-portal-api/internal/caching"
+	"job-portal-api/internal/mailer"
+	"job-portal-api/internal/models"
+	"job-portal-api/internal/repository"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func newTestAuth(t *testing.T) auth.Authentication {
+	t.Helper()
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating key %v", err)
+	}
+	a, err := auth.NewAuth([]auth.SigningKey{{ID: "test", PrivateKey: privateKey, PublicKey: &privateKey.PublicKey}}, "test", nil)
+	if err != nil {
+		t.Fatalf("NewAuth() error = %v", err)
+	}
+	return a
+}
+
+// verificationLink reads the token out of the single mail in the outbox
+func verificationLink(t *testing.T, dir string) string {
+	t.Helper()
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("outbox files = %v, want 1", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	m := regexp.MustCompile(`https://jobs\.example\.com/verify-email\?token=(\S+)`).FindSubmatch(data)
+	if m == nil {
+		t.Fatalf("verification mail = %q", data)
+	}
+	tkn, err := url.QueryUnescape(string(m[1]))
+	if err != nil {
+		t.Fatalf("unescaping token %v", err)
+	}
+	return tkn
+}
+
+func TestService_VerifyEmail(t *testing.T) {
+	a := newTestAuth(t)
+	dir := t.TempDir()
+	outbox, _ := mailer.NewOutbox(dir, "no-reply@jobportal.local")
+	mc := gomock.NewController(t)
+	MockUserRepo := repository.NewMockUserRepo(mc)
+	s, _ := NewService(MockUserRepo, a, &caching.Redis{}, outbox, WithAppURL("https://jobs.example.com/"))
+
+	MockUserRepo.EXPECT().EmailExists(gomock.Any(), "niki@gmail.com").Return(false, nil)
+	MockUserRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(models.User{Name: "Niki", Email: "niki@gmail.com"}, nil)
+	_, err := s.Signup(context.Background(), models.NewUser{Name: "Niki", Email: "niki@gmail.com", Password: "1234"})
+	if err != nil {
+		t.Fatalf("Service.Signup() error = %v", err)
+	}
+	link := verificationLink(t, dir)
+
+	// The link must not work as an access token
+	if _, err := a.ValidateToken(context.Background(), link); err == nil {
+		t.Errorf("verification token accepted as an access token")
+	}
+
+	access, _ := a.GenerateToken(newClaims(models.User{}))
+	tests := []struct {
+		name       string
+		token      string
+		setupMocks func(mr *repository.MockUserRepo)
+		wantErr    error
+	}{
+		{name: "garbage token",
+			token:      "abc",
+			setupMocks: func(mr *repository.MockUserRepo) {},
+			wantErr:    ErrInvalidVerificationToken,
+		},
+		{name: "access token is not a verification link",
+			token:      access,
+			setupMocks: func(mr *repository.MockUserRepo) {},
+			wantErr:    ErrInvalidVerificationToken,
+		},
+		{name: "account verified",
+			token: link,
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetUserById(gomock.Any(), uint(0)).Return(models.User{}, nil)
+				mr.EXPECT().MarkUserVerified(gomock.Any(), uint(0)).Return(nil)
+			},
+		},
+		{name: "link used again",
+			token: link,
+			setupMocks: func(mr *repository.MockUserRepo) {
+				mr.EXPECT().GetUserById(gomock.Any(), uint(0)).Return(models.User{Verified: true}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMocks(MockUserRepo)
+			err := s.VerifyEmail(context.Background(), tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Service.VerifyEmail() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestService_ResendVerification(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupMocks func(mr *repository.MockUserRepo, mc *caching.MockCache)
+		want       string
+		wantErr    error
+		wantMails  int
+	}{
+		{name: "resend within the cooldown",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().StartVerificationCooldown(gomock.Any(), "niki@gmail.com", verificationCooldown).Return(false, nil)
+			},
+			wantErr: ErrVerificationCooldown,
+		},
+		{name: "unknown email",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().StartVerificationCooldown(gomock.Any(), "niki@gmail.com", verificationCooldown).Return(true, nil)
+				mr.EXPECT().CheckEmail(gomock.Any(), "niki@gmail.com").Return(models.User{}, errors.New("email not found"))
+			},
+			want: VerificationSentMessage,
+		},
+		{name: "already verified",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().StartVerificationCooldown(gomock.Any(), "niki@gmail.com", verificationCooldown).Return(true, nil)
+				mr.EXPECT().CheckEmail(gomock.Any(), "niki@gmail.com").Return(models.User{Email: "niki@gmail.com", Verified: true}, nil)
+			},
+			want: VerificationSentMessage,
+		},
+		{name: "link sent again",
+			setupMocks: func(mr *repository.MockUserRepo, mc *caching.MockCache) {
+				mc.EXPECT().StartVerificationCooldown(gomock.Any(), "niki@gmail.com", verificationCooldown).Return(true, nil)
+				mr.EXPECT().CheckEmail(gomock.Any(), "niki@gmail.com").Return(models.User{Email: "niki@gmail.com"}, nil)
+			},
+			want:      VerificationSentMessage,
+			wantMails: 1,
+		},
+	}
+	a := newTestAuth(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			outbox, _ := mailer.NewOutbox(dir, "no-reply@jobportal.local")
+			mc := gomock.NewController(t)
+			MockUserRepo := repository.NewMockUserRepo(mc)
+			MockCache := caching.NewMockCache(mc)
+			tt.setupMocks(MockUserRepo, MockCache)
+			s, _ := NewService(MockUserRepo, a, MockCache, outbox, WithAppURL("https://jobs.example.com"))
+			got, err := s.ResendVerification(context.Background(), "niki@gmail.com")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Service.ResendVerification() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Service.ResendVerification() = %q, want %q", got, tt.want)
+			}
+			files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+			if len(files) != tt.wantMails {
+				t.Errorf("outbox files = %v, want %d", files, tt.wantMails)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	dir := t.TempDir()
+	l, err := NewLocal(dir)
+	if err != nil {
+		t.Fatalf("NewLocal() error = %v", err)
+	}
+	ctx := context.Background()
+
+	err = l.Put(ctx, "resumes/1", strings.NewReader("%PDF-1.7"), 8, "application/pdf")
+	if err != nil {
+		t.Fatalf("Local.Put() error = %v", err)
+	}
+	rc, err := l.Get(ctx, "resumes/1")
+	if err != nil {
+		t.Fatalf("Local.Get() error = %v", err)
+	}
+	body, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(body) != "%PDF-1.7" {
+		t.Errorf("Local.Get() = %q", body)
+	}
+
+	// A body shorter than announced leaves nothing behind
+	err = l.Put(ctx, "resumes/2", strings.NewReader("short"), 100, "")
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Local.Put() of a short body error = %v", err)
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, "resumes"))
+	if len(entries) != 1 {
+		t.Errorf("files left after a failed put: %v", entries)
+	}
+
+	err = l.Delete(ctx, "resumes/1")
+	if err != nil {
+		t.Fatalf("Local.Delete() error = %v", err)
+	}
+	_, err = l.Get(ctx, "resumes/1")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Local.Get() after delete error = %v, want %v", err, ErrNotFound)
+	}
+	if l.Delete(ctx, "resumes/1") != nil {
+		t.Errorf("Local.Delete() of a missing blob failed")
+	}
+
+	for _, key := range []string{"", "/etc/passwd", "../x", "resumes/../../x", "resumes//x"} {
+		if l.Put(ctx, key, strings.NewReader("x"), 1, "") == nil {
+			t.Errorf("Local.Put() accepted key %q", key)
+		}
+	}
+}
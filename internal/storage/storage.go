@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrNotFound is returned for a key that holds no blob
+var ErrNotFound = errors.New("blob not found")
+
+//go:generate mockgen -source=storage.go -destination=storage_mock.go -package=storage
+type BlobStore interface {
+	// Put stores size bytes read from r under key, replacing any blob there
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the blob under key, ErrNotFound when there is none
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob under key, a missing blob is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// Config for building a blob store, filled from config.StorageConfig
+type Config struct {
+	Driver      string
+	LocalDir    string
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+}
+
+// NewBlobStore picks the store named by the driver, local or s3
+func NewBlobStore(cfg Config) (BlobStore, error) {
+	switch cfg.Driver {
+	case "local", "":
+		return NewLocal(cfg.LocalDir)
+	case "s3":
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" {
+			return nil, errors.New("s3 endpoint and bucket cannot be empty")
+		}
+		return NewS3(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKey, cfg.S3SecretKey), nil
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+// validKey refuses keys that could point outside of the store, keys are
+// slash separated names such as resumes/<id>
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid blob key %q", key)
+		}
+	}
+	return nil
+}